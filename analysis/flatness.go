@@ -0,0 +1,91 @@
+//
+// SPDX-FileCopyrightText: Copyright 2026 Frank Schwab
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileType: SOURCE
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+//
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Author: Frank Schwab
+//
+// Version: 1.0.0
+//
+// Change history:
+//    2026-10-18: V1.0.0: Created.
+//
+
+package analysis
+
+import "math"
+
+// ******** Public types ********
+
+// Flatness describes how evenly the substitution characters occur in an encrypted text.
+// All frequencies are fractions of the number of substitution characters.
+type Flatness struct {
+	// Ideal is the frequency of each substitution character in a perfectly flat distribution.
+	Ideal float64
+
+	// Min is the lowest frequency of a substitution character.
+	Min float64
+
+	// Max is the highest frequency of a substitution character.
+	Max float64
+
+	// Deviation is the root mean square deviation of the frequencies from the ideal frequency.
+	Deviation float64
+
+	// IdealCount is the number of substitution characters that occur with the ideal frequency.
+	IdealCount int
+
+	// Count is the number of substitution characters.
+	Count int
+}
+
+// ******** Private constants ********
+
+// idealTolerance is the relative tolerance for a frequency to be counted as ideal.
+const idealTolerance = 1e-9
+
+// ******** Public functions ********
+
+// CalculateFlatness calculates the flatness of the frequencies of the characters of an alphabet.
+// Characters that are missing in frequencies have a frequency of 0.
+func CalculateFlatness(frequencies map[byte]float64, alphabet string) Flatness {
+	count := len(alphabet)
+	ideal := 1 / float64(count)
+	result := Flatness{
+		Ideal: ideal,
+		Min:   math.Inf(1),
+		Count: count,
+	}
+
+	sumSquares := 0.0
+	for i := range count {
+		f := frequencies[alphabet[i]]
+		result.Min = min(result.Min, f)
+		result.Max = max(result.Max, f)
+
+		d := f - ideal
+		sumSquares += d * d
+		if math.Abs(d) <= idealTolerance*ideal {
+			result.IdealCount++
+		}
+	}
+
+	result.Deviation = math.Sqrt(sumSquares / float64(count))
+
+	return result
+}
@@ -0,0 +1,315 @@
+//
+// SPDX-FileCopyrightText: Copyright 2026 Frank Schwab
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileType: SOURCE
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+//
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Author: Frank Schwab
+//
+// Version: 1.0.0
+//
+// Change history:
+//    2026-10-18: V1.0.0: Created.
+//
+
+package analysis
+
+import (
+	"cmp"
+	"math"
+	"slices"
+)
+
+// ******** Public types ********
+
+// Group is a candidate group of characters that may be homophones of the same letter.
+type Group struct {
+	// Characters contains the characters of the group in the order of decreasing counts.
+	Characters []byte
+
+	// Count is the total number of occurrences of the characters of the group.
+	Count uint
+
+	// Confidence is the mean silhouette of the characters of the group, clipped to the range 0 to 1.
+	// It is near 1, if the contacts of the characters are much more similar to each other than to
+	// the contacts of the characters of other groups. It is 0 for a group with only one character.
+	Confidence float64
+}
+
+// GroupScore compares candidate groups with the real homophones of a key.
+type GroupScore struct {
+	// Precision is the share of the pairs of characters in the same group that are homophones of the same letter.
+	Precision float64
+
+	// Recall is the share of the pairs of homophones of the same letter that are in the same group.
+	Recall float64
+}
+
+// ******** Public type functions ********
+
+// Groups clusters the characters that occur in the text into count groups of characters with similar contacts.
+// The contacts of a character are the frequencies of its left and its right neighbors.
+// Homophones of the same letter have the same neighbors in the clear text, so their contacts tend to be similar.
+// The groups are built by average-linkage clustering with the cosine similarity of the contacts.
+// They are returned in the order of decreasing counts.
+// If fewer than count characters occur, each of them is a group of its own.
+func (s *Statistics) Groups(count int) []Group {
+	if count < 1 {
+		return nil
+	}
+
+	characters := s.occurringCharacters()
+	similarity := s.contactSimilarities(characters)
+
+	// Start with one cluster per character and merge the most similar clusters until count clusters remain.
+	clusters := make([][]int, len(characters))
+	for i := range clusters {
+		clusters[i] = []int{i}
+	}
+
+	for len(clusters) > count {
+		bestA, bestB := 0, 1
+		best := math.Inf(-1)
+		for a := range clusters {
+			for b := a + 1; b < len(clusters); b++ {
+				linkage := averageSimilarity(similarity, clusters[a], clusters[b])
+				if linkage > best {
+					best = linkage
+					bestA, bestB = a, b
+				}
+			}
+		}
+
+		clusters[bestA] = append(clusters[bestA], clusters[bestB]...)
+		clusters = slices.Delete(clusters, bestB, bestB+1)
+	}
+
+	result := make([]Group, len(clusters))
+	for i, cluster := range clusters {
+		result[i] = s.makeGroup(characters, cluster, clusters, similarity)
+	}
+
+	slices.SortStableFunc(result, func(a, b Group) int {
+		if a.Count != b.Count {
+			return cmp.Compare(b.Count, a.Count)
+		}
+
+		return cmp.Compare(a.Characters[0], b.Characters[0])
+	})
+
+	return result
+}
+
+// ******** Public functions ********
+
+// ScoreGroups compares candidate groups with the real homophones.
+// letters contains the letter of each character, or 0 if the character is not known.
+func ScoreGroups(groups []Group, letters *[256]byte) GroupScore {
+	groupedPairs := 0
+	correctPairs := 0
+	letterCounts := make(map[byte]int)
+	for _, group := range groups {
+		for i, a := range group.Characters {
+			if letters[a] != 0 {
+				letterCounts[letters[a]]++
+			}
+
+			for _, b := range group.Characters[i+1:] {
+				groupedPairs++
+				if letters[a] != 0 && letters[a] == letters[b] {
+					correctPairs++
+				}
+			}
+		}
+	}
+
+	homophonePairs := 0
+	for _, n := range letterCounts {
+		homophonePairs += n * (n - 1) / 2
+	}
+
+	return GroupScore{
+		Precision: share(correctPairs, groupedPairs),
+		Recall:    share(correctPairs, homophonePairs),
+	}
+}
+
+// MajorityLetter returns the letter that most characters of the group stand for and the number of these characters.
+// letters contains the letter of each character, or 0 if the character is not known.
+// It returns 0, if no character of the group is known.
+func (g Group) MajorityLetter(letters *[256]byte) (byte, int) {
+	var counts [256]int
+	result := byte(0)
+	for _, b := range g.Characters {
+		letter := letters[b]
+		if letter == 0 {
+			continue
+		}
+
+		counts[letter]++
+		if counts[letter] > counts[result] || (counts[letter] == counts[result] && letter < result) {
+			result = letter
+		}
+	}
+
+	return result, counts[result]
+}
+
+// ******** Private type functions ********
+
+// occurringCharacters returns the characters of the alphabet that occur in the text.
+func (s *Statistics) occurringCharacters() []byte {
+	result := make([]byte, 0, len(s.alphabet))
+	for i := range len(s.alphabet) {
+		b := s.alphabet[i]
+		if s.counts[b] != 0 {
+			result = append(result, b)
+		}
+	}
+
+	return result
+}
+
+// contactSimilarities returns the cosine similarities of the contacts of each pair of characters.
+func (s *Statistics) contactSimilarities(characters []byte) [][]float64 {
+	index := make(map[byte]int, len(characters))
+	for i, b := range characters {
+		index[b] = i
+	}
+
+	// The first half of a contact vector counts the left neighbors, the second half the right neighbors.
+	size := len(characters)
+	contacts := make([][]float64, size)
+	for i := range contacts {
+		contacts[i] = make([]float64, 2*size)
+	}
+
+	for bigram, n := range s.bigramCounts {
+		left, right := index[bigram[0]], index[bigram[1]]
+		contacts[right][left] += float64(n)
+		contacts[left][size+right] += float64(n)
+	}
+
+	result := make([][]float64, size)
+	for i := range result {
+		result[i] = make([]float64, size)
+		for j := range i {
+			result[i][j] = cosineSimilarity(contacts[i], contacts[j])
+			result[j][i] = result[i][j]
+		}
+
+		result[i][i] = 1
+	}
+
+	return result
+}
+
+// makeGroup builds the group of a cluster of character indices.
+func (s *Statistics) makeGroup(characters []byte, cluster []int, clusters [][]int, similarity [][]float64) Group {
+	result := Group{Characters: make([]byte, len(cluster))}
+	for i, c := range cluster {
+		result.Characters[i] = characters[c]
+		result.Count += s.counts[characters[c]]
+	}
+
+	slices.SortStableFunc(result.Characters, func(a, b byte) int {
+		if s.counts[a] != s.counts[b] {
+			return cmp.Compare(s.counts[b], s.counts[a])
+		}
+
+		return cmp.Compare(a, b)
+	})
+
+	if len(cluster) == 1 || len(clusters) == 1 {
+		return result
+	}
+
+	sum := 0.0
+	for _, c := range cluster {
+		sum += silhouette(similarity, c, cluster, clusters)
+	}
+
+	result.Confidence = max(sum/float64(len(cluster)), 0)
+
+	return result
+}
+
+// ******** Private functions ********
+
+// silhouette returns the silhouette of the character with index c in its cluster.
+// It uses 1 minus the similarity as the distance of two characters.
+func silhouette(similarity [][]float64, c int, cluster []int, clusters [][]int) float64 {
+	inner := 0.0
+	for _, other := range cluster {
+		if other != c {
+			inner += 1 - similarity[c][other]
+		}
+	}
+	inner /= float64(len(cluster) - 1)
+
+	outer := math.Inf(1)
+	for _, otherCluster := range clusters {
+		if slices.Contains(otherCluster, c) {
+			continue
+		}
+
+		outer = min(outer, 1-averageSimilarity(similarity, []int{c}, otherCluster))
+	}
+
+	if max(inner, outer) == 0 {
+		return 0
+	}
+
+	return (outer - inner) / max(inner, outer)
+}
+
+// averageSimilarity returns the average similarity of the characters of two clusters.
+func averageSimilarity(similarity [][]float64, a []int, b []int) float64 {
+	sum := 0.0
+	for _, i := range a {
+		for _, j := range b {
+			sum += similarity[i][j]
+		}
+	}
+
+	return sum / float64(len(a)*len(b))
+}
+
+// cosineSimilarity returns the cosine of the angle between two vectors, or 0 if one of them is zero.
+func cosineSimilarity(a []float64, b []float64) float64 {
+	dot, normA, normB := 0.0, 0.0, 0.0
+	for i := range a {
+		dot += a[i] * b[i]
+		normA += a[i] * a[i]
+		normB += b[i] * b[i]
+	}
+
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+
+	return dot / math.Sqrt(normA*normB)
+}
+
+// share returns part divided by total, or 0 if total is 0.
+func share(part int, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+
+	return float64(part) / float64(total)
+}
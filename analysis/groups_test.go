@@ -0,0 +1,117 @@
+//
+// SPDX-FileCopyrightText: Copyright 2026 Frank Schwab
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileType: SOURCE
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+//
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Author: Frank Schwab
+//
+// Version: 1.0.0
+//
+// Change history:
+//    2026-10-18: V1.0.0: Created.
+//
+
+package analysis
+
+import (
+	"strings"
+	"testing"
+)
+
+// ******** Test functions ********
+
+func TestGroupsFindsHomophones(t *testing.T) {
+	// 'E' is substituted alternately by 'x' and 'y', 'T' alternately by 'p' and 'q'.
+	// All other letters are kept.
+	clearText := strings.Repeat(`THEQUICKBROWNFOXJUMPSOVERTHELAZYDOGANDTHENTHECATSLEEPS`, 20)
+	substitutions := map[byte]string{'E': `xy`, 'T': `pq`}
+	uses := make(map[byte]int)
+
+	s := NewStatistics(SubstitutionAlphabet)
+	letters := [256]byte{}
+	for _, b := range []byte(clearText) {
+		symbol := b
+		if list, ok := substitutions[b]; ok {
+			symbol = list[uses[b]%len(list)]
+			uses[b]++
+		}
+
+		letters[symbol] = b
+		s.Add(symbol)
+	}
+
+	letterCount := s.DistinctCharacters() - len(substitutions)
+	groups := s.Groups(letterCount)
+	if len(groups) != letterCount {
+		t.Fatalf(`Got %d groups instead of %d`, len(groups), letterCount)
+	}
+
+	for _, group := range groups {
+		letter, count := group.MajorityLetter(&letters)
+		if count != len(group.Characters) {
+			t.Fatalf(`Group '%s' mixes homophones of different letters`, group.Characters)
+		}
+
+		if (letter == 'E' || letter == 'T') != (len(group.Characters) == 2) {
+			t.Fatalf(`Group '%s' of '%c' has the wrong size`, group.Characters, letter)
+		}
+
+		if len(group.Characters) == 2 && group.Confidence <= 0 {
+			t.Fatalf(`Group '%s' has no confidence`, group.Characters)
+		}
+	}
+
+	score := ScoreGroups(groups, &letters)
+	expectNear(t, `precision`, score.Precision, 1)
+	expectNear(t, `recall`, score.Recall, 1)
+}
+
+func TestGroupsOrder(t *testing.T) {
+	s := NewStatistics(SubstitutionAlphabet)
+	_, _ = s.Write([]byte(`abcabcaab`))
+
+	groups := s.Groups(10)
+	if len(groups) != 3 {
+		t.Fatalf(`Got %d groups instead of 3`, len(groups))
+	}
+
+	for i, expected := range []string{`a`, `b`, `c`} {
+		if string(groups[i].Characters) != expected || groups[i].Confidence != 0 {
+			t.Fatalf(`Group %d is '%s' with confidence %f instead of '%s' with 0`, i, groups[i].Characters, groups[i].Confidence, expected)
+		}
+	}
+
+	if s.Groups(0) != nil {
+		t.Fatal(`Got groups for a count of 0`)
+	}
+}
+
+func TestScoreGroups(t *testing.T) {
+	var letters [256]byte
+	letters['a'], letters['b'], letters['c'], letters['d'] = 'E', 'E', 'E', 'T'
+
+	// Pairs in a group: ab (correct), cd (wrong). Pairs of homophones: ab, ac, bc.
+	score := ScoreGroups([]Group{{Characters: []byte(`ab`)}, {Characters: []byte(`cd`)}}, &letters)
+	expectNear(t, `precision`, score.Precision, 0.5)
+	expectNear(t, `recall`, score.Recall, 1.0/3)
+
+	letter, count := Group{Characters: []byte(`dcz`)}.MajorityLetter(&letters)
+	if letter != 'E' || count != 1 {
+		t.Fatalf(`Majority letter is '%c' with %d characters instead of 'E' with 1`, letter, count)
+	}
+}
@@ -0,0 +1,248 @@
+//
+// SPDX-FileCopyrightText: Copyright 2026 Frank Schwab
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileType: SOURCE
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+//
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Author: Frank Schwab
+//
+// Version: 1.0.0
+//
+// Change history:
+//    2026-10-18: V1.0.0: Created.
+//
+
+// Package analysis implements the statistical analysis of encrypted texts.
+package analysis
+
+import (
+	"bufio"
+	"cmp"
+	"errors"
+	"io"
+	"slices"
+)
+
+// ******** Public types ********
+
+// Bigram is a pair of consecutive characters.
+type Bigram [2]byte
+
+// BigramCount is a bigram and the number of its occurrences.
+type BigramCount struct {
+	Bigram Bigram
+	Count  uint
+}
+
+// Statistics contains the frequencies of the characters and bigrams of an alphabet in a text.
+// Characters that are not in the alphabet are skipped, so they do not separate bigrams.
+type Statistics struct {
+	alphabet     string
+	isMember     [256]bool
+	counts       [256]uint
+	total        uint
+	bigramCounts map[Bigram]uint
+	bigramTotal  uint
+	previous     int
+}
+
+// ******** Public constants ********
+
+// SubstitutionAlphabet is the alphabet of the substitution characters.
+const SubstitutionAlphabet = `ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz`
+
+// ******** Public creation functions ********
+
+// NewStatistics creates empty statistics for the characters of alphabet.
+func NewStatistics(alphabet string) *Statistics {
+	result := &Statistics{
+		alphabet:     alphabet,
+		bigramCounts: make(map[Bigram]uint),
+		previous:     -1,
+	}
+
+	for i := range len(alphabet) {
+		result.isMember[alphabet[i]] = true
+	}
+
+	return result
+}
+
+// FromReader creates the statistics of the substitution characters in the text that is read from r.
+func FromReader(r io.Reader) (*Statistics, error) {
+	result := NewStatistics(SubstitutionAlphabet)
+
+	reader := bufio.NewReader(r)
+	for {
+		b, err := reader.ReadByte()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return result, nil
+			}
+
+			return nil, err
+		}
+
+		result.Add(b)
+	}
+}
+
+// ******** Public type functions ********
+
+// Add adds a character of the text.
+func (s *Statistics) Add(b byte) {
+	if !s.isMember[b] {
+		return
+	}
+
+	s.counts[b]++
+	s.total++
+
+	if s.previous >= 0 {
+		s.bigramCounts[Bigram{byte(s.previous), b}]++
+		s.bigramTotal++
+	}
+
+	s.previous = int(b)
+}
+
+// Write adds all characters of p. It implements the [io.Writer] interface and never returns an error.
+func (s *Statistics) Write(p []byte) (int, error) {
+	for _, b := range p {
+		s.Add(b)
+	}
+
+	return len(p), nil
+}
+
+// Alphabet returns the alphabet of the statistics.
+func (s *Statistics) Alphabet() string {
+	return s.alphabet
+}
+
+// Count returns how often the character b occurs.
+func (s *Statistics) Count(b byte) uint {
+	return s.counts[b]
+}
+
+// Total returns the number of characters of the alphabet in the text.
+func (s *Statistics) Total() uint {
+	return s.total
+}
+
+// BigramTotal returns the number of bigrams in the text.
+func (s *Statistics) BigramTotal() uint {
+	return s.bigramTotal
+}
+
+// DistinctCharacters returns the number of different characters in the text.
+func (s *Statistics) DistinctCharacters() int {
+	result := 0
+	for i := range len(s.alphabet) {
+		if s.counts[s.alphabet[i]] != 0 {
+			result++
+		}
+	}
+
+	return result
+}
+
+// DistinctBigrams returns the number of different bigrams in the text.
+func (s *Statistics) DistinctBigrams() int {
+	return len(s.bigramCounts)
+}
+
+// Frequencies returns the frequency of each character of the alphabet as a fraction of the total.
+func (s *Statistics) Frequencies() map[byte]float64 {
+	result := make(map[byte]float64, len(s.alphabet))
+	for i := range len(s.alphabet) {
+		b := s.alphabet[i]
+		if s.total != 0 {
+			result[b] = float64(s.counts[b]) / float64(s.total)
+		} else {
+			result[b] = 0
+		}
+	}
+
+	return result
+}
+
+// Flatness returns the flatness of the character frequencies.
+func (s *Statistics) Flatness() Flatness {
+	return CalculateFlatness(s.Frequencies(), s.alphabet)
+}
+
+// IndexOfCoincidence returns the probability that two randomly chosen characters of the text are equal,
+// divided by the probability for a random text. It is 1 for a random text and larger for a text with
+// uneven frequencies. It is 0, if the text has less than 2 characters.
+func (s *Statistics) IndexOfCoincidence() float64 {
+	coincidences := uint(0)
+	for i := range len(s.alphabet) {
+		n := s.counts[s.alphabet[i]]
+		if n > 1 {
+			coincidences += n * (n - 1)
+		}
+	}
+
+	return normalizedCoincidence(coincidences, s.total, len(s.alphabet))
+}
+
+// BigramIndexOfCoincidence returns the index of coincidence of the bigrams of the text.
+// It is 1 for a random text and larger for a text with uneven bigram frequencies.
+func (s *Statistics) BigramIndexOfCoincidence() float64 {
+	coincidences := uint(0)
+	for _, n := range s.bigramCounts {
+		coincidences += n * (n - 1)
+	}
+
+	return normalizedCoincidence(coincidences, s.bigramTotal, len(s.alphabet)*len(s.alphabet))
+}
+
+// BigramCount returns how often the bigram occurs.
+func (s *Statistics) BigramCount(bigram Bigram) uint {
+	return s.bigramCounts[bigram]
+}
+
+// TopBigrams returns the n most frequent bigrams in the order of decreasing counts.
+// Bigrams with the same count are sorted alphabetically.
+func (s *Statistics) TopBigrams(n int) []BigramCount {
+	result := make([]BigramCount, 0, len(s.bigramCounts))
+	for bigram, count := range s.bigramCounts {
+		result = append(result, BigramCount{Bigram: bigram, Count: count})
+	}
+
+	slices.SortFunc(result, func(a, b BigramCount) int {
+		if a.Count != b.Count {
+			return cmp.Compare(b.Count, a.Count)
+		}
+
+		return cmp.Compare(string(a.Bigram[:]), string(b.Bigram[:]))
+	})
+
+	return result[:min(n, len(result))]
+}
+
+// ******** Private functions ********
+
+// normalizedCoincidence returns the coincidence rate divided by the rate of a random text over an alphabet of the given size.
+func normalizedCoincidence(coincidences uint, total uint, alphabetSize int) float64 {
+	if total < 2 {
+		return 0
+	}
+
+	return float64(coincidences) / (float64(total) * float64(total-1)) * float64(alphabetSize)
+}
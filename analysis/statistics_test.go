@@ -0,0 +1,132 @@
+//
+// SPDX-FileCopyrightText: Copyright 2026 Frank Schwab
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileType: SOURCE
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+//
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Author: Frank Schwab
+//
+// Version: 1.0.0
+//
+// Change history:
+//    2026-10-18: V1.0.0: Created.
+//
+
+package analysis
+
+import (
+	"math"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// ******** Private constants ********
+
+const tolerance = 1e-12
+
+// ******** Test functions ********
+
+func TestCounts(t *testing.T) {
+	s, err := FromReader(strings.NewReader(`AAb, b-A!zz`))
+	if err != nil {
+		t.Fatalf(`Error reading: %v`, err)
+	}
+
+	if s.Total() != 7 || s.Count('A') != 3 || s.Count('b') != 2 || s.Count('z') != 2 || s.Count(',') != 0 {
+		t.Fatalf(`Wrong counts: total %d, A %d, b %d, z %d`, s.Total(), s.Count('A'), s.Count('b'), s.Count('z'))
+	}
+
+	if s.DistinctCharacters() != 3 {
+		t.Fatalf(`Got %d different characters instead of 3`, s.DistinctCharacters())
+	}
+
+	// Characters that are not in the alphabet do not separate bigrams.
+	expected := map[Bigram]uint{{'A', 'A'}: 1, {'A', 'b'}: 1, {'b', 'b'}: 1, {'b', 'A'}: 1, {'A', 'z'}: 1, {'z', 'z'}: 1}
+	if s.BigramTotal() != 6 || s.DistinctBigrams() != 6 {
+		t.Fatalf(`Got %d bigrams with %d different ones instead of 6 and 6`, s.BigramTotal(), s.DistinctBigrams())
+	}
+	for bigram, count := range expected {
+		if s.BigramCount(bigram) != count {
+			t.Fatalf(`Bigram %s occurs %d times instead of %d`, bigram[:], s.BigramCount(bigram), count)
+		}
+	}
+}
+
+func TestIndexOfCoincidence(t *testing.T) {
+	s := NewStatistics(`AB`)
+	_, _ = s.Write([]byte(`AABB`))
+
+	// Coincidences: 2*1 + 2*1 = 4 of 4*3 = 12 pairs, multiplied by the alphabet size 2.
+	expectNear(t, `index of coincidence`, s.IndexOfCoincidence(), 4.0/12*2)
+
+	// Bigrams AA, AB, BB: no coincidences.
+	expectNear(t, `bigram index of coincidence`, s.BigramIndexOfCoincidence(), 0)
+
+	if NewStatistics(`AB`).IndexOfCoincidence() != 0 {
+		t.Fatal(`Index of coincidence of an empty text is not 0`)
+	}
+}
+
+func TestTopBigrams(t *testing.T) {
+	s := NewStatistics(SubstitutionAlphabet)
+	_, _ = s.Write([]byte(`ababab cd cd xy`))
+
+	top := s.TopBigrams(3)
+	expected := []BigramCount{{Bigram{'a', 'b'}, 3}, {Bigram{'b', 'a'}, 2}, {Bigram{'c', 'd'}, 2}}
+	if !slices.Equal(top, expected) {
+		t.Fatalf(`Got top bigrams %v instead of %v`, top, expected)
+	}
+
+	if len(s.TopBigrams(100)) != s.DistinctBigrams() {
+		t.Fatalf(`Got %d top bigrams instead of all %d`, len(s.TopBigrams(100)), s.DistinctBigrams())
+	}
+}
+
+func TestFlatness(t *testing.T) {
+	s := NewStatistics(`ABCD`)
+	_, _ = s.Write([]byte(`AABCDABCD`))
+
+	flatness := s.Flatness()
+	expectNear(t, `ideal`, flatness.Ideal, 0.25)
+	expectNear(t, `min`, flatness.Min, 2.0/9)
+	expectNear(t, `max`, flatness.Max, 3.0/9)
+
+	d := 3.0/9 - 0.25
+	e := 2.0/9 - 0.25
+	expectNear(t, `deviation`, flatness.Deviation, math.Sqrt((d*d+3*e*e)/4))
+
+	if flatness.IdealCount != 0 || flatness.Count != 4 {
+		t.Fatalf(`Got %d of %d ideal characters instead of 0 of 4`, flatness.IdealCount, flatness.Count)
+	}
+
+	flat := CalculateFlatness(map[byte]float64{'A': 0.5, 'B': 0.5}, `AB`)
+	if flat.IdealCount != 2 || flat.Deviation != 0 {
+		t.Fatalf(`Flat distribution has %d ideal characters and a deviation of %g`, flat.IdealCount, flat.Deviation)
+	}
+}
+
+// ******** Private functions ********
+
+// expectNear checks that a value is near the expected value.
+func expectNear(t *testing.T, name string, got float64, expected float64) {
+	t.Helper()
+
+	if math.Abs(got-expected) > tolerance {
+		t.Fatalf(`Got %s %g instead of %g`, name, got, expected)
+	}
+}
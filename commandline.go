@@ -20,11 +20,27 @@
 //
 // Author: Frank Schwab
 //
-// Version: 1.1.0
+// Version: 1.17.0
 //
 // Change history:
 //    2025-01-04: V1.0.0: Created.
 //    2025-01-05: V1.1.0: Correct handling of additional arguments that are not flags.
+//    2026-10-18: V1.2.0: Add "force" flag and check for identical files.
+//    2026-10-18: V1.3.0: Add "hash" flag.
+//    2026-10-18: V1.4.0: Parse the arguments passed by the caller and do not exit on errors.
+//    2026-10-18: V1.5.0: Add "method" flag.
+//    2026-10-18: V1.6.0: Add "min" and "max" flags.
+//    2026-10-18: V1.7.0: Add "select" flag.
+//    2026-10-18: V1.8.0: Add "weighted" flag.
+//    2026-10-18: V1.9.0: Add "bigram" flag and "analyze" command.
+//    2026-10-18: V1.10.0: Add "serve" command.
+//    2026-10-18: V1.11.0: Add "repl" command.
+//    2026-10-18: V1.12.0: Add "exercise" command.
+//    2026-10-18: V1.13.0: Add "grade" command.
+//    2026-10-18: V1.14.0: Add "crib" command.
+//    2026-10-18: V1.15.0: Add homophone groups to "analyze" command.
+//    2026-10-18: V1.16.0: Add "lm build" command.
+//    2026-10-18: V1.17.0: Added mode, shift and plain flags.
 //
 
 package main
@@ -32,8 +48,21 @@ package main
 import (
 	"flag"
 	"fmt"
+	"homophone/analysis"
+	"homophone/crib"
+	"homophone/distributor"
+	"homophone/exercise"
 	"homophone/filehelper"
+	"homophone/grade"
+	"homophone/homosubst"
+	"homophone/integritycheckedfile"
+	"homophone/randomlist"
+	"homophone/server"
+	"io"
+	"math"
 	"os"
+	"slices"
+	"strings"
 )
 
 // ******** Private variables ********
@@ -55,6 +84,108 @@ var substFileName string
 // keepOthers indicates that characters that are not in the range A-Z should be kept.
 var keepOthers bool
 
+// forceOverwrite indicates that existing output files may be overwritten.
+var forceOverwrite bool
+
+// protectOutput indicates that the encrypted file is written and read in the chunked integrity-checked format.
+var protectOutput bool
+
+// hashAlgorithmName is the name of the hash algorithm for the integrity check of the key file.
+var hashAlgorithmName string
+
+// hashAlgorithm is the hash algorithm for the integrity check of the key file.
+var hashAlgorithm integritycheckedfile.HashAlgorithm
+
+// methodName is the name of the method for distributing the substitution characters.
+var methodName string
+
+// apportioner is the method for distributing the substitution characters.
+var apportioner distributor.Apportioner
+
+// minLengthSpec is the specification of the minimum numbers of substitutions.
+var minLengthSpec string
+
+// maxLengthSpec is the specification of the maximum numbers of substitutions.
+var maxLengthSpec string
+
+// minLengths contains the minimum number of substitutions for each letter, or nil.
+var minLengths []uint
+
+// maxLengths contains the maximum number of substitutions for each letter, or nil.
+var maxLengths []uint
+
+// selectionName is the name of the strategy for selecting a substitution.
+var selectionName string
+
+// selection is the strategy for selecting a substitution.
+var selection randomlist.Strategy
+
+// weighted indicates that substitutions are selected with weights from the exact quotas.
+var weighted bool
+
+// contextClassCount is the number of predecessor classes for context-dependent substitutions.
+var contextClassCount int
+
+// modeName is the name of the mode of the substitution.
+var modeName string
+
+// cipherMode is the mode of the substitution.
+var cipherMode homosubst.Mode
+
+// caesarShift is the number of letters each letter is shifted in the Caesar mode.
+var caesarShift int
+
+// plainFileName is the name of the clear text file for which the analysis compares the modes.
+var plainFileName string
+
+// topCount is the number of most frequent bigrams that are shown by the analysis.
+var topCount int
+
+// groupCount is the number of groups of homophones that are shown by the analysis.
+var groupCount int
+
+// answerFileName is the name of the file for the answer key of an exercise.
+var answerFileName string
+
+// revealCount is the number of letters whose homophones are revealed in an exercise.
+var revealCount int
+
+// formatName is the name of the output format of an exercise.
+var formatName string
+
+// sheetFormat is the output format of an exercise.
+var sheetFormat exercise.Format
+
+// withFrequencies indicates that an exercise contains the frequencies of the ciphertext characters.
+var withFrequencies bool
+
+// cipherFileName is the name of the ciphertext file that is graded.
+var cipherFileName string
+
+// kindName is the name of the kind of answer that is graded.
+var kindName string
+
+// answerKind is the kind of answer that is graded.
+var answerKind grade.Kind
+
+// cribText is the known plaintext of a crib attack.
+var cribText string
+
+// maxAlignments is the maximum number of alignments of a crib that are shown.
+var maxAlignments int
+
+// cribPosition is the position of the alignment of a crib that is shown and exported, or 0 for all alignments.
+var cribPosition int
+
+// minNGramCount is the minimum number of occurrences of an n-gram in a language model.
+var minNGramCount uint64
+
+// listenAddress is the TCP address the server listens on.
+var listenAddress string
+
+// maxRequestSize is the maximum size of a request to the server in bytes.
+var maxRequestSize int64
+
 // Flag sets.
 
 // decryptCommand is the [flag.Flagset] for decryption.
@@ -63,6 +194,38 @@ var decryptCommand *flag.FlagSet
 // encryptCommand is the [flag.Flagset] for encryption.
 var encryptCommand *flag.FlagSet
 
+// exerciseCommand is the [flag.Flagset] for exercises.
+var exerciseCommand *flag.FlagSet
+
+// gradeCommand is the [flag.Flagset] for grading.
+var gradeCommand *flag.FlagSet
+
+// cribCommand is the [flag.Flagset] for crib attacks.
+var cribCommand *flag.FlagSet
+
+// lmBuildCommand is the [flag.Flagset] for building language models.
+var lmBuildCommand *flag.FlagSet
+
+// analyzeCommand is the [flag.Flagset] for the analysis.
+var analyzeCommand *flag.FlagSet
+
+// serveCommand is the [flag.Flagset] for the server.
+var serveCommand *flag.FlagSet
+
+// replCommand is the [flag.Flagset] for the interactive session.
+var replCommand *flag.FlagSet
+
+// Input and output streams.
+
+// inReader is the reader for the input of an interactive session.
+var inReader io.Reader = os.Stdin
+
+// outWriter is the writer for normal output.
+var outWriter io.Writer = os.Stdout
+
+// errWriter is the writer for error messages and usage information.
+var errWriter io.Writer = os.Stderr
+
 // Program information.
 
 // myName is the program name.
@@ -79,38 +242,213 @@ func init() {
 
 // defineCommandLineFlags defines the command line flags.
 func defineCommandLineFlags() {
-	encryptCommand = flag.NewFlagSet(`encrypt`, flag.ExitOnError)
+	encryptCommand = flag.NewFlagSet(`encrypt`, flag.ContinueOnError)
 	encryptCommand.StringVar(&inFileName, `in`, ``, "Clear text file `path`")
 	encryptCommand.StringVar(&outFileName, `out`, ``, "Encrypted file `path`")
 	encryptCommand.StringVar(&substFileName, `key`, ``, "Key file `path`")
 	encryptCommand.BoolVar(&keepOthers, `keep`, false, `Keep characters that are not in the range A-Z (default: do not keep)`)
-
-	decryptCommand = flag.NewFlagSet(`decrypt`, flag.ExitOnError)
+	encryptCommand.BoolVar(&forceOverwrite, `force`, false, `Overwrite existing output files (default: do not overwrite)`)
+	encryptCommand.StringVar(&hashAlgorithmName, `hash`, integritycheckedfile.HashSHA3_256.String(), "Hash `algorithm` for the integrity check of the key file")
+	encryptCommand.StringVar(&methodName, `method`, distributor.DefaultApportioner().Name(), "Apportionment `method` for distributing the substitution characters")
+	encryptCommand.StringVar(&minLengthSpec, `min`, ``, "Minimum number of substitutions per letter (`lengths`)")
+	encryptCommand.StringVar(&maxLengthSpec, `max`, ``, "Maximum number of substitutions per letter (`lengths`)")
+	encryptCommand.StringVar(&selectionName, `select`, randomlist.DefaultStrategy.String(), "`Strategy` for selecting one of the substitutions of a letter")
+	encryptCommand.BoolVar(&weighted, `weighted`, false, `Select substitutions with weights from the exact quotas (default: equal weights)`)
+	encryptCommand.IntVar(&contextClassCount, `bigram`, 0, "Experimental: Number of predecessor `classes` with separate substitutions (default: 0, i.e. no predecessor classes)")
+	encryptCommand.StringVar(&modeName, `mode`, homosubst.Homophonic.String(), "`Mode` of the substitution")
+	encryptCommand.IntVar(&caesarShift, `shift`, 3, "Number of `letters` each letter is shifted in the 'caesar' mode")
+	encryptCommand.BoolVar(&protectOutput, `protect`, false, `Write the encrypted file with an integrity check (default: plain encrypted file)`)
+
+	decryptCommand = flag.NewFlagSet(`decrypt`, flag.ContinueOnError)
 	decryptCommand.StringVar(&inFileName, `in`, ``, "Encrypted file `path`")
 	decryptCommand.StringVar(&outFileName, `out`, ``, "Decrypted file `path`")
 	decryptCommand.StringVar(&substFileName, `key`, ``, "Key file `path`")
+	decryptCommand.BoolVar(&forceOverwrite, `force`, false, `Overwrite existing output file (default: do not overwrite)`)
+	decryptCommand.BoolVar(&protectOutput, `protect`, false, `Verify the integrity check of an encrypted file written with 'protect' (default: plain encrypted file)`)
+
+	exerciseCommand = flag.NewFlagSet(`exercise`, flag.ContinueOnError)
+	exerciseCommand.StringVar(&inFileName, `in`, ``, "Clear text file `path`")
+	exerciseCommand.StringVar(&outFileName, `out`, ``, "Exercise sheet file `path`")
+	exerciseCommand.StringVar(&answerFileName, `answer`, ``, "Answer key file `path` (default: the answer key is part of the exercise sheet)")
+	exerciseCommand.StringVar(&substFileName, `key`, ``, "Key file `path` (default: no key file)")
+	exerciseCommand.IntVar(&revealCount, `reveal`, 5, "Number of letters whose homophones are revealed")
+	exerciseCommand.StringVar(&formatName, `format`, ``, "Output `format` (default: from the extension of the 'out' file, else markdown)")
+	exerciseCommand.BoolVar(&withFrequencies, `freq`, false, `Show the frequencies of the ciphertext characters (default: do not show)`)
+	exerciseCommand.BoolVar(&keepOthers, `keep`, false, `Keep characters that are not in the range A-Z (default: do not keep)`)
+	exerciseCommand.BoolVar(&forceOverwrite, `force`, false, `Overwrite existing output files (default: do not overwrite)`)
+
+	gradeCommand = flag.NewFlagSet(`grade`, flag.ContinueOnError)
+	gradeCommand.StringVar(&substFileName, `key`, ``, "Key file `path`")
+	gradeCommand.StringVar(&cipherFileName, `cipher`, ``, "Encrypted file `path`")
+	gradeCommand.StringVar(&answerFileName, `answer`, ``, "Answer file `path` with a plaintext or a key")
+	gradeCommand.StringVar(&kindName, `kind`, grade.Auto.String(), "`Kind` of the answer")
+
+	cribCommand = flag.NewFlagSet(`crib`, flag.ContinueOnError)
+	cribCommand.StringVar(&inFileName, `in`, ``, "Encrypted file `path`")
+	cribCommand.StringVar(&cribText, `crib`, ``, "Known `plaintext` that occurs in the clear text")
+	cribCommand.IntVar(&maxAlignments, `max`, 20, "Maximum number of alignments to show")
+	cribCommand.StringVar(&substFileName, `key`, ``, "Partial key file `path` (default: no key file)")
+	cribCommand.IntVar(&cribPosition, `pos`, 0, "`Position` of the alignment to show and to export (default: all alignments)")
+	cribCommand.BoolVar(&forceOverwrite, `force`, false, `Overwrite existing key file (default: do not overwrite)`)
+
+	lmBuildCommand = flag.NewFlagSet(`lm build`, flag.ContinueOnError)
+	lmBuildCommand.StringVar(&inFileName, `in`, ``, "Corpus file or directory `path`")
+	lmBuildCommand.StringVar(&outFileName, `out`, ``, "Language model file `path`")
+	lmBuildCommand.Uint64Var(&minNGramCount, `min`, 1, "Minimum number of occurrences of an n-gram")
+	lmBuildCommand.BoolVar(&forceOverwrite, `force`, false, `Overwrite existing output file (default: do not overwrite)`)
+
+	analyzeCommand = flag.NewFlagSet(`analyze`, flag.ContinueOnError)
+	analyzeCommand.StringVar(&inFileName, `in`, ``, "Encrypted file `path`")
+	analyzeCommand.IntVar(&topCount, `top`, 10, "Number of most frequent bigrams to show")
+	analyzeCommand.IntVar(&groupCount, `groups`, 0, "Number of `groups` of probable homophones to show (default: 0, i.e. no groups)")
+	analyzeCommand.StringVar(&plainFileName, `plain`, ``, "Clear text file `path` to compare the flatness of the modes for (default: no comparison)")
+	analyzeCommand.StringVar(&substFileName, `key`, ``, "Key file `path` to compare the groups with (default: no key file)")
+
+	serveCommand = flag.NewFlagSet(`serve`, flag.ContinueOnError)
+	serveCommand.StringVar(&listenAddress, `addr`, `:8080`, "TCP `address` to listen on")
+	serveCommand.Int64Var(&maxRequestSize, `limit`, server.DefaultMaxRequestSize, "Maximum request size in `bytes`")
+
+	replCommand = flag.NewFlagSet(`repl`, flag.ContinueOnError)
+	replCommand.StringVar(&substFileName, `key`, ``, "Key file `path` (default: create the key from the first line)")
+	replCommand.BoolVar(&keepOthers, `keep`, false, `Keep characters that are not in the range A-Z (default: do not keep)`)
+
+	encryptCommand.SetOutput(errWriter)
+	decryptCommand.SetOutput(errWriter)
+	exerciseCommand.SetOutput(errWriter)
+	gradeCommand.SetOutput(errWriter)
+	cribCommand.SetOutput(errWriter)
+	lmBuildCommand.SetOutput(errWriter)
+	analyzeCommand.SetOutput(errWriter)
+	serveCommand.SetOutput(errWriter)
+	replCommand.SetOutput(errWriter)
+}
+
+// parseDecryption parses the arguments of a "decrypt" command.
+// It returns true, if the decryption is to be done, and the return code.
+func parseDecryption(args []string) (bool, int) {
+	err := decryptCommand.Parse(args)
+	if err != nil {
+		return false, rcHelpOrError(err)
+	}
+
+	rc := checkDecryptionFlags()
+
+	return rc == rcOK, rc
+}
+
+// parseEncryption parses the arguments of an "encrypt" command.
+// It returns true, if the encryption is to be done, and the return code.
+func parseEncryption(args []string) (bool, int) {
+	err := encryptCommand.Parse(args)
+	if err != nil {
+		return false, rcHelpOrError(err)
+	}
+
+	rc := checkEncryptionFlags()
+
+	return rc == rcOK, rc
+}
+
+// parseExercise parses the arguments of an "exercise" command.
+// It returns true, if the exercise is to be created, and the return code.
+func parseExercise(args []string) (bool, int) {
+	err := exerciseCommand.Parse(args)
+	if err != nil {
+		return false, rcHelpOrError(err)
+	}
+
+	rc := checkExerciseFlags()
+
+	return rc == rcOK, rc
+}
+
+// parseGrade parses the arguments of a "grade" command.
+// It returns true, if the answer is to be graded, and the return code.
+func parseGrade(args []string) (bool, int) {
+	err := gradeCommand.Parse(args)
+	if err != nil {
+		return false, rcHelpOrError(err)
+	}
+
+	rc := checkGradeFlags()
+
+	return rc == rcOK, rc
+}
+
+// parseCrib parses the arguments of a "crib" command.
+// It returns true, if the crib attack is to be done, and the return code.
+func parseCrib(args []string) (bool, int) {
+	err := cribCommand.Parse(args)
+	if err != nil {
+		return false, rcHelpOrError(err)
+	}
+
+	rc := checkCribFlags()
+
+	return rc == rcOK, rc
+}
+
+// parseLanguageModel parses the arguments of an "lm" command.
+// It returns true, if the language model is to be built, and the return code.
+func parseLanguageModel(args []string) (bool, int) {
+	if len(args) == 0 {
+		return false, printUsageError(`Language model command is missing`)
+	}
+
+	if len(args[0]) == 0 || !strings.HasPrefix(lmBuildCommandName, strings.ToLower(args[0])) {
+		return false, printUsageErrorf(`Unknown language model command: '%s'`, args[0])
+	}
+
+	err := lmBuildCommand.Parse(args[1:])
+	if err != nil {
+		return false, rcHelpOrError(err)
+	}
+
+	rc := checkLanguageModelFlags()
+
+	return rc == rcOK, rc
+}
+
+// parseAnalysis parses the arguments of an "analyze" command.
+// It returns true, if the analysis is to be done, and the return code.
+func parseAnalysis(args []string) (bool, int) {
+	err := analyzeCommand.Parse(args)
+	if err != nil {
+		return false, rcHelpOrError(err)
+	}
 
-	flag.Usage = myUsage
+	rc := checkAnalysisFlags()
+
+	return rc == rcOK, rc
 }
 
-// parseDecryption parses the command line of a "decrypt" command.
-func parseDecryption() int {
-	err := decryptCommand.Parse(os.Args[2:])
+// parseServe parses the arguments of a "serve" command.
+// It returns true, if the server is to be started, and the return code.
+func parseServe(args []string) (bool, int) {
+	err := serveCommand.Parse(args)
 	if err != nil {
-		return rcHelpOrError(err)
+		return false, rcHelpOrError(err)
 	}
 
-	return checkDecryptionFlags()
+	rc := checkServeFlags()
+
+	return rc == rcOK, rc
 }
 
-// parseEncryption parses the command line of an "encrypt" command.
-func parseEncryption() int {
-	err := encryptCommand.Parse(os.Args[2:])
+// parseRepl parses the arguments of a "repl" command.
+// It returns true, if the interactive session is to be started, and the return code.
+func parseRepl(args []string) (bool, int) {
+	err := replCommand.Parse(args)
 	if err != nil {
-		return rcHelpOrError(err)
+		return false, rcHelpOrError(err)
 	}
 
-	return checkEncryptionFlags()
+	additionalArgs := replCommand.Args()
+	if len(additionalArgs) > 0 {
+		return false, printUsageErrorf(`Arguments without flags present: %s`, additionalArgs)
+	}
+
+	return true, rcOK
 }
 
 // checkDecryptionFlags checks the decryption flags.
@@ -124,7 +462,7 @@ func checkDecryptionFlags() int {
 		outFileName = buildDecryptOutFilePath(inFileName)
 	}
 
-	return rcOK
+	return checkFiles([]string{inFileName, substFileName}, []string{outFileName})
 }
 
 // checkEncryptionFlags checks the encryption flags.
@@ -138,9 +476,247 @@ func checkEncryptionFlags() int {
 		outFileName = buildEncryptOutFilePath(inFileName)
 	}
 
+	var err error
+	hashAlgorithm, err = integritycheckedfile.ParseHashAlgorithm(hashAlgorithmName)
+	if err != nil {
+		return printUsageErrorf(`Invalid hash algorithm: %v`, err)
+	}
+
+	apportioner, err = distributor.ParseMethod(methodName)
+	if err != nil {
+		return printUsageErrorf(`Invalid apportionment method: %v`, err)
+	}
+
+	minLengths, err = parseLengthSpecification(minLengthSpec, 0)
+	if err != nil {
+		return printUsageErrorf(`Invalid minimum lengths: %v`, err)
+	}
+
+	maxLengths, err = parseLengthSpecification(maxLengthSpec, math.MaxUint16)
+	if err != nil {
+		return printUsageErrorf(`Invalid maximum lengths: %v`, err)
+	}
+
+	selection, err = randomlist.ParseStrategy(selectionName)
+	if err != nil {
+		return printUsageErrorf(`Invalid selection strategy: %v`, err)
+	}
+
+	if contextClassCount < 0 || contextClassCount > homosubst.MaxContextClassCount {
+		return printUsageErrorf(`Invalid number of predecessor classes: %d. It must be between 0 and %d`, contextClassCount, homosubst.MaxContextClassCount)
+	}
+
+	cipherMode, err = homosubst.ParseMode(modeName)
+	if err != nil {
+		return printUsageErrorf(`Invalid mode: %v`, err)
+	}
+
+	if cipherMode != homosubst.Homophonic && contextClassCount != 0 {
+		return printUsageErrorf(`Predecessor classes can only be used with mode '%s'`, homosubst.Homophonic)
+	}
+
+	if cipherMode != homosubst.Homophonic {
+		for _, name := range []string{`method`, `min`, `max`, `select`, `weighted`} {
+			if isFlagSet(encryptCommand, name) {
+				return printUsageErrorf(`Option '%s' can only be used with mode '%s'`, name, homosubst.Homophonic)
+			}
+		}
+
+		// The baseline modes do not apportion the substitutions.
+		apportioner = nil
+	}
+
+	if contextClassCount > 0 && (weighted || selection != randomlist.DefaultStrategy) {
+		return printUsageError(`Predecessor classes can only be used with the default selection strategy and without weighting`)
+	}
+
+	if cipherMode != homosubst.Caesar && isFlagSet(encryptCommand, `shift`) {
+		return printUsageErrorf(`A shift can only be used with mode '%s'`, homosubst.Caesar)
+	}
+
+	return checkFiles([]string{inFileName}, []string{outFileName, substFileName})
+}
+
+// checkExerciseFlags checks the flags of the "exercise" command.
+func checkExerciseFlags() int {
+	additionalArgs := exerciseCommand.Args()
+	if len(additionalArgs) > 0 {
+		return printUsageErrorf(`Arguments without flags present: %s`, additionalArgs)
+	}
+
+	if len(inFileName) == 0 {
+		return printUsageError(`Name of clear text file is missing`)
+	}
+
+	if revealCount < 0 {
+		return printUsageErrorf(`Invalid number of revealed letters: %d`, revealCount)
+	}
+
+	var err error
+	switch {
+	case len(formatName) != 0:
+		sheetFormat, err = exercise.ParseFormat(formatName)
+		if err != nil {
+			return printUsageErrorf(`Invalid output format: %v`, err)
+		}
+
+	case len(outFileName) != 0:
+		sheetFormat = exercise.FormatFromFileName(outFileName)
+
+	default:
+		sheetFormat = exercise.Markdown
+	}
+
+	if len(outFileName) == 0 {
+		outFileName = buildExerciseOutFilePath(inFileName, sheetFormat.Extension())
+	}
+
+	outFileNames := []string{outFileName}
+	for _, fileName := range []string{answerFileName, substFileName} {
+		if len(fileName) != 0 {
+			outFileNames = append(outFileNames, fileName)
+		}
+	}
+
+	return checkFiles([]string{inFileName}, outFileNames)
+}
+
+// checkGradeFlags checks the flags of the "grade" command.
+func checkGradeFlags() int {
+	additionalArgs := gradeCommand.Args()
+	if len(additionalArgs) > 0 {
+		return printUsageErrorf(`Arguments without flags present: %s`, additionalArgs)
+	}
+
+	if len(substFileName) == 0 {
+		return printUsageError(`Name of key file is missing`)
+	}
+
+	if len(cipherFileName) == 0 {
+		return printUsageError(`Name of encrypted file is missing`)
+	}
+
+	if len(answerFileName) == 0 {
+		return printUsageError(`Name of answer file is missing`)
+	}
+
+	var err error
+	answerKind, err = grade.ParseKind(kindName)
+	if err != nil {
+		return printUsageErrorf(`Invalid kind of answer: %v`, err)
+	}
+
+	return rcOK
+}
+
+// checkCribFlags checks the flags of the "crib" command.
+func checkCribFlags() int {
+	additionalArgs := cribCommand.Args()
+	if len(additionalArgs) > 0 {
+		return printUsageErrorf(`Arguments without flags present: %s`, additionalArgs)
+	}
+
+	if len(inFileName) == 0 {
+		return printUsageError(`Name of encrypted file is missing`)
+	}
+
+	if len(crib.NormalizeCrib(cribText)) == 0 {
+		return printUsageError(`Crib is missing or contains no letters`)
+	}
+
+	if maxAlignments < 1 {
+		return printUsageErrorf(`Invalid maximum number of alignments: %d`, maxAlignments)
+	}
+
+	if cribPosition < 0 {
+		return printUsageErrorf(`Invalid position: %d`, cribPosition)
+	}
+
+	if len(substFileName) == 0 {
+		return rcOK
+	}
+
+	return checkFiles([]string{inFileName}, []string{substFileName})
+}
+
+// checkLanguageModelFlags checks the flags of the "lm build" command.
+func checkLanguageModelFlags() int {
+	additionalArgs := lmBuildCommand.Args()
+	if len(additionalArgs) > 0 {
+		return printUsageErrorf(`Arguments without flags present: %s`, additionalArgs)
+	}
+
+	if len(inFileName) == 0 {
+		return printUsageError(`Name of corpus is missing`)
+	}
+
+	if len(outFileName) == 0 {
+		return printUsageError(`Name of language model file is missing`)
+	}
+
+	if minNGramCount < 1 {
+		return printUsageErrorf(`Invalid minimum number of occurrences: %d`, minNGramCount)
+	}
+
+	return checkFiles([]string{inFileName}, []string{outFileName})
+}
+
+// checkAnalysisFlags checks the flags of the "analyze" command.
+func checkAnalysisFlags() int {
+	additionalArgs := analyzeCommand.Args()
+	if len(additionalArgs) > 0 {
+		return printUsageErrorf(`Arguments without flags present: %s`, additionalArgs)
+	}
+
+	if len(inFileName) == 0 {
+		return printUsageError(`Name of encrypted file is missing`)
+	}
+
+	if topCount < 0 {
+		return printUsageErrorf(`Invalid number of bigrams: %d`, topCount)
+	}
+
+	if groupCount < 0 || groupCount > len(analysis.SubstitutionAlphabet) {
+		return printUsageErrorf(`Invalid number of groups: %d. It must be between 0 and %d`, groupCount, len(analysis.SubstitutionAlphabet))
+	}
+
+	if len(substFileName) != 0 && groupCount == 0 {
+		return printUsageError(`A key file can only be compared with groups. Specify 'groups'`)
+	}
+
+	return rcOK
+}
+
+// checkServeFlags checks the flags of the "serve" command.
+func checkServeFlags() int {
+	additionalArgs := serveCommand.Args()
+	if len(additionalArgs) > 0 {
+		return printUsageErrorf(`Arguments without flags present: %s`, additionalArgs)
+	}
+
+	if len(listenAddress) == 0 {
+		return printUsageError(`Listen address is missing`)
+	}
+
+	if maxRequestSize <= 0 {
+		return printUsageErrorf(`Invalid maximum request size: %d`, maxRequestSize)
+	}
+
 	return rcOK
 }
 
+// isFlagSet returns true, if the flag with the given name has been set on the command line.
+func isFlagSet(command *flag.FlagSet, name string) bool {
+	result := false
+	command.Visit(func(f *flag.Flag) {
+		if f.Name == name {
+			result = true
+		}
+	})
+
+	return result
+}
+
 // checkFlagsCommon does the checks common to all commands.
 func checkFlagsCommon(typeName string, additionalArgs []string) int {
 	if len(additionalArgs) > 0 {
@@ -158,9 +734,40 @@ func checkFlagsCommon(typeName string, additionalArgs []string) int {
 	return rcOK
 }
 
-// myUsage is the function that is called by flag.Usage. It prints the usage information.
+// checkFiles checks that no output file is the same file as another input or output file
+// and that the output files do not exist, unless overwriting is forced.
+func checkFiles(inFileNames []string, outFileNames []string) int {
+	allFileNames := append(slices.Clone(outFileNames), inFileNames...)
+
+	for i, outFileName := range outFileNames {
+		for _, otherFileName := range allFileNames[i+1:] {
+			isSame, err := filehelper.IsSameFile(outFileName, otherFileName)
+			if err != nil {
+				return printErrorf(`Error comparing files '%s' and '%s': %v`, outFileName, otherFileName, err)
+			}
+
+			if isSame {
+				return printUsageErrorf(`Files '%s' and '%s' are the same file`, outFileName, otherFileName)
+			}
+		}
+
+		if !forceOverwrite {
+			exists, err := filehelper.FileExists(outFileName)
+			if err != nil {
+				return printErrorf(`Error checking file '%s': %v`, outFileName, err)
+			}
+
+			if exists {
+				return printUsageErrorf(`Output file '%s' already exists. Specify 'force' to overwrite it`, outFileName)
+			}
+		}
+	}
+
+	return rcOK
+}
+
+// myUsage prints the usage information.
 func myUsage() {
-	errWriter := flag.CommandLine.Output()
 	_, _ = fmt.Fprintf(errWriter, "\n'%s' implements a homomorphic encryption.\n", myName)
 	_, _ = fmt.Fprintln(errWriter, `The characters A-Z are mapped to characters in the range A-Z and a-z.
 Characters in the range a-z are converted to upper case and then mapped to the range A-Z and a-z.
@@ -177,6 +784,8 @@ The character substitution table is written to a key file which is required for
 	_, _ = fmt.Fprintln(errWriter)
 	_, _ = fmt.Fprintln(errWriter, `If the 'key' file path is not specified the name 'infilebasename_ext.subst' is used.`)
 	_, _ = fmt.Fprintln(errWriter, `If the 'out' file path is not specified the name 'infilebasename_homophone.txt' is used.`)
+	_, _ = fmt.Fprintln(errWriter, `If 'force' is not specified, an existing 'out' file is not overwritten`)
+	_, _ = fmt.Fprintln(errWriter, `If 'protect' is specified, the decrypted file is only written, if the encrypted file has not been modified`)
 	_, _ = fmt.Fprintln(errWriter)
 	_, _ = fmt.Fprintln(errWriter, `Options can be started with either '-' or '--'`)
 	_, _ = fmt.Fprintln(errWriter)
@@ -187,6 +796,79 @@ The character substitution table is written to a key file which is required for
 	_, _ = fmt.Fprintln(errWriter, `If the 'out' file path is not specified the name 'infilebasename_decrypted.txt' is used.`)
 	_, _ = fmt.Fprintln(errWriter, `If 'keep' is specified, all characters not in the range A-Z are kept and copied to the output file`)
 	_, _ = fmt.Fprintln(errWriter, `If 'keep' is not specified, only characters in the range A-Z are copied to the output file. All others are discarded`)
+	_, _ = fmt.Fprintln(errWriter, `If 'force' is not specified, existing 'out' and 'key' files are not overwritten`)
+	_, _ = fmt.Fprintln(errWriter, `If 'protect' is specified, the encrypted file contains an integrity check with a key derived from the key file. It has to be decrypted with 'protect'`)
+	_, _ = fmt.Fprintf(errWriter, "The 'hash' algorithm can be one of %s\n", strings.Join(integritycheckedfile.HashAlgorithmNames(), `, `))
+	_, _ = fmt.Fprintf(errWriter, "The 'method' can be one of %s\n", strings.Join(distributor.MethodNames(), `, `))
+	_, _ = fmt.Fprintln(errWriter, `The 'min' and 'max' lengths are a comma-separated list of a number for all letters and letter=number items for single letters, e.g. '1,E=4,Q=0'`)
+	_, _ = fmt.Fprintln(errWriter, `A letter that occurs in the clear text always gets at least one substitution`)
+	_, _ = fmt.Fprintf(errWriter, "The 'select' strategy can be one of %s\n", strings.Join(randomlist.StrategyNames(), `, `))
+	_, _ = fmt.Fprintln(errWriter, `If 'weighted' is specified, the 'select' strategy is ignored`)
+	_, _ = fmt.Fprintln(errWriter, `If 'bigram' is greater than 0, the substitution of a letter depends on the class of the letter before it. This is experimental`)
+	_, _ = fmt.Fprintln(errWriter, `'bigram' can not be combined with 'select' or 'weighted'`)
+	_, _ = fmt.Fprintf(errWriter, "The 'mode' can be one of %s\n", strings.Join(homosubst.ModeNames(), `, `))
+	_, _ = fmt.Fprintln(errWriter, `With the modes 'mono' and 'caesar' each letter has exactly one substitution and 'method', 'min', 'max', 'select', 'weighted' and 'bigram' can not be used`)
+	_, _ = fmt.Fprintln(errWriter)
+	_, _ = fmt.Fprintln(errWriter, `Options can be started with either '-' or '--'`)
+	_, _ = fmt.Fprintln(errWriter)
+	_, _ = fmt.Fprintln(errWriter, `exercise: Create a cryptanalysis exercise from a clear text file`)
+	exerciseCommand.PrintDefaults()
+	_, _ = fmt.Fprintln(errWriter)
+	_, _ = fmt.Fprintln(errWriter, `If the 'out' file path is not specified the name 'infilebasename_exercise.md' or 'infilebasename_exercise.html' is used.`)
+	_, _ = fmt.Fprintf(errWriter, "The 'format' can be one of %s\n", strings.Join(exercise.FormatNames(), `, `))
+	_, _ = fmt.Fprintln(errWriter, `The letters whose homophones are revealed are chosen randomly`)
+	_, _ = fmt.Fprintln(errWriter, `If 'force' is not specified, existing 'out', 'answer' and 'key' files are not overwritten`)
+	_, _ = fmt.Fprintln(errWriter)
+	_, _ = fmt.Fprintln(errWriter, `Options can be started with either '-' or '--'`)
+	_, _ = fmt.Fprintln(errWriter)
+	_, _ = fmt.Fprintln(errWriter, `grade: Grade a plaintext or a key that a student recovered from an encrypted file`)
+	gradeCommand.PrintDefaults()
+	_, _ = fmt.Fprintln(errWriter)
+	_, _ = fmt.Fprintf(errWriter, "The 'kind' can be one of %s\n", strings.Join(grade.KindNames(), `, `))
+	_, _ = fmt.Fprintln(errWriter, `A key contains lines like 'E: abc' or a JSON object like {"E": "abc"}`)
+	_, _ = fmt.Fprintln(errWriter)
+	_, _ = fmt.Fprintln(errWriter, `Options can be started with either '-' or '--'`)
+	_, _ = fmt.Fprintln(errWriter)
+	_, _ = fmt.Fprintln(errWriter, `crib: Find the positions of a known plaintext in an encrypted file`)
+	cribCommand.PrintDefaults()
+	_, _ = fmt.Fprintln(errWriter)
+	_, _ = fmt.Fprintln(errWriter, `A position is rejected if a substitution character would have to stand for two different letters`)
+	_, _ = fmt.Fprintln(errWriter, `Positions count the characters in the ranges A-Z and a-z, starting with 1`)
+	_, _ = fmt.Fprintln(errWriter, `If more than one position is possible, 'pos' must be specified to export a 'key' file`)
+	_, _ = fmt.Fprintln(errWriter, `If 'force' is not specified, an existing 'key' file is not overwritten`)
+	_, _ = fmt.Fprintln(errWriter)
+	_, _ = fmt.Fprintln(errWriter, `Options can be started with either '-' or '--'`)
+	_, _ = fmt.Fprintln(errWriter)
+	_, _ = fmt.Fprintln(errWriter, `lm build: Build a language model from a text corpus`)
+	lmBuildCommand.PrintDefaults()
+	_, _ = fmt.Fprintln(errWriter)
+	_, _ = fmt.Fprintln(errWriter, `The corpus is a file or a directory, whose files are all read, including those in subdirectories`)
+	_, _ = fmt.Fprintln(errWriter, `The model contains the probabilities of the trigrams and quadgrams of the letters A-Z of the corpus`)
+	_, _ = fmt.Fprintln(errWriter, `N-grams that occur less than 'min' times are treated as if they did not occur, which makes the model file smaller`)
+	_, _ = fmt.Fprintln(errWriter, `If 'force' is not specified, an existing 'out' file is not overwritten`)
+	_, _ = fmt.Fprintln(errWriter)
+	_, _ = fmt.Fprintln(errWriter, `Options can be started with either '-' or '--'`)
+	_, _ = fmt.Fprintln(errWriter)
+	_, _ = fmt.Fprintln(errWriter, `analyze: Show the frequencies of the characters and bigrams of an encrypted file`)
+	analyzeCommand.PrintDefaults()
+	_, _ = fmt.Fprintln(errWriter)
+	_, _ = fmt.Fprintln(errWriter, `If 'groups' is greater than 0, the characters are grouped by the similarity of their neighbors, as homophones of a letter have the same neighbors`)
+	_, _ = fmt.Fprintln(errWriter, `If a 'key' file is specified, the groups are compared with its homophones`)
+	_, _ = fmt.Fprintln(errWriter, `If a 'plain' file is specified, the expected flatness of all modes for this clear text is shown`)
+	_, _ = fmt.Fprintln(errWriter)
+	_, _ = fmt.Fprintln(errWriter, `Options can be started with either '-' or '--'`)
+	_, _ = fmt.Fprintln(errWriter)
+	_, _ = fmt.Fprintln(errWriter, `serve: Serve the endpoints /encrypt, /decrypt, /keygen and /analyze over HTTP until interrupted`)
+	serveCommand.PrintDefaults()
+	_, _ = fmt.Fprintln(errWriter)
+	_, _ = fmt.Fprintln(errWriter, `All endpoints accept POST requests with a JSON, multipart or form body and return JSON. Keys are base64 encoded key files`)
+	_, _ = fmt.Fprintln(errWriter)
+	_, _ = fmt.Fprintln(errWriter, `Options can be started with either '-' or '--'`)
+	_, _ = fmt.Fprintln(errWriter)
+	_, _ = fmt.Fprintln(errWriter, `repl: Encrypt and decrypt the lines that are typed in`)
+	replCommand.PrintDefaults()
+	_, _ = fmt.Fprintln(errWriter)
+	_, _ = fmt.Fprintln(errWriter, `Lines starting with ':' are commands. Type ':help' to show them`)
 	_, _ = fmt.Fprintln(errWriter)
 	_, _ = fmt.Fprintln(errWriter, `Options can be started with either '-' or '--'`)
 	_, _ = fmt.Fprintln(errWriter)
@@ -20,10 +20,11 @@
 //
 // Author: Frank Schwab
 //
-// Version: 1.0.0
+// Version: 1.1.0
 //
 // Change history:
 //    2023-03-25: V1.0.0: Created.
+//    2026-10-18: V1.1.0: Reentrant functions, add AppendUInt32 and PutUInt32.
 //
 
 package compressedinteger
@@ -39,6 +40,9 @@ import (
 const MinAllowedInt = 0
 const MaxAllowedInt = 0x40404040 - 1
 
+// MaxLength is the maximum length of a compressed representation.
+const MaxLength = 4
+
 // List of errors
 
 var ErrIntIsNegative = errors.New(`integer is negative`)
@@ -58,47 +62,45 @@ const byteMaskForInteger = 0xff
 
 // Slice manipulation constants
 
-const resultSliceLength = 4
-const resultMaxIndex = resultSliceLength - 1
+const resultMaxIndex = MaxLength - 1
 const lengthBitsShiftValue = 6
 
-// ******** Private global variables ********
-
-// result is the global result buffer (i.e. all returned byte slices point here)
-var result = make([]byte, resultSliceLength)
-
 // ******** Public functions ********
 
-// FromUInt32 converts an uint32 to a compressed representation byte slice
+// FromUInt32 converts an uint32 to a compressed representation byte slice.
+// Each call returns a new byte slice, so it is safe for concurrent use.
 func FromUInt32(i uint32) ([]byte, error) {
 	// Check if value is within allowed range
 	if i > MaxAllowedInt {
 		return nil, ErrIntIsTooLarge
 	}
 
-	// Convert to byte array
+	return AppendUInt32(make([]byte, 0, MaxLength), i), nil
+}
 
-	// This loop subtracts the offset from each byte
-	temp := i
-	actIndex := resultMaxIndex
-	for temp >= offsetValue {
-		b := temp & byteMaskForInteger
-		temp >>= 8
-		if b >= offsetValue {
-			b -= offsetValue
-		} else {
-			b += 256 - offsetValue
-			temp--
-		}
+// AppendUInt32 appends the compressed representation of v to dst and returns the extended slice.
+// It panics with [ErrIntIsTooLarge], if v is larger than [MaxAllowedInt].
+func AppendUInt32(dst []byte, v uint32) []byte {
+	var buffer [MaxLength]byte
+	start := encodeUInt32(&buffer, v)
 
-		result[actIndex] = byte(b)
-		actIndex--
-	}
+	return append(dst, buffer[start:]...)
+}
 
-	// Add length flag
-	result[actIndex] = byte(temp | (uint32(resultMaxIndex-actIndex) << lengthBitsShiftValue))
+// PutUInt32 writes the compressed representation of v to the start of dst and returns the number of bytes written.
+// It panics with [ErrIntIsTooLarge], if v is larger than [MaxAllowedInt],
+// and with [ErrSliceTooSmall], if dst is too small.
+// A dst with a length of [MaxLength] is always large enough.
+func PutUInt32(dst []byte, v uint32) int {
+	var buffer [MaxLength]byte
+	start := encodeUInt32(&buffer, v)
+
+	n := MaxLength - start
+	if len(dst) < n {
+		panic(ErrSliceTooSmall)
+	}
 
-	return result[actIndex:resultSliceLength], nil
+	return copy(dst, buffer[start:])
 }
 
 // FromInt converts an int to a compressed representation byte slice
@@ -140,6 +142,40 @@ func ToInt(p []byte) (int, int, error) {
 	return int(i), l, err
 }
 
+// ExpectedLength returns the length of a compressed representation that starts with byte b.
 func ExpectedLength(b byte) int {
 	return int(b>>lengthBitsShiftValue) + 1
 }
+
+// ******** Private functions ********
+
+// encodeUInt32 writes the compressed representation of v to the end of buffer
+// and returns the index of its first byte.
+func encodeUInt32(buffer *[MaxLength]byte, v uint32) int {
+	// Check if value is within allowed range
+	if v > MaxAllowedInt {
+		panic(ErrIntIsTooLarge)
+	}
+
+	// This loop subtracts the offset from each byte
+	temp := v
+	actIndex := resultMaxIndex
+	for temp >= offsetValue {
+		b := temp & byteMaskForInteger
+		temp >>= 8
+		if b >= offsetValue {
+			b -= offsetValue
+		} else {
+			b += 256 - offsetValue
+			temp--
+		}
+
+		buffer[actIndex] = byte(b)
+		actIndex--
+	}
+
+	// Add length flag
+	buffer[actIndex] = byte(temp | (uint32(resultMaxIndex-actIndex) << lengthBitsShiftValue))
+
+	return actIndex
+}
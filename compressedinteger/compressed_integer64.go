@@ -0,0 +1,195 @@
+//
+// SPDX-FileCopyrightText: Copyright 2026 Frank Schwab
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileType: SOURCE
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+//
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Author: Frank Schwab
+//
+// Version: 1.0.0
+//
+// Change history:
+//    2026-10-18: V1.0.0: Created.
+//
+
+package compressedinteger
+
+import (
+	"errors"
+	"math"
+)
+
+// The 64-bit variant uses the same scheme as the 32-bit format, but with 4 length bits
+// in the first byte instead of 2. So a compressed representation has a length of 1 to 9 bytes.
+// The two formats are not interchangeable.
+//
+// Signed integers are mapped to unsigned integers with the zigzag encoding
+// (0, -1, 1, -2, 2, ... is mapped to 0, 1, 2, 3, 4, ...), so small absolute values
+// result in short representations.
+
+// ******** Public constants ********
+
+// MaxLength64 is the maximum length of a compressed representation of a 64-bit integer.
+const MaxLength64 = 9
+
+// ErrInvalidLength is returned when the length bits of a compressed integer denote an invalid length.
+var ErrInvalidLength = errors.New(`invalid length of compressed integer`)
+
+// ******** Private constants ********
+
+// offsetValue64 is the offset for the conversion of 64-bit integers.
+const offsetValue64 = 0x10
+
+// noLengthMaskForByte64 is the mask for the value bits in the first byte of a 64-bit integer.
+const noLengthMaskForByte64 = offsetValue64 - 1
+
+// lengthBitsShiftValue64 is the position of the length bits in the first byte of a 64-bit integer.
+const lengthBitsShiftValue64 = 4
+
+// resultMaxIndex64 is the maximum index in a 64-bit conversion buffer.
+const resultMaxIndex64 = MaxLength64 - 1
+
+// ******** Public functions ********
+
+// AppendUInt64 appends the compressed representation of v to dst and returns the extended slice.
+func AppendUInt64(dst []byte, v uint64) []byte {
+	var buffer [MaxLength64]byte
+	start := encodeUInt64(&buffer, v)
+
+	return append(dst, buffer[start:]...)
+}
+
+// PutUInt64 writes the compressed representation of v to the start of dst and returns the number of bytes written.
+// It panics with [ErrSliceTooSmall], if dst is too small.
+// A dst with a length of [MaxLength64] is always large enough.
+func PutUInt64(dst []byte, v uint64) int {
+	var buffer [MaxLength64]byte
+	start := encodeUInt64(&buffer, v)
+
+	n := MaxLength64 - start
+	if len(dst) < n {
+		panic(ErrSliceTooSmall)
+	}
+
+	return copy(dst, buffer[start:])
+}
+
+// ToUInt64 converts a compressed representation into an uint64.
+// It returns the value and the number of bytes used.
+func ToUInt64(p []byte) (uint64, int, error) {
+	pLen := len(p)
+	if pLen == 0 {
+		return 0, 0, ErrSliceTooSmall
+	}
+
+	expectedLength := ExpectedLength64(p[0])
+	if expectedLength > MaxLength64 {
+		return 0, 0, ErrInvalidLength
+	}
+
+	if pLen < expectedLength {
+		return 0, 0, ErrSliceTooSmall
+	}
+
+	t := uint64(p[0] & noLengthMaskForByte64)
+	var err error
+	for i := 1; i < expectedLength; i++ {
+		t, err = addByte64(t, p[i])
+		if err != nil {
+			return 0, 0, err
+		}
+	}
+
+	return t, expectedLength, nil
+}
+
+// AppendInt64 appends the zigzag compressed representation of v to dst and returns the extended slice.
+func AppendInt64(dst []byte, v int64) []byte {
+	return AppendUInt64(dst, zigzagEncode(v))
+}
+
+// PutInt64 writes the zigzag compressed representation of v to the start of dst and returns the number of bytes written.
+// It panics with [ErrSliceTooSmall], if dst is too small.
+func PutInt64(dst []byte, v int64) int {
+	return PutUInt64(dst, zigzagEncode(v))
+}
+
+// ToInt64 converts a zigzag compressed representation into an int64.
+// It returns the value and the number of bytes used.
+func ToInt64(p []byte) (int64, int, error) {
+	u, l, err := ToUInt64(p)
+	return zigzagDecode(u), l, err
+}
+
+// ExpectedLength64 returns the length of a compressed 64-bit representation that starts with byte b.
+func ExpectedLength64(b byte) int {
+	return int(b>>lengthBitsShiftValue64) + 1
+}
+
+// ******** Private functions ********
+
+// encodeUInt64 writes the compressed representation of v to the end of buffer
+// and returns the index of its first byte.
+func encodeUInt64(buffer *[MaxLength64]byte, v uint64) int {
+	// This loop subtracts the offset from each byte
+	temp := v
+	actIndex := resultMaxIndex64
+	for temp >= offsetValue64 {
+		b := temp & byteMaskForInteger
+		temp >>= 8
+		if b >= offsetValue64 {
+			b -= offsetValue64
+		} else {
+			b += 256 - offsetValue64
+			temp--
+		}
+
+		buffer[actIndex] = byte(b)
+		actIndex--
+	}
+
+	// Add length flag
+	buffer[actIndex] = byte(temp | (uint64(resultMaxIndex64-actIndex) << lengthBitsShiftValue64))
+
+	return actIndex
+}
+
+// addByte64 adds the next byte of a compressed representation to the value decoded so far.
+// It returns [ErrIntIsTooLarge], if the result does not fit into an uint64.
+func addByte64(t uint64, b byte) (uint64, error) {
+	if t > math.MaxUint64>>8 {
+		return 0, ErrIntIsTooLarge
+	}
+
+	t = (t << 8) | uint64(b)
+	if t > math.MaxUint64-offsetValue64 {
+		return 0, ErrIntIsTooLarge
+	}
+
+	return t + offsetValue64, nil
+}
+
+// zigzagEncode maps a signed integer to an unsigned integer,
+// so that small absolute values are mapped to small values.
+func zigzagEncode(v int64) uint64 {
+	return uint64(v<<1) ^ uint64(v>>63)
+}
+
+// zigzagDecode is the inverse of [zigzagEncode].
+func zigzagDecode(u uint64) int64 {
+	return int64(u>>1) ^ -int64(u&1)
+}
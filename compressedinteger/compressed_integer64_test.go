@@ -0,0 +1,300 @@
+//
+// SPDX-FileCopyrightText: Copyright 2026 Frank Schwab
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileType: SOURCE
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+//
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Author: Frank Schwab
+//
+// Version: 1.0.0
+//
+// Change history:
+//    2026-10-18: V1.0.0: Created.
+//
+
+package compressedinteger
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"math"
+	"testing"
+)
+
+// ******** Private variables ********
+
+var uint64Values = []uint64{0, 0xf,
+	0x10, 0x100f,
+	0x1010, 0x10100f,
+	0x101010, 0x1010100f,
+	0x10101010, 0x101010100f,
+	0x1010101010, 0x10101010100f,
+	0x101010101010, 0x1010101010100f,
+	0x10101010101010, 0x101010101010100f,
+	0x1010101010101010, math.MaxUint64,
+}
+
+var uint64ByteSliceValues = [][]byte{{0x00}, {0x0f},
+	{0x10, 0x00}, {0x1f, 0xff},
+	{0x20, 0x00, 0x00}, {0x2f, 0xff, 0xff},
+	{0x30, 0x00, 0x00, 0x00}, {0x3f, 0xff, 0xff, 0xff},
+	{0x40, 0x00, 0x00, 0x00, 0x00}, {0x4f, 0xff, 0xff, 0xff, 0xff},
+	{0x50, 0x00, 0x00, 0x00, 0x00, 0x00}, {0x5f, 0xff, 0xff, 0xff, 0xff, 0xff},
+	{0x60, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00}, {0x6f, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff},
+	{0x70, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00}, {0x7f, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff},
+	{0x80, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00}, {0x80, 0xef, 0xef, 0xef, 0xef, 0xef, 0xef, 0xef, 0xef},
+}
+
+var int64Values = []int64{0, -1, 1, -2, 2, -8, 7, -9, 8, math.MinInt64, math.MaxInt64}
+
+// ******** Test functions ********
+
+// TestBoundariesAppendUInt64 tests the conversion of 64-bit boundary values.
+func TestBoundariesAppendUInt64(t *testing.T) {
+	for i, n := range uint64Values {
+		c := AppendUInt64(nil, n)
+		if !bytes.Equal(c, uint64ByteSliceValues[i]) {
+			t.Fatalf("conversion of %d (0x%x) resulted in 0x%x instead of 0x%x", n, n, c, uint64ByteSliceValues[i])
+		}
+
+		var buffer [MaxLength64]byte
+		l := PutUInt64(buffer[:], n)
+		if !bytes.Equal(buffer[:l], c) {
+			t.Fatalf("putting %d (0x%x) resulted in 0x%x instead of 0x%x", n, n, buffer[:l], c)
+		}
+	}
+}
+
+// TestBoundariesToUInt64 tests the conversion of 64-bit boundary byte slices.
+func TestBoundariesToUInt64(t *testing.T) {
+	for i, c := range uint64ByteSliceValues {
+		n, l, err := ToUInt64(c)
+		if err != nil {
+			t.Fatalf("conversion of 0x%x resulted in error: %v", c, err)
+		}
+
+		if n != uint64Values[i] {
+			t.Fatalf("conversion of 0x%x resulted in %d instead of %d", c, n, uint64Values[i])
+		}
+
+		if l != len(c) {
+			t.Fatalf("conversion of 0x%x used %d bytes instead of %d", c, l, len(c))
+		}
+	}
+}
+
+// TestInvalidUInt64Bytes tests the conversion of invalid 64-bit byte slices.
+func TestInvalidUInt64Bytes(t *testing.T) {
+	invalid := []struct {
+		c   []byte
+		err error
+	}{
+		{[]byte{}, ErrSliceTooSmall},
+		{[]byte{0x10}, ErrSliceTooSmall},
+		{[]byte{0x80, 0x00}, ErrSliceTooSmall},
+		{[]byte{0x90, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00}, ErrInvalidLength},
+		{[]byte{0xff}, ErrInvalidLength},
+		{[]byte{0x80, 0xef, 0xef, 0xef, 0xef, 0xef, 0xef, 0xef, 0xf0}, ErrIntIsTooLarge},
+		{[]byte{0x81, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00}, ErrIntIsTooLarge},
+		{[]byte{0x8f, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}, ErrIntIsTooLarge},
+	}
+
+	for _, ic := range invalid {
+		_, _, err := ToUInt64(ic.c)
+		if !errors.Is(err, ic.err) {
+			t.Fatalf("conversion of 0x%x resulted in error %v instead of %v", ic.c, err, ic.err)
+		}
+
+		_, err = NewReader(bytes.NewReader(ic.c)).ReadUInt64()
+		if err == nil {
+			t.Fatalf("reading 0x%x did not result in an error", ic.c)
+		}
+	}
+}
+
+// TestInt64ZigZag tests that small absolute values result in short representations.
+func TestInt64ZigZag(t *testing.T) {
+	expectedLengths := []int{1, 1, 1, 1, 1, 1, 1, 2, 2, MaxLength64, MaxLength64}
+	for i, n := range int64Values {
+		c := AppendInt64(nil, n)
+		if len(c) != expectedLengths[i] {
+			t.Fatalf("conversion of %d resulted in %d bytes instead of %d", n, len(c), expectedLengths[i])
+		}
+
+		v, l, err := ToInt64(c)
+		if err != nil {
+			t.Fatalf("conversion of 0x%x resulted in error: %v", c, err)
+		}
+
+		if v != n || l != len(c) {
+			t.Fatalf("conversion of 0x%x resulted in %d (%d bytes) instead of %d (%d bytes)", c, v, l, n, len(c))
+		}
+	}
+}
+
+// TestStream64RoundTrip tests writing and reading a stream of 64-bit integers.
+func TestStream64RoundTrip(t *testing.T) {
+	var buffer bytes.Buffer
+	w := NewWriter(&buffer)
+	for _, v := range uint64Values {
+		err := w.WriteUInt64(v)
+		if err != nil {
+			t.Fatalf("error writing %d: %v", v, err)
+		}
+	}
+	for _, v := range int64Values {
+		err := w.WriteInt64(v)
+		if err != nil {
+			t.Fatalf("error writing %d: %v", v, err)
+		}
+	}
+
+	r := NewReader(bytes.NewReader(buffer.Bytes()))
+	for _, expected := range uint64Values {
+		v, err := r.ReadUInt64()
+		if err != nil {
+			t.Fatalf("error reading %d: %v", expected, err)
+		}
+
+		if v != expected {
+			t.Fatalf("read %d instead of %d", v, expected)
+		}
+	}
+	for _, expected := range int64Values {
+		v, err := r.ReadInt64()
+		if err != nil {
+			t.Fatalf("error reading %d: %v", expected, err)
+		}
+
+		if v != expected {
+			t.Fatalf("read %d instead of %d", v, expected)
+		}
+	}
+
+	_, err := r.ReadUInt64()
+	if !errors.Is(err, io.EOF) {
+		t.Fatalf("reading after the last value resulted in error %v instead of %v", err, io.EOF)
+	}
+}
+
+// ******** Fuzz functions ********
+
+// FuzzUInt32RoundTrip tests that every allowed 32-bit value survives a round trip.
+func FuzzUInt32RoundTrip(f *testing.F) {
+	for _, n := range intValues {
+		f.Add(uint32(n))
+	}
+
+	f.Fuzz(func(t *testing.T, v uint32) {
+		if v > MaxAllowedInt {
+			v %= MaxAllowedInt + 1
+		}
+
+		c := AppendUInt32(nil, v)
+		n, l, err := ToUInt32(c)
+		if err != nil {
+			t.Fatalf("conversion of 0x%x resulted in error: %v", c, err)
+		}
+
+		if n != v || l != len(c) {
+			t.Fatalf("round trip of %d resulted in %d (%d of %d bytes)", v, n, l, len(c))
+		}
+	})
+}
+
+// FuzzUInt32Canonical tests that every byte slice that is accepted is the only representation of its value.
+func FuzzUInt32Canonical(f *testing.F) {
+	for _, c := range byteSliceValues {
+		f.Add(c)
+	}
+
+	f.Fuzz(func(t *testing.T, c []byte) {
+		n, l, err := ToUInt32(c)
+		if err != nil {
+			return
+		}
+
+		expected := c[:l]
+		actual := AppendUInt32(nil, n)
+		if !bytes.Equal(actual, expected) {
+			t.Fatalf("0x%x is converted to %d, which is converted to 0x%x", expected, n, actual)
+		}
+	})
+}
+
+// FuzzUInt64RoundTrip tests that every 64-bit value survives a round trip.
+func FuzzUInt64RoundTrip(f *testing.F) {
+	for _, n := range uint64Values {
+		f.Add(n)
+	}
+
+	f.Fuzz(func(t *testing.T, v uint64) {
+		c := AppendUInt64(nil, v)
+		n, l, err := ToUInt64(c)
+		if err != nil {
+			t.Fatalf("conversion of 0x%x resulted in error: %v", c, err)
+		}
+
+		if n != v || l != len(c) {
+			t.Fatalf("round trip of %d resulted in %d (%d of %d bytes)", v, n, l, len(c))
+		}
+	})
+}
+
+// FuzzUInt64Canonical tests that every byte slice that is accepted is the only representation of its value.
+func FuzzUInt64Canonical(f *testing.F) {
+	for _, c := range uint64ByteSliceValues {
+		f.Add(c)
+	}
+
+	f.Fuzz(func(t *testing.T, c []byte) {
+		n, l, err := ToUInt64(c)
+		if err != nil {
+			return
+		}
+
+		actual := AppendUInt64(nil, n)
+		if !bytes.Equal(actual, c[:l]) {
+			t.Fatalf("0x%x is converted to %d, which is converted to 0x%x", c[:l], n, actual)
+		}
+
+		r, err := NewReader(bytes.NewReader(c)).ReadUInt64()
+		if err != nil || r != n {
+			t.Fatalf("reading 0x%x resulted in %d and error %v instead of %d", c[:l], r, err, n)
+		}
+	})
+}
+
+// FuzzInt64RoundTrip tests that every signed 64-bit value survives a round trip.
+func FuzzInt64RoundTrip(f *testing.F) {
+	for _, n := range int64Values {
+		f.Add(n)
+	}
+
+	f.Fuzz(func(t *testing.T, v int64) {
+		c := AppendInt64(nil, v)
+		n, l, err := ToInt64(c)
+		if err != nil {
+			t.Fatalf("conversion of 0x%x resulted in error: %v", c, err)
+		}
+
+		if n != v || l != len(c) {
+			t.Fatalf("round trip of %d resulted in %d (%d of %d bytes)", v, n, l, len(c))
+		}
+	})
+}
@@ -20,11 +20,12 @@
 //
 // Author: Frank Schwab
 //
-// Version: 1.1.0
+// Version: 1.2.0
 //
 // Change history:
 //    2023-01-21: V1.0.0: Created.
 //    2025-01-05: V1.1.0: use math/rand/v2.
+//    2026-10-18: V1.2.0: Add tests for AppendUInt32, PutUInt32 and concurrent use.
 //
 
 package compressedinteger
@@ -34,6 +35,7 @@ import (
 	"errors"
 	"math"
 	"math/rand/v2"
+	"sync"
 	"testing"
 )
 
@@ -179,6 +181,113 @@ func TestInvalidBytes(t *testing.T) {
 	expectByteSliceConversionError(t, []byte{0xff, 0x00, 0x00}, ErrSliceTooSmall)
 }
 
+// TestBoundariesAppendUInt32 tests all integer boundary cases when appending.
+func TestBoundariesAppendUInt32(t *testing.T) {
+	prefix := []byte{0xaa}
+	for i, n := range intValues {
+		c := AppendUInt32(prefix, uint32(n))
+		if !bytes.Equal(c[:1], prefix) || !bytes.Equal(c[1:], byteSliceValues[i]) {
+			t.Fatalf("appending %d (0x%x) resulted in 0x%x instead of 0x%x", n, n, c[1:], byteSliceValues[i])
+		}
+	}
+}
+
+// TestBoundariesPutUInt32 tests all integer boundary cases when putting.
+func TestBoundariesPutUInt32(t *testing.T) {
+	for i, n := range intValues {
+		var buffer [MaxLength]byte
+		l := PutUInt32(buffer[:], uint32(n))
+		if !bytes.Equal(buffer[:l], byteSliceValues[i]) {
+			t.Fatalf("putting %d (0x%x) resulted in 0x%x instead of 0x%x", n, n, buffer[:l], byteSliceValues[i])
+		}
+	}
+}
+
+// TestPutUInt32SliceTooSmall tests if putting into a slice that is too small panics.
+func TestPutUInt32SliceTooSmall(t *testing.T) {
+	defer func() {
+		if r := recover(); r != ErrSliceTooSmall {
+			t.Fatalf("putting into a slice that is too small panicked with %v instead of %v", r, ErrSliceTooSmall)
+		}
+	}()
+
+	PutUInt32(make([]byte, 1, MaxLength), 0x4040)
+}
+
+// TestAppendUInt32TooLarge tests if appending a value that is too large panics.
+func TestAppendUInt32TooLarge(t *testing.T) {
+	defer func() {
+		if r := recover(); r != ErrIntIsTooLarge {
+			t.Fatalf("appending a value that is too large panicked with %v instead of %v", r, ErrIntIsTooLarge)
+		}
+	}()
+
+	AppendUInt32(nil, MaxAllowedInt+1)
+}
+
+// TestAppendUInt32NoAllocation tests that appending to a slice with enough capacity does not allocate.
+func TestAppendUInt32NoAllocation(t *testing.T) {
+	buffer := make([]byte, 0, MaxLength)
+	allocs := testing.AllocsPerRun(100, func() {
+		buffer = AppendUInt32(buffer[:0], MaxAllowedInt)
+		_ = PutUInt32(buffer[:MaxLength], MaxAllowedInt)
+	})
+
+	if allocs != 0 {
+		t.Fatalf("appending and putting allocated %v times", allocs)
+	}
+}
+
+// TestConcurrentFromUInt32 tests that FromUInt32 can be used from many goroutines at the same time.
+// Run it with the race detector ("go test -race") to detect data races.
+func TestConcurrentFromUInt32(t *testing.T) {
+	const goroutineCount = 16
+	const conversionCount = 1_000
+
+	var wg sync.WaitGroup
+	errs := make(chan error, goroutineCount)
+
+	for g := range goroutineCount {
+		wg.Add(1)
+		go func(offset uint32) {
+			defer wg.Done()
+
+			results := make([][]byte, conversionCount)
+			for i := range results {
+				c, err := FromUInt32((offset + uint32(i)*0x10101) % MaxAllowedInt)
+				if err != nil {
+					errs <- err
+					return
+				}
+
+				results[i] = c
+			}
+
+			// Check the results only after all conversions, so overwritten results would be detected.
+			for i, c := range results {
+				expected := (offset + uint32(i)*0x10101) % MaxAllowedInt
+				n, _, err := ToUInt32(c)
+				if err != nil {
+					errs <- err
+					return
+				}
+
+				if n != expected {
+					errs <- errors.New("concurrent conversion returned a wrong result")
+					return
+				}
+			}
+		}(uint32(g) * 0x1234567)
+	}
+
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		t.Fatal(err)
+	}
+}
+
 // ******** Benchmark functions ********
 
 // BenchmarkFrom1ByteInteger benchmarks conversions for integers that generate 1 byte compressed representations
@@ -245,6 +354,15 @@ func BenchmarkFrom4ByteSlice(b *testing.B) {
 	}
 }
 
+// BenchmarkAppend4ByteInteger benchmarks appending integers that generate 4 bytes compressed representations
+func BenchmarkAppend4ByteInteger(b *testing.B) {
+	i := uint32(intValues[7])
+	buffer := make([]byte, 0, MaxLength)
+	for n := 0; n < b.N; n++ {
+		buffer = AppendUInt32(buffer[:0], i)
+	}
+}
+
 // ******** Private methods ********
 
 // expectIntegerConversionError tests if a given integer conversion return the expected error
@@ -0,0 +1,129 @@
+//
+// SPDX-FileCopyrightText: Copyright 2026 Frank Schwab
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileType: SOURCE
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+//
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Author: Frank Schwab
+//
+// Version: 1.1.0
+//
+// Change history:
+//    2026-10-18: V1.0.0: Created.
+//    2026-10-18: V1.1.0: Add 64-bit and signed integers.
+//
+
+package compressedinteger
+
+import (
+	"errors"
+	"fmt"
+	"io"
+)
+
+// ******** Public types ********
+
+// Reader reads compressed integers from a byte stream.
+type Reader struct {
+	source io.ByteReader
+}
+
+// ******** Public constants ********
+
+// ErrValueTruncated is returned when the stream ends in the middle of a compressed integer.
+var ErrValueTruncated = errors.New(`compressed integer is truncated`)
+
+// ******** Public creation functions ********
+
+// NewReader creates a new reader for compressed integers that reads from source.
+func NewReader(source io.ByteReader) *Reader {
+	return &Reader{source: source}
+}
+
+// ******** Public functions ********
+
+// ReadUInt32 reads the next compressed integer.
+// It returns [io.EOF] if the stream ends before the first byte of an integer,
+// and an error that wraps [ErrValueTruncated] if the stream ends in the middle of an integer.
+func (r *Reader) ReadUInt32() (uint32, error) {
+	b, err := r.source.ReadByte()
+	if err != nil {
+		return 0, err
+	}
+
+	expectedLength := ExpectedLength(b)
+
+	// Decompress the bytes
+	t := uint32(b & noLengthMaskForByte)
+
+	for i := 1; i < expectedLength; i++ {
+		b, err = r.source.ReadByte()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return 0, fmt.Errorf(`%w: got %d of %d bytes`, ErrValueTruncated, i, expectedLength)
+			}
+
+			return 0, err
+		}
+
+		t = ((t << 8) | uint32(b)) + offsetValue
+	}
+
+	return t, nil
+}
+
+// ReadUInt64 reads the next compressed 64-bit integer.
+// It returns the same errors as [Reader.ReadUInt32] and additionally [ErrInvalidLength] or
+// [ErrIntIsTooLarge], if the bytes are not a valid 64-bit representation.
+func (r *Reader) ReadUInt64() (uint64, error) {
+	b, err := r.source.ReadByte()
+	if err != nil {
+		return 0, err
+	}
+
+	expectedLength := ExpectedLength64(b)
+	if expectedLength > MaxLength64 {
+		return 0, ErrInvalidLength
+	}
+
+	// Decompress the bytes
+	t := uint64(b & noLengthMaskForByte64)
+
+	for i := 1; i < expectedLength; i++ {
+		b, err = r.source.ReadByte()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return 0, fmt.Errorf(`%w: got %d of %d bytes`, ErrValueTruncated, i, expectedLength)
+			}
+
+			return 0, err
+		}
+
+		t, err = addByte64(t, b)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	return t, nil
+}
+
+// ReadInt64 reads the next zigzag compressed signed 64-bit integer.
+func (r *Reader) ReadInt64() (int64, error) {
+	u, err := r.ReadUInt64()
+	return zigzagDecode(u), err
+}
@@ -0,0 +1,116 @@
+//
+// SPDX-FileCopyrightText: Copyright 2026 Frank Schwab
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileType: SOURCE
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+//
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Author: Frank Schwab
+//
+// Version: 1.0.0
+//
+// Change history:
+//    2026-10-18: V1.0.0: Created.
+//
+
+package compressedinteger
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"math/rand/v2"
+	"testing"
+)
+
+// ******** Test functions ********
+
+// TestStreamRoundTrip tests writing and reading a stream of compressed integers.
+func TestStreamRoundTrip(t *testing.T) {
+	values := make([]uint32, 0, len(intValues)+100)
+	for _, n := range intValues {
+		values = append(values, uint32(n))
+	}
+	for range 100 {
+		values = append(values, rand.Uint32N(MaxAllowedInt+1))
+	}
+
+	var buffer bytes.Buffer
+	w := NewWriter(&buffer)
+	for _, v := range values {
+		err := w.WriteUInt32(v)
+		if err != nil {
+			t.Fatalf("error writing %d: %v", v, err)
+		}
+	}
+
+	r := NewReader(bytes.NewReader(buffer.Bytes()))
+	for _, expected := range values {
+		v, err := r.ReadUInt32()
+		if err != nil {
+			t.Fatalf("error reading %d: %v", expected, err)
+		}
+
+		if v != expected {
+			t.Fatalf("read %d instead of %d", v, expected)
+		}
+	}
+
+	_, err := r.ReadUInt32()
+	if !errors.Is(err, io.EOF) {
+		t.Fatalf("reading after the last value resulted in error %v instead of %v", err, io.EOF)
+	}
+}
+
+// TestStreamBoundaries tests that the stream format is the same as the byte slice format.
+func TestStreamBoundaries(t *testing.T) {
+	for i, n := range intValues {
+		var buffer bytes.Buffer
+		err := NewWriter(&buffer).WriteUInt32(uint32(n))
+		if err != nil {
+			t.Fatalf("error writing %d: %v", n, err)
+		}
+
+		if !bytes.Equal(buffer.Bytes(), byteSliceValues[i]) {
+			t.Fatalf("writing %d (0x%x) resulted in 0x%x instead of 0x%x", n, n, buffer.Bytes(), byteSliceValues[i])
+		}
+	}
+}
+
+// TestStreamWriteTooLarge tests that writing a value that is too large fails.
+func TestStreamWriteTooLarge(t *testing.T) {
+	var buffer bytes.Buffer
+	err := NewWriter(&buffer).WriteUInt32(MaxAllowedInt + 1)
+	if !errors.Is(err, ErrIntIsTooLarge) {
+		t.Fatalf("writing a value that is too large resulted in error %v instead of %v", err, ErrIntIsTooLarge)
+	}
+
+	if buffer.Len() != 0 {
+		t.Fatalf("writing a value that is too large wrote 0x%x", buffer.Bytes())
+	}
+}
+
+// TestStreamTruncated tests that truncated values are reported as such.
+func TestStreamTruncated(t *testing.T) {
+	for _, c := range byteSliceValues {
+		for l := 1; l < len(c); l++ {
+			_, err := NewReader(bytes.NewReader(c[:l])).ReadUInt32()
+			if !errors.Is(err, ErrValueTruncated) {
+				t.Fatalf("reading truncated bytes 0x%x resulted in error %v instead of %v", c[:l], err, ErrValueTruncated)
+			}
+		}
+	}
+}
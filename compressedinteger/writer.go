@@ -0,0 +1,75 @@
+//
+// SPDX-FileCopyrightText: Copyright 2026 Frank Schwab
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileType: SOURCE
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+//
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Author: Frank Schwab
+//
+// Version: 1.1.0
+//
+// Change history:
+//    2026-10-18: V1.0.0: Created.
+//    2026-10-18: V1.1.0: Add 64-bit and signed integers.
+//
+
+package compressedinteger
+
+import "io"
+
+// ******** Public types ********
+
+// Writer writes compressed integers to a stream.
+type Writer struct {
+	destination io.Writer
+	buffer      [MaxLength64]byte
+}
+
+// ******** Public creation functions ********
+
+// NewWriter creates a new writer for compressed integers that writes to destination.
+func NewWriter(destination io.Writer) *Writer {
+	return &Writer{destination: destination}
+}
+
+// ******** Public functions ********
+
+// WriteUInt32 writes the compressed representation of v.
+// It returns [ErrIntIsTooLarge], if v is larger than [MaxAllowedInt].
+func (w *Writer) WriteUInt32(v uint32) error {
+	if v > MaxAllowedInt {
+		return ErrIntIsTooLarge
+	}
+
+	n := PutUInt32(w.buffer[:], v)
+	_, err := w.destination.Write(w.buffer[:n])
+
+	return err
+}
+
+// WriteUInt64 writes the compressed 64-bit representation of v.
+func (w *Writer) WriteUInt64(v uint64) error {
+	n := PutUInt64(w.buffer[:], v)
+	_, err := w.destination.Write(w.buffer[:n])
+
+	return err
+}
+
+// WriteInt64 writes the zigzag compressed 64-bit representation of v.
+func (w *Writer) WriteInt64(v int64) error {
+	return w.WriteUInt64(zigzagEncode(v))
+}
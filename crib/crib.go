@@ -0,0 +1,178 @@
+//
+// SPDX-FileCopyrightText: Copyright 2026 Frank Schwab
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileType: SOURCE
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+//
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Author: Frank Schwab
+//
+// Version: 1.0.0
+//
+// Change history:
+//    2026-10-18: V1.0.0: Created.
+//
+
+// Package crib implements a known-plaintext attack on homophonic substitutions.
+// A crib, i.e. a word that is supposed to occur in the clear text, is slid over the ciphertext.
+// Each position where no substitution character would have to stand for two different letters
+// is a possible alignment of the crib and yields a partial key.
+package crib
+
+import (
+	"cmp"
+	"errors"
+	"homophone/analysis"
+	"slices"
+	"strings"
+)
+
+// ******** Public types ********
+
+// Alignment is a position of the crib in the ciphertext that is consistent with a homophonic substitution.
+type Alignment struct {
+	// Position is the index of the first substitution character of the alignment
+	// among the substitution characters of the ciphertext.
+	Position int
+
+	// Ciphertext contains the substitution characters that the crib covers.
+	Ciphertext string
+
+	// Crib is the normalized crib.
+	Crib string
+
+	// Repeats is the number of crib letters whose substitution character already occurs
+	// before in the alignment. The more repeats an alignment has, the less likely it is by chance.
+	Repeats int
+}
+
+// ******** Public constants ********
+
+// ErrEmptyCrib is returned when the crib contains no letters.
+var ErrEmptyCrib = errors.New(`crib contains no letters`)
+
+// ******** Public functions ********
+
+// NormalizeCrib returns the letters of a crib in upper case. All other characters are removed.
+func NormalizeCrib(crib string) string {
+	var result strings.Builder
+	for _, b := range []byte(strings.ToUpper(crib)) {
+		if b >= 'A' && b <= 'Z' {
+			result.WriteByte(b)
+		}
+	}
+
+	return result.String()
+}
+
+// Symbols returns the substitution characters of a ciphertext. All other characters are removed.
+func Symbols(ciphertext []byte) []byte {
+	result := make([]byte, 0, len(ciphertext))
+	for _, b := range ciphertext {
+		if strings.IndexByte(analysis.SubstitutionAlphabet, b) >= 0 {
+			result = append(result, b)
+		}
+	}
+
+	return result
+}
+
+// Find slides the crib over the substitution characters of the ciphertext and returns all
+// consistent alignments and the number of positions that have been tried.
+// The alignments are sorted by decreasing number of repeats and then by position.
+func Find(ciphertext []byte, crib string) ([]Alignment, int, error) {
+	normalized := NormalizeCrib(crib)
+	if len(normalized) == 0 {
+		return nil, 0, ErrEmptyCrib
+	}
+
+	symbols := Symbols(ciphertext)
+	positionCount := max(len(symbols)-len(normalized)+1, 0)
+
+	result := make([]Alignment, 0)
+	for position := range positionCount {
+		window := symbols[position : position+len(normalized)]
+		repeats, ok := checkAlignment(window, normalized)
+		if !ok {
+			continue
+		}
+
+		result = append(result, Alignment{
+			Position:   position,
+			Ciphertext: string(window),
+			Crib:       normalized,
+			Repeats:    repeats,
+		})
+	}
+
+	slices.SortStableFunc(result, func(a, b Alignment) int {
+		return cmp.Compare(b.Repeats, a.Repeats)
+	})
+
+	return result, positionCount, nil
+}
+
+// ******** Public type functions ********
+
+// Homophones returns the substitution characters of each letter A-Z that the alignment implies.
+// The substitution characters of a letter are in the order of their first occurrence.
+func (a Alignment) Homophones() [][]byte {
+	result := make([][]byte, 'Z'-'A'+1)
+	for i := range len(a.Crib) {
+		index := a.Crib[i] - 'A'
+		symbol := a.Ciphertext[i]
+		if slices.Index(result[index], symbol) < 0 {
+			result[index] = append(result[index], symbol)
+		}
+	}
+
+	return result
+}
+
+// Letter returns the letter that the alignment implies for a substitution character,
+// or 0 if the alignment does not contain the substitution character.
+func (a Alignment) Letter(symbol byte) byte {
+	index := strings.IndexByte(a.Ciphertext, symbol)
+	if index < 0 {
+		return 0
+	}
+
+	return a.Crib[index]
+}
+
+// ******** Private functions ********
+
+// checkAlignment checks that no substitution character of window stands for two different letters of crib.
+// It returns the number of repeated substitution characters and whether the alignment is consistent.
+func checkAlignment(window []byte, crib string) (int, bool) {
+	var letters [256]byte
+	repeats := 0
+	for i, symbol := range window {
+		letter := crib[i]
+		switch letters[symbol] {
+		case 0:
+			letters[symbol] = letter
+
+		case letter:
+			repeats++
+
+		default:
+			return 0, false
+		}
+	}
+
+	return repeats, true
+}
@@ -0,0 +1,119 @@
+//
+// SPDX-FileCopyrightText: Copyright 2026 Frank Schwab
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileType: SOURCE
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+//
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Author: Frank Schwab
+//
+// Version: 1.0.0
+//
+// Change history:
+//    2026-10-18: V1.0.0: Created.
+//
+
+package crib
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+// ******** Test functions ********
+
+func TestFindRejectsInconsistentPositions(t *testing.T) {
+	// The substitution characters are "xyXabzXz". Only "bzXz" is inconsistent, as 'z' would stand for 'E' and 'D'.
+	alignments, positionCount, err := Find([]byte(`xy Xabz, Xz`), `dead`)
+	if err != nil {
+		t.Fatalf(`Error finding crib: %v`, err)
+	}
+
+	if positionCount != 5 {
+		t.Fatalf(`Expected 5 positions, got %d`, positionCount)
+	}
+
+	if len(alignments) != 4 {
+		t.Fatalf(`Expected 4 alignments, got %d: %v`, len(alignments), alignments)
+	}
+
+	for i, a := range alignments {
+		if a.Position != i || a.Crib != `DEAD` {
+			t.Fatalf(`Unexpected alignment %d: %+v`, i, a)
+		}
+	}
+}
+
+func TestFindRepeats(t *testing.T) {
+	alignments, _, err := Find([]byte(`QQ abca QQ`), `DEAD`)
+	if err != nil {
+		t.Fatalf(`Error finding crib: %v`, err)
+	}
+
+	if len(alignments) == 0 {
+		t.Fatal(`No alignments found`)
+	}
+
+	best := alignments[0]
+	if best.Position != 2 || best.Ciphertext != `abca` || best.Repeats != 1 {
+		t.Fatalf(`Unexpected best alignment: %+v`, best)
+	}
+
+	for _, a := range alignments {
+		if a.Ciphertext == `QQab` {
+			t.Fatalf(`Inconsistent alignment found: %+v`, a)
+		}
+	}
+}
+
+func TestAlignmentHomophones(t *testing.T) {
+	a := Alignment{Ciphertext: `XaqBzX`, Crib: `DEDEAD`}
+
+	homophones := a.Homophones()
+	if len(homophones) != 26 {
+		t.Fatalf(`Expected 26 lists, got %d`, len(homophones))
+	}
+
+	expected := map[byte]string{'A': `z`, 'D': `Xq`, 'E': `aB`}
+	for i, list := range homophones {
+		if !slices.Equal(list, []byte(expected[byte(i+'A')])) {
+			t.Fatalf(`Expected '%s' for '%c', got '%s'`, expected[byte(i+'A')], i+'A', list)
+		}
+	}
+
+	if a.Letter('q') != 'D' || a.Letter('y') != 0 {
+		t.Fatalf(`Unexpected letters: '%c' and %d`, a.Letter('q'), a.Letter('y'))
+	}
+}
+
+func TestFindEmptyCrib(t *testing.T) {
+	_, _, err := Find([]byte(`abc`), `1 2 3`)
+	if !errors.Is(err, ErrEmptyCrib) {
+		t.Fatalf(`Expected error '%v', got '%v'`, ErrEmptyCrib, err)
+	}
+}
+
+func TestFindCribLongerThanCiphertext(t *testing.T) {
+	alignments, positionCount, err := Find([]byte(`ab`), `Dear Sir`)
+	if err != nil {
+		t.Fatalf(`Error finding crib: %v`, err)
+	}
+
+	if positionCount != 0 || len(alignments) != 0 {
+		t.Fatalf(`Expected no positions, got %d positions and %d alignments`, positionCount, len(alignments))
+	}
+}
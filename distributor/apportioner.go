@@ -0,0 +1,165 @@
+//
+// SPDX-FileCopyrightText: Copyright 2026 Frank Schwab
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileType: SOURCE
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+//
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Author: Frank Schwab
+//
+// Version: 1.1.0
+//
+// Change history:
+//    2026-10-18: V1.0.0: Created.
+//    2026-10-18: V1.1.0: Add minimum and maximum number of seats.
+//
+
+package distributor
+
+import (
+	"errors"
+	"fmt"
+	"homophone/nametable"
+	"math"
+	"slices"
+)
+
+// ******** Public types ********
+
+// Apportioner is a method that distributes a number of seats to counts in proportion to the counts.
+type Apportioner interface {
+	// Apportion distributes wantedSeatCount seats to the counts.
+	// The result contains the number of seats for each count.
+	Apportion(counts []uint, wantedSeatCount uint) ([]uint, error)
+
+	// ApportionBounded distributes wantedSeatCount seats to the counts, so that each count i
+	// gets at least minSeats[i] and at most maxSeats[i] seats.
+	// A nil minSeats means no minimum and a nil maxSeats means no maximum.
+	// Counts that are zero get exactly their minimum number of seats.
+	ApportionBounded(counts []uint, wantedSeatCount uint, minSeats []uint, maxSeats []uint) ([]uint, error)
+
+	// Name returns the name of the method.
+	Name() string
+}
+
+// ******** Public constants ********
+
+// ErrNoCounts is returned when seats are to be distributed, but all counts are zero.
+var ErrNoCounts = errors.New(`there are no counts to distribute seats to`)
+
+// ErrInvalidBounds is returned when the minimum and maximum numbers of seats cannot be satisfied.
+var ErrInvalidBounds = errors.New(`minimum and maximum numbers of seats cannot be satisfied`)
+
+// ErrUnknownMethod is returned when an apportionment method is not known.
+var ErrUnknownMethod = errors.New(`unknown apportionment method`)
+
+// ******** Private variables ********
+
+// apportioners contains all known apportionment methods. The first one is the default method.
+var apportioners = nametable.FromValues([]Apportioner{
+	SainteLague{},
+	DHondt{},
+	HareNiemeyer{},
+	HuntingtonHill{},
+	Adams{},
+}, Apportioner.Name)
+
+// ******** Public functions ********
+
+// DefaultApportioner returns the default apportionment method.
+func DefaultApportioner() Apportioner {
+	return apportioners[0].Value
+}
+
+// ParseMethod returns the apportionment method with the given name.
+func ParseMethod(name string) (Apportioner, error) {
+	return apportioners.Parse(name, ErrUnknownMethod)
+}
+
+// MethodNames returns the names of all known apportionment methods.
+func MethodNames() []string {
+	return apportioners.Names()
+}
+
+// ******** Private functions ********
+
+// checkBounds checks that the minimum and maximum numbers of seats can be satisfied.
+func checkBounds(counts []uint, wantedSeatCount uint, minSeats []uint, maxSeats []uint) error {
+	if (minSeats != nil && len(minSeats) != len(counts)) || (maxSeats != nil && len(maxSeats) != len(counts)) {
+		return fmt.Errorf(`%w: there are %d counts, but %d minimums and %d maximums`, ErrInvalidBounds, len(counts), len(minSeats), len(maxSeats))
+	}
+
+	minSum := uint(0)
+	maxSum := uint(0)
+	for i := range counts {
+		lower := minOf(minSeats, i)
+		upper := maxOf(maxSeats, i)
+		if lower > upper {
+			return fmt.Errorf(`%w: minimum %d of count %d is larger than its maximum %d`, ErrInvalidBounds, lower, i, upper)
+		}
+
+		minSum += lower
+		maxSum += min(upper, wantedSeatCount-min(maxSum, wantedSeatCount))
+	}
+
+	if minSum > wantedSeatCount {
+		return fmt.Errorf(`%w: the minimums need %d seats, but there are only %d`, ErrInvalidBounds, minSum, wantedSeatCount)
+	}
+
+	if maxSum < wantedSeatCount {
+		return fmt.Errorf(`%w: the maximums allow only %d seats, but there are %d`, ErrInvalidBounds, maxSum, wantedSeatCount)
+	}
+
+	return nil
+}
+
+// initialSeats returns the seats every count gets, i.e. the minimum seats.
+func initialSeats(counts []uint, minSeats []uint) []uint {
+	if minSeats == nil {
+		return make([]uint, len(counts))
+	}
+
+	return slices.Clone(minSeats)
+}
+
+// minOf returns the minimum number of seats of count i.
+func minOf(minSeats []uint, i int) uint {
+	if minSeats == nil {
+		return 0
+	}
+
+	return minSeats[i]
+}
+
+// maxOf returns the maximum number of seats of count i.
+func maxOf(maxSeats []uint, i int) uint {
+	if maxSeats == nil {
+		return math.MaxUint
+	}
+
+	return maxSeats[i]
+}
+
+// errNoSeatsLeft returns the error for seats that could not be given to any count.
+func errNoSeatsLeft(counts []uint) error {
+	for _, count := range counts {
+		if count != 0 {
+			return ErrInvalidBounds
+		}
+	}
+
+	return ErrNoCounts
+}
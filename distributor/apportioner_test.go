@@ -0,0 +1,295 @@
+//
+// SPDX-FileCopyrightText: Copyright 2026 Frank Schwab
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileType: SOURCE
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+//
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Author: Frank Schwab
+//
+// Version: 1.1.0
+//
+// Change history:
+//    2026-10-18: V1.0.0: Created.
+//    2026-10-18: V1.1.0: Add tests for minimum and maximum number of seats.
+//
+
+package distributor_test
+
+import (
+	"errors"
+	"homophone/distributor"
+	"math/rand/v2"
+	"slices"
+	"testing"
+)
+
+// ******** Test functions ********
+
+func TestApportioners(t *testing.T) {
+	tests := []struct {
+		counts   []uint
+		seats    uint
+		expected map[string][]uint
+	}{
+		{
+			counts: []uint{100_000, 80_000, 30_000, 20_000},
+			seats:  8,
+			expected: map[string][]uint{
+				`sainte-lague`:    {3, 3, 1, 1},
+				`dhondt`:          {4, 3, 1, 0},
+				`hare-niemeyer`:   {3, 3, 1, 1},
+				`huntington-hill`: {3, 3, 1, 1},
+				`adams`:           {3, 3, 1, 1},
+			},
+		},
+		{
+			counts: []uint{749_216, 719_274, 277_173, 172_002, 119_964, 104_888},
+			seats:  119,
+			expected: map[string][]uint{
+				`sainte-lague`:    {41, 40, 15, 10, 7, 6},
+				`dhondt`:          {42, 41, 15, 9, 6, 6},
+				`hare-niemeyer`:   {42, 40, 15, 9, 7, 6},
+				`huntington-hill`: {41, 40, 15, 10, 7, 6},
+				`adams`:           {41, 39, 16, 10, 7, 6},
+			},
+		},
+		{
+			counts: []uint{5_000, 3_000, 1_500, 400, 100},
+			seats:  10,
+			expected: map[string][]uint{
+				`sainte-lague`:    {5, 3, 2, 0, 0},
+				`dhondt`:          {6, 3, 1, 0, 0},
+				`hare-niemeyer`:   {5, 3, 2, 0, 0},
+				`huntington-hill`: {4, 3, 1, 1, 1},
+				`adams`:           {4, 3, 1, 1, 1},
+			},
+		},
+	}
+
+	for _, name := range distributor.MethodNames() {
+		a, err := distributor.ParseMethod(name)
+		if err != nil {
+			t.Fatalf(`Error parsing method '%s': %v`, name, err)
+		}
+
+		for _, tc := range tests {
+			var seats []uint
+			seats, err = a.Apportion(tc.counts, tc.seats)
+			if err != nil {
+				t.Fatalf(`%s: Error distributing seats: %v`, name, err)
+			}
+
+			if !slices.Equal(tc.expected[name], seats) {
+				t.Errorf(`%s: `+formatExpectedGot, name, tc.expected[name], seats)
+			}
+		}
+	}
+}
+
+func TestApportionersZeroCounts(t *testing.T) {
+	counts := []uint{0, 3, 0, 1, 0}
+
+	for _, name := range distributor.MethodNames() {
+		a, _ := distributor.ParseMethod(name)
+
+		seats, err := a.Apportion(counts, 20)
+		if err != nil {
+			t.Fatalf(`%s: Error distributing seats: %v`, name, err)
+		}
+
+		if seats[0] != 0 || seats[2] != 0 || seats[4] != 0 {
+			t.Errorf(`%s: Zero counts got seats: %v`, name, seats)
+		}
+
+		if total(seats) != 20 {
+			t.Errorf(`%s: Expected 20 seats, got %v`, name, seats)
+		}
+
+		_, err = a.Apportion([]uint{0, 0}, 5)
+		if !errors.Is(err, distributor.ErrNoCounts) {
+			t.Errorf(`%s: Expected error %v, got %v`, name, distributor.ErrNoCounts, err)
+		}
+
+		seats, err = a.Apportion(counts, 0)
+		if err != nil || total(seats) != 0 {
+			t.Errorf(`%s: Distributing no seats resulted in %v and error %v`, name, seats, err)
+		}
+	}
+}
+
+func TestApportionersManySeats(t *testing.T) {
+	// The squared divisors of these seat numbers do not fit into 32 bits.
+	counts := []uint{3, 1}
+
+	for _, name := range distributor.MethodNames() {
+		a, _ := distributor.ParseMethod(name)
+
+		seats, err := a.Apportion(counts, 100_000)
+		if err != nil {
+			t.Fatalf(`%s: Error distributing seats: %v`, name, err)
+		}
+
+		if seats[0] != 75_000 || seats[1] != 25_000 {
+			t.Errorf(`%s: Expected [75000 25000], got %v`, name, seats)
+		}
+	}
+}
+
+func TestApportionersBounded(t *testing.T) {
+	counts := []uint{100_000, 80_000, 30_000, 20_000, 0}
+	minSeats := []uint{0, 0, 0, 3, 1}
+	maxSeats := []uint{2, 8, 8, 8, 1}
+
+	for _, name := range distributor.MethodNames() {
+		a, _ := distributor.ParseMethod(name)
+
+		seats, err := a.ApportionBounded(counts, 12, minSeats, maxSeats)
+		if err != nil {
+			t.Fatalf(`%s: Error distributing seats: %v`, name, err)
+		}
+
+		expectBounds(t, name, seats, 12, minSeats, maxSeats)
+
+		if seats[0] != 2 {
+			t.Errorf(`%s: The largest count did not get its maximum: %v`, name, seats)
+		}
+	}
+}
+
+func TestApportionersInvalidBounds(t *testing.T) {
+	counts := []uint{5, 3, 1}
+
+	tests := []struct {
+		name     string
+		seats    uint
+		minSeats []uint
+		maxSeats []uint
+	}{
+		{`minimums too large`, 5, []uint{2, 2, 2}, nil},
+		{`maximums too small`, 5, nil, []uint{1, 1, 1}},
+		{`minimum larger than maximum`, 5, []uint{3, 0, 0}, []uint{2, 5, 5}},
+		{`wrong length`, 5, []uint{1, 1}, nil},
+		{`zero count cannot get seats`, 5, nil, []uint{1, 1, 5}},
+	}
+
+	for _, name := range distributor.MethodNames() {
+		a, _ := distributor.ParseMethod(name)
+
+		for _, tc := range tests {
+			c := counts
+			if tc.name == `zero count cannot get seats` {
+				c = []uint{5, 3, 0}
+			}
+
+			_, err := a.ApportionBounded(c, tc.seats, tc.minSeats, tc.maxSeats)
+			if !errors.Is(err, distributor.ErrInvalidBounds) {
+				t.Errorf(`%s: %s: Expected error %v, got %v`, name, tc.name, distributor.ErrInvalidBounds, err)
+			}
+		}
+	}
+}
+
+// TestApportionersBoundedProperties checks the bounds and the seat count for random counts and satisfiable bounds.
+// Without bounds the result must be the same as the result of the unbounded method.
+func TestApportionersBoundedProperties(t *testing.T) {
+	rng := rand.New(rand.NewPCG(0x426f756e6473, 0x4d696e4d6178))
+
+	for _, name := range distributor.MethodNames() {
+		a, _ := distributor.ParseMethod(name)
+
+		for range 1_000 {
+			n := 1 + rng.IntN(26)
+			counts := make([]uint, n)
+			minSeats := make([]uint, n)
+			maxSeats := make([]uint, n)
+			minSum := uint(0)
+			maxSum := uint(0)
+			for i := range counts {
+				if rng.IntN(5) != 0 {
+					counts[i] = rng.UintN(1_000)
+				}
+				minSeats[i] = rng.UintN(3)
+				maxSeats[i] = minSeats[i] + rng.UintN(6)
+				if counts[i] == 0 {
+					maxSeats[i] = minSeats[i]
+				}
+				minSum += minSeats[i]
+				maxSum += maxSeats[i]
+			}
+
+			seatsCount := minSum + rng.UintN(maxSum-minSum+1)
+
+			seats, err := a.ApportionBounded(counts, seatsCount, minSeats, maxSeats)
+			if err != nil {
+				t.Fatalf(`%s: %v: Error distributing %d seats: %v`, name, counts, seatsCount, err)
+			}
+
+			expectBounds(t, name, seats, seatsCount, minSeats, maxSeats)
+
+			if total(counts) == 0 {
+				continue
+			}
+
+			var withoutBounds, unbounded []uint
+			withoutBounds, err = a.ApportionBounded(counts, seatsCount, nil, nil)
+			if err != nil {
+				t.Fatalf(`%s: %v: Error distributing %d seats without bounds: %v`, name, counts, seatsCount, err)
+			}
+
+			unbounded, _ = a.Apportion(counts, seatsCount)
+			if !slices.Equal(withoutBounds, unbounded) {
+				t.Fatalf(`%s: %v: Without bounds %v differs from %v`, name, counts, withoutBounds, unbounded)
+			}
+		}
+	}
+}
+
+func TestParseMethod(t *testing.T) {
+	a, err := distributor.ParseMethod(`DHondt`)
+	if err != nil {
+		t.Fatalf(`Error parsing method: %v`, err)
+	}
+	if a.Name() != `dhondt` {
+		t.Errorf(formatExpectedGot, `dhondt`, a.Name())
+	}
+
+	_, err = distributor.ParseMethod(`lottery`)
+	if !errors.Is(err, distributor.ErrUnknownMethod) {
+		t.Errorf(formatExpectedGot, distributor.ErrUnknownMethod, err)
+	}
+
+	if distributor.DefaultApportioner().Name() != `sainte-lague` {
+		t.Errorf(formatExpectedGot, `sainte-lague`, distributor.DefaultApportioner().Name())
+	}
+}
+
+// ******** Private functions ********
+
+// expectBounds checks that the seats sum up to the seat count and are within the bounds.
+func expectBounds(t *testing.T, name string, seats []uint, seatsCount uint, minSeats []uint, maxSeats []uint) {
+	t.Helper()
+
+	if total(seats) != seatsCount {
+		t.Fatalf(`%s: Expected %d seats, got %v`, name, seatsCount, seats)
+	}
+
+	for i, s := range seats {
+		if s < minSeats[i] || s > maxSeats[i] {
+			t.Fatalf(`%s: Seats %d of count %d are not in [%d, %d]: %v`, name, s, i, minSeats[i], maxSeats[i], seats)
+		}
+	}
+}
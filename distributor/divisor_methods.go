@@ -0,0 +1,141 @@
+//
+// SPDX-FileCopyrightText: Copyright 2026 Frank Schwab
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileType: SOURCE
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+//
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Author: Frank Schwab
+//
+// Version: 1.2.1
+//
+// Change history:
+//    2026-10-18: V1.0.0: Created.
+//    2026-10-18: V1.1.0: Compare quotients exactly.
+//    2026-10-18: V1.2.0: Add minimum and maximum number of seats.
+//    2026-10-18: V1.2.1: Calculate squared divisors with 64 bits.
+//
+
+package distributor
+
+// ******** Public types ********
+
+// SainteLague implements the Sainte-Laguë method with the divisors 1, 3, 5, 7, ...
+type SainteLague struct{}
+
+// DHondt implements the D'Hondt method with the divisors 1, 2, 3, 4, ...
+// It favors large counts.
+type DHondt struct{}
+
+// HuntingtonHill implements the Huntington-Hill method with the divisors √(s·(s+1)).
+// Each count that is not zero gets at least one seat, if there are enough seats.
+type HuntingtonHill struct{}
+
+// Adams implements the Adams method with the divisors 0, 1, 2, 3, ...
+// Each count that is not zero gets at least one seat, if there are enough seats. It favors small counts.
+type Adams struct{}
+
+// ******** Public type functions ********
+
+// Apportion distributes wantedSeatCount seats to the counts with the Sainte-Laguë method.
+func (SainteLague) Apportion(counts []uint, wantedSeatCount uint) ([]uint, error) {
+	return highestAverages(counts, wantedSeatCount, nil, nil, sainteLagueSquaredDivisor)
+}
+
+// ApportionBounded distributes wantedSeatCount seats to the counts with the Sainte-Laguë method
+// and honors the minimum and maximum numbers of seats.
+func (SainteLague) ApportionBounded(counts []uint, wantedSeatCount uint, minSeats []uint, maxSeats []uint) ([]uint, error) {
+	return highestAverages(counts, wantedSeatCount, minSeats, maxSeats, sainteLagueSquaredDivisor)
+}
+
+// Name returns the name of the Sainte-Laguë method.
+func (SainteLague) Name() string {
+	return `sainte-lague`
+}
+
+// Apportion distributes wantedSeatCount seats to the counts with the D'Hondt method.
+func (DHondt) Apportion(counts []uint, wantedSeatCount uint) ([]uint, error) {
+	return highestAverages(counts, wantedSeatCount, nil, nil, dHondtSquaredDivisor)
+}
+
+// ApportionBounded distributes wantedSeatCount seats to the counts with the D'Hondt method
+// and honors the minimum and maximum numbers of seats.
+func (DHondt) ApportionBounded(counts []uint, wantedSeatCount uint, minSeats []uint, maxSeats []uint) ([]uint, error) {
+	return highestAverages(counts, wantedSeatCount, minSeats, maxSeats, dHondtSquaredDivisor)
+}
+
+// Name returns the name of the D'Hondt method.
+func (DHondt) Name() string {
+	return `dhondt`
+}
+
+// Apportion distributes wantedSeatCount seats to the counts with the Huntington-Hill method.
+func (HuntingtonHill) Apportion(counts []uint, wantedSeatCount uint) ([]uint, error) {
+	return highestAverages(counts, wantedSeatCount, nil, nil, huntingtonHillSquaredDivisor)
+}
+
+// ApportionBounded distributes wantedSeatCount seats to the counts with the Huntington-Hill method
+// and honors the minimum and maximum numbers of seats.
+func (HuntingtonHill) ApportionBounded(counts []uint, wantedSeatCount uint, minSeats []uint, maxSeats []uint) ([]uint, error) {
+	return highestAverages(counts, wantedSeatCount, minSeats, maxSeats, huntingtonHillSquaredDivisor)
+}
+
+// Name returns the name of the Huntington-Hill method.
+func (HuntingtonHill) Name() string {
+	return `huntington-hill`
+}
+
+// Apportion distributes wantedSeatCount seats to the counts with the Adams method.
+func (Adams) Apportion(counts []uint, wantedSeatCount uint) ([]uint, error) {
+	return highestAverages(counts, wantedSeatCount, nil, nil, adamsSquaredDivisor)
+}
+
+// ApportionBounded distributes wantedSeatCount seats to the counts with the Adams method
+// and honors the minimum and maximum numbers of seats.
+func (Adams) ApportionBounded(counts []uint, wantedSeatCount uint, minSeats []uint, maxSeats []uint) ([]uint, error) {
+	return highestAverages(counts, wantedSeatCount, minSeats, maxSeats, adamsSquaredDivisor)
+}
+
+// Name returns the name of the Adams method.
+func (Adams) Name() string {
+	return `adams`
+}
+
+// ******** Private functions ********
+
+// sainteLagueSquaredDivisor returns the square of the Sainte-Laguë divisor 2s+1.
+func sainteLagueSquaredDivisor(seats uint) uint64 {
+	d := 2*uint64(seats) + 1
+	return d * d
+}
+
+// dHondtSquaredDivisor returns the square of the D'Hondt divisor s+1.
+func dHondtSquaredDivisor(seats uint) uint64 {
+	d := uint64(seats) + 1
+	return d * d
+}
+
+// huntingtonHillSquaredDivisor returns the square of the Huntington-Hill divisor √(s·(s+1)).
+func huntingtonHillSquaredDivisor(seats uint) uint64 {
+	s := uint64(seats)
+	return s * (s + 1)
+}
+
+// adamsSquaredDivisor returns the square of the Adams divisor s.
+func adamsSquaredDivisor(seats uint) uint64 {
+	s := uint64(seats)
+	return s * s
+}
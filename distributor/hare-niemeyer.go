@@ -0,0 +1,213 @@
+//
+// SPDX-FileCopyrightText: Copyright 2026 Frank Schwab
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileType: SOURCE
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+//
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Author: Frank Schwab
+//
+// Version: 1.1.0
+//
+// Change history:
+//    2026-10-18: V1.0.0: Created.
+//    2026-10-18: V1.1.0: Add minimum and maximum number of seats.
+//
+
+package distributor
+
+import (
+	"math/big"
+	"math/bits"
+	"slices"
+)
+
+// ******** Public types ********
+
+// HareNiemeyer implements the Hare-Niemeyer (largest remainder) method.
+// Each count gets the integer part of its quota. The remaining seats are given to the counts
+// with the largest remainders.
+type HareNiemeyer struct{}
+
+// ******** Public type functions ********
+
+// Apportion distributes wantedSeatCount seats to the counts with the Hare-Niemeyer method.
+// If two counts have the same remainder the one with the lower index gets the seat.
+func (h HareNiemeyer) Apportion(counts []uint, wantedSeatCount uint) ([]uint, error) {
+	return h.ApportionBounded(counts, wantedSeatCount, nil, nil)
+}
+
+// ApportionBounded distributes wantedSeatCount seats to the counts with the Hare-Niemeyer method
+// and honors the minimum and maximum numbers of seats.
+// The quota of each count is count·t, where t is chosen such that the quotas that are limited
+// to their bounds sum up to wantedSeatCount. Counts whose quota is not limited by a bound then
+// get the integer part of their quota, and the remaining seats are given to the largest remainders.
+func (HareNiemeyer) ApportionBounded(counts []uint, wantedSeatCount uint, minSeats []uint, maxSeats []uint) ([]uint, error) {
+	err := checkBounds(counts, wantedSeatCount, minSeats, maxSeats)
+	if err != nil {
+		return nil, err
+	}
+
+	seats, free := boundedSeats(counts, wantedSeatCount, minSeats, maxSeats)
+
+	remainingSeatCount := wantedSeatCount
+	freeTotal := uint(0)
+	for i, count := range counts {
+		if free[i] {
+			freeTotal += count
+		} else {
+			remainingSeatCount -= seats[i]
+		}
+	}
+
+	if freeTotal == 0 {
+		if remainingSeatCount != 0 {
+			return nil, errNoSeatsLeft(counts)
+		}
+
+		return seats, nil
+	}
+
+	// The quotas are calculated with integers, so there are no rounding errors.
+	remainders := make([]uint, len(counts))
+	for i, count := range counts {
+		if free[i] {
+			hi, lo := bits.Mul(count, remainingSeatCount)
+			seats[i], remainders[i] = bits.Div(hi, lo, freeTotal)
+		}
+	}
+
+	distributeRemainders(seats, remainders, free, remainingSeatCount)
+
+	return seats, nil
+}
+
+// Name returns the name of the Hare-Niemeyer method.
+func (HareNiemeyer) Name() string {
+	return `hare-niemeyer`
+}
+
+// ******** Private functions ********
+
+// boundedSeats determines which counts are limited by their bounds.
+// The sum of the limited quotas, i.e. max(minSeats, min(count·t, maxSeats)), is a monotone function of t,
+// which is linear between the points where a quota reaches a bound. The last of these points
+// where the sum is not larger than wantedSeatCount determines the counts that are limited.
+// It returns the seats of the limited counts and which counts are not limited.
+func boundedSeats(counts []uint, wantedSeatCount uint, minSeats []uint, maxSeats []uint) ([]uint, []bool) {
+	breakpoints := []*big.Rat{new(big.Rat)}
+	for i, count := range counts {
+		if count == 0 {
+			continue
+		}
+
+		if minSeats != nil && minSeats[i] != 0 {
+			breakpoints = append(breakpoints, ratio(minSeats[i], count))
+		}
+
+		if maxSeats != nil {
+			breakpoints = append(breakpoints, ratio(maxSeats[i], count))
+		}
+	}
+
+	wanted := ratio(wantedSeatCount, 1)
+	t := breakpoints[0]
+	for _, b := range breakpoints[1:] {
+		if b.Cmp(t) > 0 && limitedQuotaSum(counts, b, minSeats, maxSeats).Cmp(wanted) <= 0 {
+			t = b
+		}
+	}
+
+	// Classify the counts for a t that is slightly larger than the breakpoint.
+	seats := initialSeats(counts, minSeats)
+	free := make([]bool, len(counts))
+	for i, count := range counts {
+		if count == 0 {
+			continue
+		}
+
+		quota := new(big.Rat).Mul(ratio(count, 1), t)
+		switch {
+		case maxSeats != nil && quota.Cmp(ratio(maxSeats[i], 1)) >= 0:
+			seats[i] = maxSeats[i]
+
+		case quota.Cmp(ratio(minOf(minSeats, i), 1)) < 0:
+			// The count keeps its minimum.
+
+		default:
+			free[i] = true
+		}
+	}
+
+	return seats, free
+}
+
+// limitedQuotaSum returns the sum of the quotas count·t that are limited to their bounds.
+func limitedQuotaSum(counts []uint, t *big.Rat, minSeats []uint, maxSeats []uint) *big.Rat {
+	result := new(big.Rat)
+	quota := new(big.Rat)
+	for i, count := range counts {
+		quota.Mul(ratio(count, 1), t)
+
+		lower := ratio(minOf(minSeats, i), 1)
+		if quota.Cmp(lower) < 0 {
+			quota.Set(lower)
+		}
+
+		if maxSeats != nil {
+			upper := ratio(maxSeats[i], 1)
+			if quota.Cmp(upper) > 0 {
+				quota.Set(upper)
+			}
+		}
+
+		result.Add(result, quota)
+	}
+
+	return result
+}
+
+// ratio returns the rational number a/b.
+func ratio(a uint, b uint) *big.Rat {
+	return new(big.Rat).SetFrac(new(big.Int).SetUint64(uint64(a)), new(big.Int).SetUint64(uint64(b)))
+}
+
+// distributeRemainders gives the seats that are left after the integer parts of the quotas
+// to the free counts with the largest remainders.
+func distributeRemainders(seats []uint, remainders []uint, free []bool, remainingSeatCount uint) {
+	indices := make([]int, 0, len(seats))
+	for i := range seats {
+		if free[i] {
+			indices = append(indices, i)
+			remainingSeatCount -= seats[i]
+		}
+	}
+
+	slices.SortStableFunc(indices, func(a, b int) int {
+		switch {
+		case remainders[a] > remainders[b]:
+			return -1
+		case remainders[a] < remainders[b]:
+			return 1
+		default:
+			return 0
+		}
+	})
+
+	for _, i := range indices[:remainingSeatCount] {
+		seats[i]++
+	}
+}
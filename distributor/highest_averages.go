@@ -0,0 +1,202 @@
+//
+// SPDX-FileCopyrightText: Copyright 2026 Frank Schwab
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileType: SOURCE
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+//
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Author: Frank Schwab
+//
+// Version: 1.1.1
+//
+// Change history:
+//    2026-10-18: V1.0.0: Created.
+//    2026-10-18: V1.1.0: Add minimum and maximum number of seats.
+//    2026-10-18: V1.1.1: Calculate squared divisors with 64 bits.
+//
+
+package distributor
+
+import (
+	"cmp"
+	"container/heap"
+	"errors"
+	"math/bits"
+)
+
+// Divisor methods give the next seat to the count with the highest quotient of the count
+// and a divisor d(s) that depends on the number of seats s the count already has.
+//
+// The quotients are compared exactly with integers. As the divisor of the Huntington-Hill method
+// is a square root, all methods compare count²/d(s)², instead of count/d(s).
+//
+// Each count starts with its minimum number of seats and does not get any more seats when it
+// has reached its maximum number of seats.
+//
+// Counts that are zero never get more than their minimum. A divisor of zero results in an infinite quotient.
+// If two quotients are equal, the seat is given to the count with the lower index.
+// So the result is always the same for the same input.
+
+// ******** Public constants ********
+
+// ErrTooManySeats is returned when the number of seats is too large for an exact calculation.
+var ErrTooManySeats = errors.New(`too many seats`)
+
+// ******** Private constants ********
+
+// maxSeatCount is the maximum number of seats.
+// The largest squared divisor is (2·2^30+1)², so the squared divisors fit into an uint64, even on 32-bit platforms.
+const maxSeatCount = 1 << 30
+
+// ******** Private types ********
+
+// squaredDivisorFunc returns the square of the divisor for a count that already has seats seats.
+// It is calculated with 64 bits, as the square of a divisor overflows an uint on 32-bit platforms.
+type squaredDivisorFunc func(seats uint) uint64
+
+// candidate is an entry in the priority queue of a divisor method.
+type candidate struct {
+	index          int
+	count          uint
+	squaredDivisor uint64
+}
+
+// candidateQueue is a priority queue of candidates with the highest quotient first.
+type candidateQueue []candidate
+
+// ******** Private functions ********
+
+// highestAverages distributes wantedSeatCount seats one by one with a divisor method.
+func highestAverages(
+	counts []uint,
+	wantedSeatCount uint,
+	minSeats []uint,
+	maxSeats []uint,
+	squaredDivisor squaredDivisorFunc,
+) ([]uint, error) {
+	if wantedSeatCount > maxSeatCount {
+		return nil, ErrTooManySeats
+	}
+
+	err := checkBounds(counts, wantedSeatCount, minSeats, maxSeats)
+	if err != nil {
+		return nil, err
+	}
+
+	seats := initialSeats(counts, minSeats)
+	remainingSeatCount := wantedSeatCount
+	for _, s := range seats {
+		remainingSeatCount -= s
+	}
+
+	queue := make(candidateQueue, 0, len(counts))
+	for i, count := range counts {
+		if count != 0 && seats[i] < maxOf(maxSeats, i) {
+			queue = append(queue, candidate{index: i, count: count, squaredDivisor: squaredDivisor(seats[i])})
+		}
+	}
+	heap.Init(&queue)
+
+	for range remainingSeatCount {
+		if len(queue) == 0 {
+			return nil, errNoSeatsLeft(counts)
+		}
+
+		best := &queue[0]
+		i := best.index
+		seats[i]++
+		if seats[i] < maxOf(maxSeats, i) {
+			best.squaredDivisor = squaredDivisor(seats[i])
+			heap.Fix(&queue, 0)
+		} else {
+			heap.Pop(&queue)
+		}
+	}
+
+	return seats, nil
+}
+
+// compareQuotients compares the quotients countA²/squaredDivisorA and countB²/squaredDivisorB.
+// It returns -1, 0 or +1, if the first quotient is less than, equal to or greater than the second one.
+// The quotients are compared by their cross products, which need up to 192 bits.
+func compareQuotients(countA uint, squaredDivisorA uint64, countB uint, squaredDivisorB uint64) int {
+	leftHigh, leftMiddle, leftLow := squareTimes(countA, squaredDivisorB)
+	rightHigh, rightMiddle, rightLow := squareTimes(countB, squaredDivisorA)
+
+	if c := cmp.Compare(leftHigh, rightHigh); c != 0 {
+		return c
+	}
+
+	if c := cmp.Compare(leftMiddle, rightMiddle); c != 0 {
+		return c
+	}
+
+	return cmp.Compare(leftLow, rightLow)
+}
+
+// squareTimes returns count²·factor as a 192-bit number, split into three 64-bit words.
+func squareTimes(count uint, factor uint64) (high uint64, middle uint64, low uint64) {
+	squareHigh, squareLow := bits.Mul64(uint64(count), uint64(count))
+
+	lowCarry, low := bits.Mul64(squareLow, factor)
+	high, middle = bits.Mul64(squareHigh, factor)
+
+	var carry uint64
+	middle, carry = bits.Add64(middle, lowCarry, 0)
+	high += carry
+
+	return high, middle, low
+}
+
+// ******** Private type functions ********
+
+// Len returns the number of candidates.
+func (q candidateQueue) Len() int {
+	return len(q)
+}
+
+// Less reports whether candidate i has a higher priority than candidate j.
+func (q candidateQueue) Less(i, j int) bool {
+	ci := &q[i]
+	cj := &q[j]
+
+	c := compareQuotients(ci.count, ci.squaredDivisor, cj.count, cj.squaredDivisor)
+	if c != 0 {
+		return c > 0
+	}
+
+	return ci.index < cj.index
+}
+
+// Swap swaps two candidates.
+func (q candidateQueue) Swap(i, j int) {
+	q[i], q[j] = q[j], q[i]
+}
+
+// Push is needed by [heap.Interface]. It is never used, as candidates are only added before initialization.
+func (q *candidateQueue) Push(x any) {
+	*q = append(*q, x.(candidate))
+}
+
+// Pop removes the last candidate. It is used to remove a candidate that has reached its maximum number of seats.
+func (q *candidateQueue) Pop() any {
+	old := *q
+	n := len(old) - 1
+	result := old[n]
+	*q = old[:n]
+
+	return result
+}
@@ -0,0 +1,92 @@
+//
+// SPDX-FileCopyrightText: Copyright 2026 Frank Schwab
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileType: SOURCE
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+//
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Author: Frank Schwab
+//
+// Version: 1.0.0
+//
+// Change history:
+//    2026-10-18: V1.0.0: Created.
+//
+
+package distributor
+
+import (
+	"math"
+	"math/big"
+	"math/rand/v2"
+	"testing"
+)
+
+// ******** Test functions ********
+
+// TestCompareQuotients compares the results of compareQuotients with an exact calculation with big integers.
+func TestCompareQuotients(t *testing.T) {
+	edges := []uint64{0, 1, 2, math.MaxUint32, math.MaxUint32 + 1, math.MaxUint64 - 1, math.MaxUint64}
+	for _, countA := range edges {
+		for _, divisorA := range edges {
+			for _, countB := range edges {
+				for _, divisorB := range edges {
+					checkCompareQuotients(t, uint(countA), divisorA, uint(countB), divisorB)
+				}
+			}
+		}
+	}
+
+	for range 10_000 {
+		count := uint(rand.Uint64())
+		divisor := rand.Uint64()
+		checkCompareQuotients(t, count, divisor, count, divisor)
+		checkCompareQuotients(t, count, divisor, uint(rand.Uint64()), rand.Uint64())
+		checkCompareQuotients(t, count, divisor, count+1, divisor)
+		checkCompareQuotients(t, count, divisor, count, divisor+1)
+	}
+}
+
+func TestCompareQuotientsAllocations(t *testing.T) {
+	allocs := testing.AllocsPerRun(100, func() {
+		compareQuotients(math.MaxUint32, math.MaxUint64, math.MaxUint32-1, math.MaxUint64-1)
+	})
+	if allocs != 0 {
+		t.Fatalf(`Expected no allocations, got %v`, allocs)
+	}
+}
+
+// ******** Private functions ********
+
+// checkCompareQuotients checks one comparison of compareQuotients.
+func checkCompareQuotients(t *testing.T, countA uint, squaredDivisorA uint64, countB uint, squaredDivisorB uint64) {
+	t.Helper()
+
+	expected := crossProduct(countA, squaredDivisorB).Cmp(crossProduct(countB, squaredDivisorA))
+	got := compareQuotients(countA, squaredDivisorA, countB, squaredDivisorB)
+	if got != expected {
+		t.Fatalf(`Comparing %d²/%d with %d²/%d: Expected %d, got %d`,
+			countA, squaredDivisorA, countB, squaredDivisorB, expected, got)
+	}
+}
+
+// crossProduct returns count²·factor.
+func crossProduct(count uint, factor uint64) *big.Int {
+	result := new(big.Int).SetUint64(uint64(count))
+	result.Mul(result, result)
+
+	return result.Mul(result, new(big.Int).SetUint64(factor))
+}
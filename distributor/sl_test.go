@@ -20,19 +20,23 @@
 //
 // Author: Frank Schwab
 //
-// Version: 1.1.0
+// Version: 2.0.0
 //
 // Change history:
 //    2025-02-08: V1.0.0: Created.
 //    2025-02-10: V1.1.0: Refactor constants out of statements.
+//    2026-10-18: V2.0.0: Distribution returns an error. Add property and tie-breaking tests.
 //
 
 // Package distributor_test contains the tests for the count distributors.
 package distributor_test
 
 import (
+	"errors"
 	"homophone/constraints"
 	"homophone/distributor"
+	"math/big"
+	"math/rand/v2"
 	"slices"
 	"testing"
 )
@@ -51,7 +55,7 @@ func TestSainteLagueDistributionSachsen(t *testing.T) {
 	counts := []uint{749_216, 719_274, 277_173, 172_002, 119_964, 104_888}
 	seatsCount := uint(119)
 	expectedSeats := []uint{41, 40, 15, 10, 7, 6}
-	seats := distributor.SainteLagueDistribution(counts, total(counts), seatsCount)
+	seats := sainteLague(t, counts, seatsCount)
 	if !slices.Equal(expectedSeats, seats) {
 		t.Errorf(formatExpectedGot, expectedSeats, seats)
 	}
@@ -61,7 +65,7 @@ func TestSainteLagueMallersdorfPfaffenberg(t *testing.T) {
 	counts := []int16{28_206, 18_251, 10_000, 9_229, 1_487}
 	seatsCount := uint(20)
 	expectedSeats := []uint{8, 6, 3, 3, 0}
-	seats := distributor.SainteLagueDistribution(counts, total(counts), seatsCount)
+	seats := sainteLague(t, counts, seatsCount)
 	if !slices.Equal(expectedSeats, seats) {
 		t.Errorf(formatExpectedGot, expectedSeats, seats)
 	}
@@ -71,7 +75,7 @@ func TestSainteLagueOnlyOne(t *testing.T) {
 	counts := []uint8{5}
 	seatsCount := uint(37)
 	expectedSeats := []uint{37}
-	seats := distributor.SainteLagueDistribution(counts, total(counts), seatsCount)
+	seats := sainteLague(t, counts, seatsCount)
 	if !slices.Equal(expectedSeats, seats) {
 		t.Errorf(formatExpectedGot, expectedSeats, seats)
 	}
@@ -80,7 +84,7 @@ func TestSainteLagueOnlyOne(t *testing.T) {
 func TestSainteLagueEquals(t *testing.T) {
 	counts := []uint{7, 7, 7, 7, 7}
 	seatsCount := uint(12)
-	seats := distributor.SainteLagueDistribution(counts, total(counts), seatsCount)
+	seats := sainteLague(t, counts, seatsCount)
 	runMap := runs(seats)
 	if len(runMap) != 2 {
 		t.Errorf(formatExpectedRuns, 2, len(runMap), runMap)
@@ -96,7 +100,7 @@ func TestSainteLagueEquals(t *testing.T) {
 func TestSainteLagueZeroesAndOnes(t *testing.T) {
 	counts := []uint{0, 0, 0, 0, 1, 0, 0, 1, 0, 1, 0, 0, 1, 0, 0, 0, 1, 0, 0, 0}
 	seatsCount := uint(52)
-	seats := distributor.SainteLagueDistribution(counts, total(counts), seatsCount)
+	seats := sainteLague(t, counts, seatsCount)
 	runMap := runs(seats)
 	if len(runMap) != 3 {
 		t.Errorf(formatExpectedRuns, 3, len(runMap), runMap)
@@ -109,27 +113,152 @@ func TestSainteLagueZeroesAndOnes(t *testing.T) {
 	}
 }
 
+// TestSainteLagueRandom checks a distribution with many equal counts.
+// Of the six counts of 14 only the first four get a seat, as their quotients are equal.
 func TestSainteLagueRandom(t *testing.T) {
 	counts := []uint16{40, 12, 18, 14, 94, 17, 13, 17, 50, 16, 6, 28, 14, 27, 45, 14, 13, 36, 43, 70, 31, 13, 14, 14, 14, 15}
 	seatsCount := uint(26)
-	seats := distributor.SainteLagueDistribution(counts, total(counts), seatsCount)
+	expectedSeats := []uint{1, 0, 1, 1, 3, 1, 0, 1, 2, 1, 0, 1, 1, 1, 2, 1, 0, 1, 2, 3, 1, 0, 1, 0, 0, 1}
+	seats := sainteLague(t, counts, seatsCount)
+	if !slices.Equal(expectedSeats, seats) {
+		t.Errorf(formatExpectedGot, expectedSeats, seats)
+	}
 	runMap := runs(seats)
-	if len(runMap) != 3 {
-		t.Errorf(formatExpectedRuns, 3, len(runMap), runMap)
+	if len(runMap) != 4 {
+		t.Errorf(formatExpectedRuns, 4, len(runMap), runMap)
+	}
+	if runMap[0] != 7 {
+		t.Errorf(formatExpectedSpecificRuns, 7, 0, runMap[0])
+	}
+	if runMap[1] != 14 {
+		t.Errorf(formatExpectedSpecificRuns, 14, 1, runMap[1])
+	}
+	if runMap[2] != 3 {
+		t.Errorf(formatExpectedSpecificRuns, 3, 2, runMap[2])
+	}
+	if runMap[3] != 2 {
+		t.Errorf(formatExpectedSpecificRuns, 2, 3, runMap[3])
 	}
-	if runMap[0] != 5 {
-		t.Errorf(formatExpectedSpecificRuns, 5, 0, runMap[0])
+}
+
+func TestSainteLagueTieBreaking(t *testing.T) {
+	counts := []uint{7, 3, 7, 3, 7}
+	seatsCount := uint(4)
+	expectedSeats := []uint{1, 1, 1, 0, 1}
+	seats := sainteLague(t, counts, seatsCount)
+	if !slices.Equal(expectedSeats, seats) {
+		t.Errorf(formatExpectedGot, expectedSeats, seats)
 	}
-	if runMap[1] != 16 {
-		t.Errorf(formatExpectedSpecificRuns, 16, 1, runMap[1])
+}
+
+func TestSainteLagueNegativeCount(t *testing.T) {
+	counts := []int{3, -1, 5}
+	_, err := distributor.SainteLagueDistribution(counts, 5)
+	if !errors.Is(err, distributor.ErrNegativeCount) {
+		t.Errorf(formatExpectedGot, distributor.ErrNegativeCount, err)
+	}
+}
+
+func TestSainteLagueAllZero(t *testing.T) {
+	counts := []uint{0, 0, 0}
+	_, err := distributor.SainteLagueDistribution(counts, 5)
+	if !errors.Is(err, distributor.ErrNoCounts) {
+		t.Errorf(formatExpectedGot, distributor.ErrNoCounts, err)
 	}
-	if runMap[2] != 5 {
-		t.Errorf(formatExpectedSpecificRuns, 5, 2, runMap[2])
+}
+
+// TestSainteLagueProperties checks the properties of the distribution for random counts.
+// The sum of the seats must be the seat count, zero counts must not get a seat, and no
+// count with a seat may have a lower quotient before its last seat than any count after its last seat.
+func TestSainteLagueProperties(t *testing.T) {
+	rng := rand.New(rand.NewPCG(0x5361696e7465, 0x4c61677565))
+
+	for range 2_000 {
+		counts := make([]uint, 1+rng.IntN(30))
+		// The counts must fit into an uint on 32-bit platforms.
+		maxCount := uint(1) << rng.IntN(32)
+		for i := range counts {
+			// Many equal and zero counts make ties likely.
+			switch rng.IntN(4) {
+			case 0:
+				counts[i] = 0
+			case 1:
+				counts[i] = 7
+			default:
+				counts[i] = rng.UintN(maxCount)
+			}
+		}
+		if total(counts) == 0 {
+			counts[0] = 1
+		}
+
+		seatsCount := rng.UintN(200)
+
+		seats := sainteLague(t, counts, seatsCount)
+
+		if total(seats) != seatsCount {
+			t.Fatalf(`%v: Expected %d seats, got %d: %v`, counts, seatsCount, total(seats), seats)
+		}
+
+		for i, count := range counts {
+			if count == 0 && seats[i] != 0 {
+				t.Fatalf(`%v: Count %d is zero, but got %d seats`, counts, i, seats[i])
+			}
+		}
+
+		expectHighestAverages(t, counts, seats)
+
+		again := sainteLague(t, counts, seatsCount)
+		if !slices.Equal(seats, again) {
+			t.Fatalf(`%v: Distribution is not deterministic: %v and %v`, counts, seats, again)
+		}
 	}
 }
 
 // ******** Private functions ********
 
+// sainteLague distributes the seats with the Sainte-Laguë method and fails on errors.
+func sainteLague[T constraints.Integer](t *testing.T, counts []T, seatsCount uint) []uint {
+	t.Helper()
+
+	seats, err := distributor.SainteLagueDistribution(counts, seatsCount)
+	if err != nil {
+		t.Fatalf(`Error distributing %d seats to %v: %v`, seatsCount, counts, err)
+	}
+
+	return seats
+}
+
+// expectHighestAverages checks that the smallest quotient count/(2s-1) of all counts with s > 0 seats
+// is not smaller than the largest quotient count/(2s+1) of all counts.
+func expectHighestAverages(t *testing.T, counts []uint, seats []uint) {
+	t.Helper()
+
+	var minLast, maxNext *big.Rat
+	for i, count := range counts {
+		if count == 0 {
+			continue
+		}
+
+		c := new(big.Int).SetUint64(uint64(count))
+		if seats[i] > 0 {
+			last := new(big.Rat).SetFrac(c, new(big.Int).SetUint64(uint64(2*seats[i]-1)))
+			if minLast == nil || last.Cmp(minLast) < 0 {
+				minLast = last
+			}
+		}
+
+		next := new(big.Rat).SetFrac(c, new(big.Int).SetUint64(uint64(2*seats[i]+1)))
+		if maxNext == nil || next.Cmp(maxNext) > 0 {
+			maxNext = next
+		}
+	}
+
+	if minLast != nil && minLast.Cmp(maxNext) < 0 {
+		t.Fatalf(`%v: Seats %v are not a highest averages distribution: %s < %s`, counts, seats, minLast, maxNext)
+	}
+}
+
 // total returns the total count.
 func total[T constraints.Integer](counts []T) uint {
 	result := uint(0)
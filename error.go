@@ -20,11 +20,13 @@
 //
 // Author: Frank Schwab
 //
-// Version: 1.0.1
+// Version: 1.2.0
 //
 // Change history:
 //    2024-12-29: V1.0.0: Created.
 //    2025-01-05: V1.0.1: New line after processing error.
+//    2026-10-18: V1.1.0: Print warnings.
+//    2026-10-18: V1.2.0: Write to the output streams of the program.
 //
 
 package main
@@ -33,8 +35,6 @@ import (
 	"errors"
 	"flag"
 	"fmt"
-	"io"
-	"os"
 	"runtime"
 )
 
@@ -50,52 +50,54 @@ const (
 
 // printUsageError prints an error message and the usage information.
 func printUsageError(msg string) int {
-	errWriter := flag.CommandLine.Output()
-
 	_, _ = fmt.Fprintln(errWriter)
 	_, _ = fmt.Fprint(errWriter, msg)
 
-	return printUsage(errWriter)
+	return printUsage()
 }
 
 // printUsageErrorf print a formatted error message and the usage information.
 func printUsageErrorf(format string, a ...any) int {
-	errWriter := flag.CommandLine.Output()
-
 	_, _ = fmt.Fprintln(errWriter)
 	_, _ = fmt.Fprintf(errWriter, format, a...)
 
-	return printUsage(errWriter)
+	return printUsage()
 }
 
 // printUsage prints the usage.
-func printUsage(errWriter io.Writer) int {
+func printUsage() int {
 	_, _ = fmt.Fprintln(errWriter)
-	flag.Usage()
+	myUsage()
 
 	return rcParameterError
 }
 
 // printUsageOnly prints only the usage.
 func printUsageOnly() int {
-	_, _ = fmt.Fprintln(flag.CommandLine.Output())
-	flag.Usage()
+	_, _ = fmt.Fprintln(errWriter)
+	myUsage()
 
 	return rcOK
 }
 
 // printErrorf prints a processing error message.
 func printErrorf(format string, a ...any) int {
-	_, _ = fmt.Fprintln(os.Stderr)
-	_, _ = fmt.Fprintf(os.Stderr, format, a...)
-	_, _ = fmt.Fprintln(os.Stderr)
+	_, _ = fmt.Fprintln(errWriter)
+	_, _ = fmt.Fprintf(errWriter, format, a...)
+	_, _ = fmt.Fprintln(errWriter)
 
 	return rcProcessingError
 }
 
+// printWarningf prints a warning message.
+func printWarningf(format string, a ...any) {
+	_, _ = fmt.Fprintf(errWriter, format, a...)
+	_, _ = fmt.Fprintln(errWriter)
+}
+
 // printVersion prints the version information for this program.
 func printVersion() int {
-	fmt.Printf("\n%s V%s (%s), %s\n", myName, myVersion, runtime.Version(), myCopyright)
+	_, _ = fmt.Fprintf(outWriter, "\n%s V%s (%s), %s\n", myName, myVersion, runtime.Version(), myCopyright)
 	return rcOK
 }
 
@@ -0,0 +1,109 @@
+//
+// SPDX-FileCopyrightText: Copyright 2026 Frank Schwab
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileType: SOURCE
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+//
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Author: Frank Schwab
+//
+// Version: 1.0.0
+//
+// Change history:
+//    2026-10-18: V1.0.0: Created.
+//
+
+package exercise
+
+import (
+	"errors"
+	"homophone/nametable"
+	"path/filepath"
+	"strings"
+)
+
+// ******** Public types ********
+
+// Format is the output format of an exercise sheet.
+type Format byte
+
+// ******** Public constants ********
+
+// Known output formats.
+const (
+	// Markdown is the Markdown format.
+	Markdown Format = iota
+
+	// HTML is a self-contained HTML page.
+	HTML
+)
+
+// ErrUnknownFormat is returned when an output format is not known.
+var ErrUnknownFormat = errors.New(`unknown output format`)
+
+// ******** Private variables ********
+
+// formatNames contains the names of the formats in the order of their values.
+var formatNames = nametable.Table[Format]{
+	{Value: Markdown, Name: `markdown`},
+	{Value: HTML, Name: `html`},
+}
+
+// formatExtensions contains the file extensions of the formats in the order of their values.
+var formatExtensions = []string{
+	`.md`,
+	`.html`,
+}
+
+// ******** Public functions ********
+
+// ParseFormat returns the output format with the given name.
+func ParseFormat(name string) (Format, error) {
+	return formatNames.Parse(name, ErrUnknownFormat)
+}
+
+// FormatNames returns the names of all known output formats.
+func FormatNames() []string {
+	return formatNames.Names()
+}
+
+// FormatFromFileName returns the output format for the extension of a file name.
+// Files with the extensions ".html" and ".htm" are HTML, all others are Markdown.
+func FormatFromFileName(fileName string) Format {
+	switch strings.ToLower(filepath.Ext(fileName)) {
+	case `.html`, `.htm`:
+		return HTML
+
+	default:
+		return Markdown
+	}
+}
+
+// ******** Public type functions ********
+
+// String returns the name of the output format.
+func (f Format) String() string {
+	return formatNames.Name(f)
+}
+
+// Extension returns the file extension of the output format including the dot.
+func (f Format) Extension() string {
+	if int(f) < len(formatExtensions) {
+		return formatExtensions[f]
+	}
+
+	return ``
+}
@@ -0,0 +1,372 @@
+//
+// SPDX-FileCopyrightText: Copyright 2026 Frank Schwab
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileType: SOURCE
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+//
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Author: Frank Schwab
+//
+// Version: 1.0.0
+//
+// Change history:
+//    2026-10-18: V1.0.0: Created.
+//
+
+package exercise
+
+import (
+	"fmt"
+	htmltemplate "html/template"
+	"io"
+	"slices"
+	"strings"
+	texttemplate "text/template"
+)
+
+// ******** Private types ********
+
+// templateExecutor executes a named template. It is implemented by text and HTML templates.
+type templateExecutor interface {
+	ExecuteTemplate(w io.Writer, name string, data any) error
+}
+
+// sheetView contains the prepared data of a sheet for the templates.
+type sheetView struct {
+	Ciphertext    string
+	Solution      string
+	Fence         string
+	RevealedCount int
+	LetterCount   int
+	PartialKey    []keyRow
+	AnswerKey     []keyRow
+	Frequencies   []frequencyRow
+}
+
+// keyRow is a row of a key table.
+type keyRow struct {
+	Letter     string
+	Homophones string
+}
+
+// frequencyRow is a row of the frequency table.
+type frequencyRow struct {
+	Symbol string
+	Count  uint
+	Share  string
+}
+
+// ******** Private constants ********
+
+// Layout of texts without characters other than letters.
+const (
+	groupLength   = 5
+	groupsPerLine = 10
+)
+
+// unknownHomophones is shown for a letter whose homophones are not revealed.
+const unknownHomophones = `?`
+
+// noHomophones is shown in the answer key for a letter without homophones.
+const noHomophones = `-`
+
+// Names of the parts of the templates.
+const (
+	headPart     = `head`
+	exercisePart = `exercise`
+	answerPart   = `answer`
+	footPart     = `foot`
+)
+
+// markdownTemplate is the template of the Markdown format.
+const markdownTemplate = `{{define "head"}}# Homophonic substitution exercise
+{{end}}
+
+{{define "exercise"}}
+Each letter of the clear text has been replaced by one of several symbols, its homophones.
+Frequent letters have more homophones than rare ones.
+Find the clear text.
+
+## Ciphertext
+
+{{.Fence}}
+{{.Ciphertext}}
+{{.Fence}}
+
+## Partial key
+
+The homophones of {{.RevealedCount}} of {{.LetterCount}} letters are revealed.
+
+| Letter | Homophones |
+|--------|------------|
+{{range .PartialKey}}| {{.Letter}} | {{.Homophones}} |
+{{end}}{{if .Frequencies}}
+## Frequencies of the ciphertext symbols
+
+| Symbol | Count | Share |
+|--------|------:|------:|
+{{range .Frequencies}}| {{.Symbol}} | {{.Count}} | {{.Share}} |
+{{end}}{{end}}{{end}}
+
+{{define "answer"}}
+---
+
+## Answer key
+
+| Letter | Homophones |
+|--------|------------|
+{{range .AnswerKey}}| {{.Letter}} | {{.Homophones}} |
+{{end}}
+### Solution
+
+{{.Fence}}
+{{.Solution}}
+{{.Fence}}
+{{end}}
+
+{{define "foot"}}{{end}}
+`
+
+// htmlTemplate is the template of the HTML format.
+const htmlTemplate = `{{define "head"}}<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>Homophonic substitution exercise</title>
+<style>
+body { font-family: sans-serif; max-width: 50em; margin: 2em auto; }
+pre, td { font-family: monospace; font-size: 1.1em; }
+table { border-collapse: collapse; }
+th, td { border: 1px solid #888; padding: 0.2em 0.6em; }
+td.number { text-align: right; }
+.answer { page-break-before: always; break-before: page; }
+</style>
+</head>
+<body>
+<h1>Homophonic substitution exercise</h1>
+{{end}}
+
+{{define "exercise"}}
+<p>Each letter of the clear text has been replaced by one of several symbols, its homophones.
+Frequent letters have more homophones than rare ones.
+Find the clear text.</p>
+
+<h2>Ciphertext</h2>
+<pre>{{.Ciphertext}}</pre>
+
+<h2>Partial key</h2>
+<p>The homophones of {{.RevealedCount}} of {{.LetterCount}} letters are revealed.</p>
+<table>
+<tr><th>Letter</th><th>Homophones</th></tr>
+{{range .PartialKey}}<tr><td>{{.Letter}}</td><td>{{.Homophones}}</td></tr>
+{{end}}</table>
+{{if .Frequencies}}
+<h2>Frequencies of the ciphertext symbols</h2>
+<table>
+<tr><th>Symbol</th><th>Count</th><th>Share</th></tr>
+{{range .Frequencies}}<tr><td>{{.Symbol}}</td><td class="number">{{.Count}}</td><td class="number">{{.Share}}</td></tr>
+{{end}}</table>
+{{end}}{{end}}
+
+{{define "answer"}}
+<div class="answer">
+<h2>Answer key</h2>
+<table>
+<tr><th>Letter</th><th>Homophones</th></tr>
+{{range .AnswerKey}}<tr><td>{{.Letter}}</td><td>{{.Homophones}}</td></tr>
+{{end}}</table>
+
+<h3>Solution</h3>
+<pre>{{.Solution}}</pre>
+</div>
+{{end}}
+
+{{define "foot"}}</body>
+</html>
+{{end}}
+`
+
+// ******** Private variables ********
+
+// Parsed templates.
+var (
+	markdownSheet = texttemplate.Must(texttemplate.New(`markdown`).Parse(markdownTemplate))
+	htmlSheet     = htmltemplate.Must(htmltemplate.New(`html`).Parse(htmlTemplate))
+)
+
+// ******** Public type functions ********
+
+// Write writes the exercise and the answer key in the given format to w.
+func (s *Sheet) Write(w io.Writer, format Format) error {
+	return s.write(w, format, exercisePart, answerPart)
+}
+
+// WriteExercise writes the exercise without the answer key in the given format to w.
+func (s *Sheet) WriteExercise(w io.Writer, format Format) error {
+	return s.write(w, format, exercisePart)
+}
+
+// WriteAnswerKey writes only the answer key in the given format to w.
+func (s *Sheet) WriteAnswerKey(w io.Writer, format Format) error {
+	return s.write(w, format, answerPart)
+}
+
+// ******** Private type functions ********
+
+// write writes a document with the given parts of the sheet in the given format to w.
+func (s *Sheet) write(w io.Writer, format Format, parts ...string) error {
+	var t templateExecutor
+	switch format {
+	case Markdown:
+		t = markdownSheet
+
+	case HTML:
+		t = htmlSheet
+
+	default:
+		return fmt.Errorf(`%s: %w`, format, ErrUnknownFormat)
+	}
+
+	view := s.view()
+	for _, part := range append(append([]string{headPart}, parts...), footPart) {
+		err := t.ExecuteTemplate(w, part, view)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// view prepares the data of the sheet for the templates.
+func (s *Sheet) view() *sheetView {
+	result := &sheetView{
+		Ciphertext:    s.layout(s.Ciphertext),
+		Solution:      s.layout(s.Solution),
+		RevealedCount: s.RevealedCount(),
+		LetterCount:   s.LetterCount(),
+		PartialKey:    make([]keyRow, letterCount),
+		AnswerKey:     make([]keyRow, letterCount),
+	}
+
+	result.Fence = markdownFence(result.Ciphertext + "\n" + result.Solution)
+
+	for i, homophones := range s.Homophones {
+		letter := string(rune(i + 'A'))
+		all := spaced(homophones)
+		if len(all) == 0 {
+			all = noHomophones
+		}
+
+		result.AnswerKey[i] = keyRow{Letter: letter, Homophones: all}
+
+		partial := unknownHomophones
+		if s.Revealed[i] {
+			partial = all
+		}
+
+		result.PartialKey[i] = keyRow{Letter: letter, Homophones: partial}
+	}
+
+	if s.Statistics != nil {
+		result.Frequencies = frequencyRows(s.Statistics.Alphabet(), s.Statistics.Count, s.Statistics.Total())
+	}
+
+	return result
+}
+
+// layout prepares a text for the output.
+// A text with other characters than letters keeps its lines.
+// A text with only letters is split into groups of letters.
+func (s *Sheet) layout(text string) string {
+	if s.KeepOthers {
+		return strings.TrimRight(text, "\r\n")
+	}
+
+	var result strings.Builder
+	for i := 0; i < len(text); i += groupLength {
+		if i != 0 {
+			if (i/groupLength)%groupsPerLine == 0 {
+				result.WriteByte('\n')
+			} else {
+				result.WriteByte(' ')
+			}
+		}
+
+		result.WriteString(text[i:min(i+groupLength, len(text))])
+	}
+
+	return result.String()
+}
+
+// ******** Private functions ********
+
+// frequencyRows returns the rows of the frequency table in the order of decreasing counts.
+func frequencyRows(alphabet string, count func(byte) uint, total uint) []frequencyRow {
+	symbols := []byte(alphabet)
+	slices.SortStableFunc(symbols, func(a, b byte) int {
+		switch ca, cb := count(a), count(b); {
+		case ca > cb:
+			return -1
+		case ca < cb:
+			return 1
+		default:
+			return 0
+		}
+	})
+
+	result := make([]frequencyRow, len(symbols))
+	for i, symbol := range symbols {
+		share := 0.0
+		if total != 0 {
+			share = float64(count(symbol)) / float64(total) * 100
+		}
+
+		result[i] = frequencyRow{Symbol: string(symbol), Count: count(symbol), Share: fmt.Sprintf(`%.2f%%`, share)}
+	}
+
+	return result
+}
+
+// spaced returns the characters separated by spaces.
+func spaced(characters []byte) string {
+	var result strings.Builder
+	for i, c := range characters {
+		if i != 0 {
+			result.WriteByte(' ')
+		}
+
+		result.WriteByte(c)
+	}
+
+	return result.String()
+}
+
+// markdownFence returns a code fence that is longer than any sequence of backticks in text.
+func markdownFence(text string) string {
+	longest := 0
+	current := 0
+	for i := 0; i < len(text); i++ {
+		if text[i] == '`' {
+			current++
+			longest = max(longest, current)
+		} else {
+			current = 0
+		}
+	}
+
+	return strings.Repeat("`", max(3, longest+1))
+}
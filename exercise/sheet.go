@@ -0,0 +1,156 @@
+//
+// SPDX-FileCopyrightText: Copyright 2026 Frank Schwab
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileType: SOURCE
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+//
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Author: Frank Schwab
+//
+// Version: 1.0.0
+//
+// Change history:
+//    2026-10-18: V1.0.0: Created.
+//
+
+// Package exercise creates cryptanalysis exercises with a homophonic substitution.
+// An exercise sheet contains the ciphertext, a partially revealed key, optional frequency tables
+// and an answer key with the complete key and the solution.
+package exercise
+
+import (
+	"bytes"
+	"errors"
+	"homophone/analysis"
+	"homophone/homosubst"
+	"io"
+	"math/rand/v2"
+)
+
+// ******** Public types ********
+
+// Options are the options for an exercise sheet.
+type Options struct {
+	// Reveal is the number of letters whose homophones are revealed.
+	// If it is larger than the number of letters of the clear text, all letters are revealed.
+	Reveal int
+	// KeepOthers indicates that characters that are not in the range A-Z are kept in the ciphertext.
+	KeepOthers bool
+	// Frequencies indicates that the sheet contains the frequencies of the ciphertext characters.
+	Frequencies bool
+}
+
+// Sheet is an exercise sheet.
+type Sheet struct {
+	// Ciphertext is the encrypted clear text.
+	Ciphertext string
+	// Solution is the decrypted ciphertext, i.e. the clear text in upper case.
+	Solution string
+	// Homophones contains the homophones of the letters A-Z.
+	Homophones [][]byte
+	// Revealed indicates for each letter A-Z whether its homophones are revealed.
+	Revealed []bool
+	// Statistics contains the frequencies of the ciphertext characters. It is nil, if they are not shown.
+	Statistics *analysis.Statistics
+	// KeepOthers indicates that the ciphertext contains characters that are not letters.
+	KeepOthers bool
+}
+
+// ******** Public constants ********
+
+// ErrInvalidReveal is returned when the number of revealed letters is negative.
+var ErrInvalidReveal = errors.New(`number of revealed letters must not be negative`)
+
+// ******** Private constants ********
+
+// letterCount is the number of letters A-Z.
+const letterCount = 26
+
+// ******** Public functions ********
+
+// New creates an exercise sheet by encrypting clearText with substitutor.
+// The letters whose homophones are revealed are chosen randomly among the letters that have homophones.
+func New(substitutor *homosubst.Substitutor, clearText io.Reader, options Options) (*Sheet, error) {
+	if options.Reveal < 0 {
+		return nil, ErrInvalidReveal
+	}
+
+	var ciphertext bytes.Buffer
+	err := substitutor.EncryptStream(clearText, &ciphertext, options.KeepOthers)
+	if err != nil {
+		return nil, err
+	}
+
+	var solution bytes.Buffer
+	err = substitutor.DecryptStream(bytes.NewReader(ciphertext.Bytes()), &solution)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &Sheet{
+		Ciphertext: ciphertext.String(),
+		Solution:   solution.String(),
+		Homophones: make([][]byte, letterCount),
+		Revealed:   make([]bool, letterCount),
+		KeepOthers: options.KeepOthers,
+	}
+
+	candidates := make([]int, 0, letterCount)
+	for i := range letterCount {
+		result.Homophones[i] = substitutor.Homophones(byte(i) + 'A')
+		if len(result.Homophones[i]) != 0 {
+			candidates = append(candidates, i)
+		}
+	}
+
+	rand.Shuffle(len(candidates), func(i, j int) { candidates[i], candidates[j] = candidates[j], candidates[i] })
+	for _, i := range candidates[:min(options.Reveal, len(candidates))] {
+		result.Revealed[i] = true
+	}
+
+	if options.Frequencies {
+		result.Statistics = analysis.NewStatistics(analysis.SubstitutionAlphabet)
+		_, _ = result.Statistics.Write(ciphertext.Bytes())
+	}
+
+	return result, nil
+}
+
+// ******** Public type functions ********
+
+// RevealedCount returns the number of letters whose homophones are revealed.
+func (s *Sheet) RevealedCount() int {
+	result := 0
+	for _, revealed := range s.Revealed {
+		if revealed {
+			result++
+		}
+	}
+
+	return result
+}
+
+// LetterCount returns the number of letters that have homophones.
+func (s *Sheet) LetterCount() int {
+	result := 0
+	for _, homophones := range s.Homophones {
+		if len(homophones) != 0 {
+			result++
+		}
+	}
+
+	return result
+}
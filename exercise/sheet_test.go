@@ -0,0 +1,203 @@
+//
+// SPDX-FileCopyrightText: Copyright 2026 Frank Schwab
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileType: SOURCE
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+//
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Author: Frank Schwab
+//
+// Version: 1.0.0
+//
+// Change history:
+//    2026-10-18: V1.0.0: Created.
+//
+
+package exercise
+
+import (
+	"bytes"
+	"errors"
+	"homophone/homosubst"
+	"strings"
+	"testing"
+)
+
+// ******** Private constants ********
+
+// testClearText is the clear text of the tests.
+const testClearText = "The quick brown fox jumps over the lazy dog.\nPack my box with <five> dozen liquor jugs!\n"
+
+// ******** Test functions ********
+
+func TestNew(t *testing.T) {
+	for _, reveal := range []int{0, 5, 26, 100} {
+		sheet := newTestSheet(t, Options{Reveal: reveal})
+
+		expected := min(reveal, 26)
+		if sheet.RevealedCount() != expected || sheet.LetterCount() != 26 {
+			t.Fatalf(`Reveal %d: %d of %d letters revealed instead of %d of 26`, reveal, sheet.RevealedCount(), sheet.LetterCount(), expected)
+		}
+
+		for i, revealed := range sheet.Revealed {
+			if revealed && len(sheet.Homophones[i]) == 0 {
+				t.Fatalf(`Reveal %d: Letter '%c' without homophones is revealed`, reveal, i+'A')
+			}
+		}
+	}
+
+	_, err := New(newTestSubstitutor(t), strings.NewReader(testClearText), Options{Reveal: -1})
+	if !errors.Is(err, ErrInvalidReveal) {
+		t.Fatalf(`Expected error '%v', got '%v'`, ErrInvalidReveal, err)
+	}
+}
+
+func TestWriteMarkdown(t *testing.T) {
+	sheet := newTestSheet(t, Options{Reveal: 3, Frequencies: true})
+	output := writeSheet(t, sheet, Markdown)
+
+	for _, expected := range []string{
+		"## Ciphertext\n\n```\n",
+		`The homophones of 3 of 26 letters are revealed.`,
+		"| A | ",
+		`## Frequencies of the ciphertext symbols`,
+		"## Answer key",
+		"```\nTHEQU ICKBR OWNFO XJUMP SOVER THELA ZYDOG PACKM YBOXW ITHFI\nVEDOZ ENLIQ UORJU GS\n```\n",
+	} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("Output does not contain '%s':\n%s", expected, output)
+		}
+	}
+
+	if strings.Count(output, " | "+unknownHomophones+" |") != 23 {
+		t.Errorf("Output does not contain 23 unrevealed letters:\n%s", output)
+	}
+}
+
+func TestWriteHTML(t *testing.T) {
+	sheet := newTestSheet(t, Options{Reveal: 1, KeepOthers: true})
+	output := writeSheet(t, sheet, HTML)
+
+	for _, expected := range []string{
+		`<h2>Ciphertext</h2>`,
+		`<div class="answer">`,
+		"PACK MY BOX WITH &lt;FIVE&gt; DOZEN LIQUOR JUGS!</pre>",
+	} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("Output does not contain '%s':\n%s", expected, output)
+		}
+	}
+
+	if strings.Contains(output, `Frequencies`) {
+		t.Errorf("Output contains frequencies:\n%s", output)
+	}
+}
+
+func TestWriteParts(t *testing.T) {
+	sheet := newTestSheet(t, Options{Reveal: 2})
+
+	for _, format := range []Format{Markdown, HTML} {
+		var exercise, answerKey bytes.Buffer
+		if err := sheet.WriteExercise(&exercise, format); err != nil {
+			t.Fatalf(`%s: Error writing exercise: %v`, format, err)
+		}
+
+		if err := sheet.WriteAnswerKey(&answerKey, format); err != nil {
+			t.Fatalf(`%s: Error writing answer key: %v`, format, err)
+		}
+
+		if strings.Contains(exercise.String(), `Answer key`) || !strings.Contains(exercise.String(), `Partial key`) {
+			t.Errorf("%s: Wrong exercise:\n%s", format, exercise.String())
+		}
+
+		if !strings.Contains(answerKey.String(), `Answer key`) || strings.Contains(answerKey.String(), `Partial key`) {
+			t.Errorf("%s: Wrong answer key:\n%s", format, answerKey.String())
+		}
+
+		if !strings.Contains(answerKey.String(), `Homophonic substitution exercise`) {
+			t.Errorf("%s: Answer key has no title:\n%s", format, answerKey.String())
+		}
+	}
+}
+
+func TestMarkdownFence(t *testing.T) {
+	for text, expected := range map[string]string{
+		`no backticks`:  "```",
+		"a ``` b":       "````",
+		"a ````` b ` c": "``````",
+	} {
+		if got := markdownFence(text); got != expected {
+			t.Errorf(`'%s': Expected fence '%s', got '%s'`, text, expected, got)
+		}
+	}
+}
+
+func TestFormat(t *testing.T) {
+	for _, name := range FormatNames() {
+		format, err := ParseFormat(strings.ToUpper(name))
+		if err != nil || format.String() != name {
+			t.Fatalf(`'%s': Parsed as '%s' with error %v`, name, format, err)
+		}
+	}
+
+	_, err := ParseFormat(`pdf`)
+	if !errors.Is(err, ErrUnknownFormat) {
+		t.Fatalf(`Expected error '%v', got '%v'`, ErrUnknownFormat, err)
+	}
+
+	if FormatFromFileName(`sheet.HTM`) != HTML || FormatFromFileName(`sheet.md`) != Markdown || FormatFromFileName(`sheet`) != Markdown {
+		t.Fatal(`Wrong format from file name`)
+	}
+}
+
+// ******** Private functions ********
+
+// newTestSubstitutor creates a substitutor for the test clear text.
+func newTestSubstitutor(t *testing.T) *homosubst.Substitutor {
+	t.Helper()
+
+	s, err := homosubst.NewSubstitutorFromReader(strings.NewReader(testClearText), homosubst.Options{})
+	if err != nil {
+		t.Fatalf(`Error creating substitutor: %v`, err)
+	}
+
+	return s
+}
+
+// newTestSheet creates a sheet for the test clear text.
+func newTestSheet(t *testing.T, options Options) *Sheet {
+	t.Helper()
+
+	sheet, err := New(newTestSubstitutor(t), strings.NewReader(testClearText), options)
+	if err != nil {
+		t.Fatalf(`Error creating sheet: %v`, err)
+	}
+
+	return sheet
+}
+
+// writeSheet writes a sheet and returns the output.
+func writeSheet(t *testing.T, sheet *Sheet, format Format) string {
+	t.Helper()
+
+	var buffer bytes.Buffer
+	err := sheet.Write(&buffer, format)
+	if err != nil {
+		t.Fatalf(`Error writing sheet: %v`, err)
+	}
+
+	return buffer.String()
+}
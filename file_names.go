@@ -20,10 +20,11 @@
 //
 // Author: Frank Schwab
 //
-// Version: 1.0.0
+// Version: 1.1.0
 //
 // Change history:
 //    2025-01-02: V1.0.0: Created.
+//    2026-10-18: V1.1.0: Add exercise file name.
 //
 
 package main
@@ -39,6 +40,9 @@ import (
 // decryptedMarker is the last part of the default file name for an decrypted file.
 const decryptedMarker = `_decrypted`
 
+// exerciseMarker is the last part of the default file name for an exercise sheet.
+const exerciseMarker = `_exercise`
+
 // homophoneMarker is the last part of the default file name for an encrypted file.
 const homophoneMarker = `_homophone`
 
@@ -54,6 +58,13 @@ func buildEncryptOutFilePath(filePath string) string {
 	return buildFilePathWithMarker(filePath, homophoneMarker)
 }
 
+// buildExerciseOutFilePath builds the file path of an exercise sheet with the given extension.
+func buildExerciseOutFilePath(filePath string, ext string) string {
+	dir, base, _ := cleanPathComponents(filePath)
+
+	return filepath.Join(dir, base+exerciseMarker+ext)
+}
+
 // buildSubstFilePath builds the file path of the substitution file.
 func buildSubstFilePath(filePath string) string {
 	dir, base, ext := cleanPathComponents(filePath)
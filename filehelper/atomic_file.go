@@ -0,0 +1,257 @@
+//
+// SPDX-FileCopyrightText: Copyright 2026 Frank Schwab
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileType: SOURCE
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+//
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Author: Frank Schwab
+//
+// Version: 1.2.0
+//
+// Change history:
+//    2026-10-18: V1.0.0: Created.
+//    2026-10-18: V1.1.0: Do not replace a file that is created before the commit.
+//    2026-10-18: V1.2.0: Copy the file, if the file system does not support hard links.
+//
+
+package filehelper
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"syscall"
+)
+
+// ******** Public types ********
+
+// AtomicFile is a file that is written to a temporary file in the directory of the destination file.
+// The temporary file is moved to the destination file by [AtomicFile.Commit].
+// If the file is closed without a commit, the temporary file is removed and the destination file is untouched.
+type AtomicFile struct {
+	*os.File
+	destinationName string
+	overwrite       bool
+	committed       bool
+}
+
+// ******** Public constants ********
+
+// ErrFileExists is returned when a file should be written that already exists and overwriting is not allowed.
+var ErrFileExists = errors.New(`file already exists`)
+
+// ******** Private constants ********
+
+// tempFilePattern is the pattern of the temporary file name.
+const tempFilePattern = `.%s.*.tmp`
+
+// ******** Private variables ********
+
+// linkFile creates a hard link. It is a variable, so that tests can simulate file systems without hard links.
+var linkFile = os.Link
+
+// ******** Public creation functions ********
+
+// CreateAtomic creates a new temporary file for the destination file with the specified permissions.
+// If overwrite is false, an existing destination file results in an [ErrFileExists] error.
+func CreateAtomic(destinationName string, perm os.FileMode, overwrite bool) (*AtomicFile, error) {
+	err := checkOverwrite(destinationName, overwrite)
+	if err != nil {
+		return nil, err
+	}
+
+	var file *os.File
+	file, err = os.CreateTemp(filepath.Dir(destinationName), fmt.Sprintf(tempFilePattern, filepath.Base(destinationName)))
+	if err != nil {
+		return nil, err
+	}
+
+	err = file.Chmod(perm)
+	if err != nil {
+		_ = file.Close()
+		_ = os.Remove(file.Name())
+		return nil, err
+	}
+
+	return &AtomicFile{
+		File:            file,
+		destinationName: destinationName,
+		overwrite:       overwrite,
+	}, nil
+}
+
+// ******** Public type functions ********
+
+// Commit closes the temporary file and moves it to the destination file.
+// If overwrite is false and the destination file has been created in the meantime, an [ErrFileExists] error is returned.
+func (a *AtomicFile) Commit() error {
+	if a.committed {
+		return os.ErrClosed
+	}
+
+	tempName := a.File.Name()
+
+	err := a.File.Sync()
+	if err == nil {
+		err = a.File.Close()
+	} else {
+		_ = a.File.Close()
+	}
+
+	if err == nil {
+		err = a.moveToDestination(tempName)
+	}
+
+	if err != nil {
+		_ = os.Remove(tempName)
+	}
+
+	a.committed = true
+
+	return err
+}
+
+// Close closes and removes the temporary file, if it has not been committed.
+// It does nothing, if the file has already been committed.
+func (a *AtomicFile) Close() error {
+	if a.committed {
+		return nil
+	}
+
+	a.committed = true
+
+	tempName := a.File.Name()
+	err := a.File.Close()
+	removeErr := os.Remove(tempName)
+	if err != nil {
+		return err
+	}
+
+	return removeErr
+}
+
+// Name returns the name of the destination file.
+func (a *AtomicFile) Name() string {
+	return a.destinationName
+}
+
+// ******** Private type functions ********
+
+// moveToDestination moves the temporary file to the destination file.
+// If overwrite is false, the temporary file is linked to the destination name and then removed.
+// Other than a check followed by a rename, the link fails atomically if the destination file exists,
+// so a file that is created by another process in the meantime is never replaced.
+// If the file system does not support hard links, the temporary file is copied to the destination file instead.
+func (a *AtomicFile) moveToDestination(tempName string) error {
+	if a.overwrite {
+		return os.Rename(tempName, a.destinationName)
+	}
+
+	err := linkFile(tempName, a.destinationName)
+	if err != nil {
+		if errors.Is(err, fs.ErrExist) {
+			return fmt.Errorf(`'%s': %w`, a.destinationName, ErrFileExists)
+		}
+
+		if !isLinkUnsupported(err) {
+			return err
+		}
+
+		err = a.copyToDestination(tempName)
+		if err != nil {
+			return err
+		}
+	}
+
+	return os.Remove(tempName)
+}
+
+// copyToDestination copies the temporary file to the destination file, which must not exist.
+// The destination file is created exclusively, so a file that is created by another process
+// in the meantime is never replaced. Other than a link, the copy is not atomic, so
+// an incomplete destination file is removed, if the copy fails.
+func (a *AtomicFile) copyToDestination(tempName string) error {
+	source, err := os.Open(tempName)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = source.Close() }()
+
+	var fi os.FileInfo
+	fi, err = source.Stat()
+	if err != nil {
+		return err
+	}
+
+	var destination *os.File
+	destination, err = os.OpenFile(a.destinationName, os.O_WRONLY|os.O_CREATE|os.O_EXCL, fi.Mode().Perm())
+	if err != nil {
+		if errors.Is(err, fs.ErrExist) {
+			return fmt.Errorf(`'%s': %w`, a.destinationName, ErrFileExists)
+		}
+
+		return err
+	}
+
+	_, err = io.Copy(destination, source)
+	if err == nil {
+		err = destination.Sync()
+	}
+
+	if err == nil {
+		err = destination.Close()
+	} else {
+		_ = destination.Close()
+	}
+
+	if err != nil {
+		_ = os.Remove(a.destinationName)
+	}
+
+	return err
+}
+
+// ******** Private functions ********
+
+// isLinkUnsupported checks whether err indicates that the file system cannot create a hard link,
+// e.g. because it does not support them or because the files are on different devices.
+func isLinkUnsupported(err error) bool {
+	return errors.Is(err, errors.ErrUnsupported) ||
+		errors.Is(err, syscall.EPERM) ||
+		errors.Is(err, syscall.EXDEV)
+}
+
+// checkOverwrite checks if the destination file may be written.
+func checkOverwrite(destinationName string, overwrite bool) error {
+	if overwrite {
+		return nil
+	}
+
+	exists, err := FileExists(destinationName)
+	if err != nil {
+		return err
+	}
+
+	if exists {
+		return fmt.Errorf(`'%s': %w`, destinationName, ErrFileExists)
+	}
+
+	return nil
+}
@@ -0,0 +1,258 @@
+//
+// SPDX-FileCopyrightText: Copyright 2026 Frank Schwab
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileType: SOURCE
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+//
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Author: Frank Schwab
+//
+// Version: 1.0.0
+//
+// Change history:
+//    2026-10-18: V1.0.0: Created.
+//
+
+package filehelper
+
+import (
+	"errors"
+	"homophone/oshelper"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+)
+
+// ******** Test functions ********
+
+func TestAtomicFileCommit(t *testing.T) {
+	fileName := filepath.Join(t.TempDir(), `out.txt`)
+
+	f, err := CreateAtomic(fileName, 0600, false)
+	if err != nil {
+		t.Fatalf(`Error creating file: %v`, err)
+	}
+
+	_, err = f.WriteString(`data`)
+	if err != nil {
+		t.Fatalf(`Error writing file: %v`, err)
+	}
+
+	if exists, _ := FileExists(fileName); exists {
+		t.Fatal(`File exists before commit`)
+	}
+
+	err = f.Commit()
+	if err != nil {
+		t.Fatalf(`Error committing file: %v`, err)
+	}
+
+	expectFileContent(t, fileName, `data`)
+	expectOnlyFile(t, fileName)
+}
+
+func TestAtomicFileCloseWithoutCommit(t *testing.T) {
+	fileName := filepath.Join(t.TempDir(), `out.txt`)
+	err := os.WriteFile(fileName, []byte(`old`), 0600)
+	if err != nil {
+		t.Fatalf(`Error writing file: %v`, err)
+	}
+
+	var f *AtomicFile
+	f, err = CreateAtomic(fileName, 0600, true)
+	if err != nil {
+		t.Fatalf(`Error creating file: %v`, err)
+	}
+
+	_, _ = f.WriteString(`new`)
+
+	err = f.Close()
+	if err != nil {
+		t.Fatalf(`Error closing file: %v`, err)
+	}
+
+	expectFileContent(t, fileName, `old`)
+	expectOnlyFile(t, fileName)
+}
+
+func TestAtomicFileNoOverwrite(t *testing.T) {
+	fileName := filepath.Join(t.TempDir(), `out.txt`)
+	err := os.WriteFile(fileName, []byte(`old`), 0600)
+	if err != nil {
+		t.Fatalf(`Error writing file: %v`, err)
+	}
+
+	_, err = CreateAtomic(fileName, 0600, false)
+	if !errors.Is(err, ErrFileExists) {
+		t.Fatalf(`Expected error '%v', got '%v'`, ErrFileExists, err)
+	}
+}
+
+func TestAtomicFileCreatedBeforeCommit(t *testing.T) {
+	fileName := filepath.Join(t.TempDir(), `out.txt`)
+
+	f, err := CreateAtomic(fileName, 0600, false)
+	if err != nil {
+		t.Fatalf(`Error creating file: %v`, err)
+	}
+
+	_, _ = f.WriteString(`new`)
+
+	err = os.WriteFile(fileName, []byte(`other`), 0600)
+	if err != nil {
+		t.Fatalf(`Error writing file: %v`, err)
+	}
+
+	err = f.Commit()
+	if !errors.Is(err, ErrFileExists) {
+		t.Fatalf(`Expected error '%v', got '%v'`, ErrFileExists, err)
+	}
+
+	expectFileContent(t, fileName, `other`)
+	expectOnlyFile(t, fileName)
+}
+
+func TestAtomicFileWithoutLinks(t *testing.T) {
+	withoutLinks(t)
+
+	fileName := filepath.Join(t.TempDir(), `out.txt`)
+
+	f, err := CreateAtomic(fileName, 0600, false)
+	if err != nil {
+		t.Fatalf(`Error creating file: %v`, err)
+	}
+
+	_, _ = f.WriteString(`data`)
+
+	err = f.Commit()
+	if err != nil {
+		t.Fatalf(`Error committing file: %v`, err)
+	}
+
+	expectFileContent(t, fileName, `data`)
+	expectOnlyFile(t, fileName)
+
+	var fi os.FileInfo
+	fi, err = os.Stat(fileName)
+	if err != nil {
+		t.Fatalf(`Error getting file info: %v`, err)
+	}
+
+	if oshelper.HasFilePermissions && fi.Mode().Perm() != 0600 {
+		t.Fatalf(`Copied file has permissions %04o instead of 0600`, fi.Mode().Perm())
+	}
+}
+
+func TestAtomicFileWithoutLinksCreatedBeforeCommit(t *testing.T) {
+	withoutLinks(t)
+
+	fileName := filepath.Join(t.TempDir(), `out.txt`)
+
+	f, err := CreateAtomic(fileName, 0600, false)
+	if err != nil {
+		t.Fatalf(`Error creating file: %v`, err)
+	}
+
+	_, _ = f.WriteString(`new`)
+
+	err = os.WriteFile(fileName, []byte(`other`), 0600)
+	if err != nil {
+		t.Fatalf(`Error writing file: %v`, err)
+	}
+
+	err = f.Commit()
+	if !errors.Is(err, ErrFileExists) {
+		t.Fatalf(`Expected error '%v', got '%v'`, ErrFileExists, err)
+	}
+
+	expectFileContent(t, fileName, `other`)
+	expectOnlyFile(t, fileName)
+}
+
+func TestIsSameFile(t *testing.T) {
+	dir := t.TempDir()
+	fileName := filepath.Join(dir, `in.txt`)
+	err := os.WriteFile(fileName, []byte(`data`), 0600)
+	if err != nil {
+		t.Fatalf(`Error writing file: %v`, err)
+	}
+
+	linkName := filepath.Join(dir, `link.txt`)
+	err = os.Symlink(fileName, linkName)
+	if err != nil {
+		t.Skipf(`Symbolic links are not supported: %v`, err)
+	}
+
+	expectSameFile(t, fileName, filepath.Join(dir, `.`, `in.txt`), true)
+	expectSameFile(t, fileName, linkName, true)
+	expectSameFile(t, fileName, filepath.Join(dir, `other.txt`), false)
+}
+
+// ******** Private functions ********
+
+// withoutLinks simulates a file system without hard links for the rest of the test.
+func withoutLinks(t *testing.T) {
+	t.Helper()
+
+	saved := linkFile
+	linkFile = func(oldName string, newName string) error {
+		return &os.LinkError{Op: `link`, Old: oldName, New: newName, Err: syscall.EXDEV}
+	}
+	t.Cleanup(func() { linkFile = saved })
+}
+
+// expectFileContent checks that a file has the expected content.
+func expectFileContent(t *testing.T, fileName string, expected string) {
+	t.Helper()
+
+	content, err := os.ReadFile(fileName)
+	if err != nil {
+		t.Fatalf(`Error reading file: %v`, err)
+	}
+
+	if string(content) != expected {
+		t.Fatalf(`Expected content '%s', got '%s'`, expected, content)
+	}
+}
+
+// expectOnlyFile checks that the file is the only file in its directory.
+func expectOnlyFile(t *testing.T, fileName string) {
+	t.Helper()
+
+	entries, err := os.ReadDir(filepath.Dir(fileName))
+	if err != nil {
+		t.Fatalf(`Error reading directory: %v`, err)
+	}
+
+	if len(entries) != 1 || entries[0].Name() != filepath.Base(fileName) {
+		t.Fatalf(`Expected only file '%s', got %v`, filepath.Base(fileName), entries)
+	}
+}
+
+// expectSameFile checks the result of [IsSameFile].
+func expectSameFile(t *testing.T, fileName1 string, fileName2 string, expected bool) {
+	t.Helper()
+
+	isSame, err := IsSameFile(fileName1, fileName2)
+	if err != nil {
+		t.Fatalf(`Error comparing files: %v`, err)
+	}
+
+	if isSame != expected {
+		t.Fatalf(`Expected '%s' and '%s' to be the same file: %t, got %t`, fileName1, fileName2, expected, isSame)
+	}
+}
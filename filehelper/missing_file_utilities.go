@@ -20,17 +20,19 @@
 //
 // Author: Frank Schwab
 //
-// Version: 1.1.0
+// Version: 1.2.0
 //
 // Change history:
 //    2025-01-02: V1.0.0: Created.
 //    2025-01-03: V1.1.0: Added "PathComponents".
+//    2026-10-18: V1.2.0: Added "FileExists" and "IsSameFile".
 //
 
 // Package filehelper contains file utilities missing from the Go base libraries.
 package filehelper
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -59,8 +61,67 @@ func PathComponents(filePath string) (string, string, string) {
 	return dir, base, ext
 }
 
+// FileExists checks if a file exists.
+// A symbolic link counts as an existing file, even if its target does not exist.
+func FileExists(filePath string) (bool, error) {
+	_, err := os.Lstat(filePath)
+	if err == nil {
+		return true, nil
+	}
+
+	if errors.Is(err, os.ErrNotExist) {
+		return false, nil
+	}
+
+	return false, err
+}
+
+// IsSameFile checks if two file paths denote the same file.
+// Symbolic links are followed. Paths of files that do not exist
+// are the same, if they have the same absolute path.
+func IsSameFile(filePath1 string, filePath2 string) (bool, error) {
+	absPath1, err := filepath.Abs(filePath1)
+	if err != nil {
+		return false, err
+	}
+
+	var absPath2 string
+	absPath2, err = filepath.Abs(filePath2)
+	if err != nil {
+		return false, err
+	}
+
+	if absPath1 == absPath2 {
+		return true, nil
+	}
+
+	var fi1 os.FileInfo
+	fi1, err = os.Stat(absPath1)
+	if err != nil {
+		return ignoreNotExist(err)
+	}
+
+	var fi2 os.FileInfo
+	fi2, err = os.Stat(absPath2)
+	if err != nil {
+		return ignoreNotExist(err)
+	}
+
+	return os.SameFile(fi1, fi2), nil
+}
+
 // ******** Private functions ********
 
+// ignoreNotExist returns "false" and no error, if the error is a "file does not exist" error.
+// Otherwise, it returns "false" and the error.
+func ignoreNotExist(err error) (bool, error) {
+	if errors.Is(err, os.ErrNotExist) {
+		return false, nil
+	}
+
+	return false, err
+}
+
 // printCloseOperationError prints an error message for a file operation.
 func printCloseOperationError(name string, err error) {
 	_, _ = fmt.Fprintf(os.Stderr, `Error closing '%s': %v`, name, err)
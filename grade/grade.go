@@ -0,0 +1,387 @@
+//
+// SPDX-FileCopyrightText: Copyright 2026 Frank Schwab
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileType: SOURCE
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+//
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Author: Frank Schwab
+//
+// Version: 1.1.0
+//
+// Change history:
+//    2026-10-18: V1.0.0: Created.
+//    2026-10-18: V1.1.0: Align the plaintexts, so missing and additional characters do not shift the comparison.
+//
+
+// Package grade grades the solutions of students for a ciphertext.
+// A solution is either a recovered plaintext or a recovered key.
+package grade
+
+import (
+	"bytes"
+	"fmt"
+	"homophone/homosubst"
+	"io"
+	"slices"
+)
+
+// ******** Public types ********
+
+// LetterResult is the result for one letter of the clear text.
+type LetterResult struct {
+	// Letter is the letter in the range A-Z.
+	Letter byte
+	// Total is the number of homophones of the letter for a key, or the number of its occurrences for a plaintext.
+	Total int
+	// Correct is the number of homophones or occurrences the student got right.
+	Correct int
+}
+
+// Result is the result of a grading.
+type Result struct {
+	// Kind is the kind of the answer, i.e. [Plaintext] or [KeyAnswer].
+	Kind Kind
+	// Expected is the correct plaintext, only with letters in upper case.
+	Expected []byte
+	// Answer is the plaintext of the student, only with letters in upper case.
+	// For a key it is the ciphertext decrypted with the key. Unknown characters are [UnknownLetter].
+	Answer []byte
+	// Correct is the number of correct characters of the answer.
+	Correct int
+	// Letters contains the results for the letters that occur in the plaintext or the key.
+	Letters []LetterResult
+	// KeySymbols is the number of substitution characters of the key. It is 0 for a plaintext.
+	KeySymbols int
+	// CorrectSymbols is the number of substitution characters that the student assigned to the correct letter.
+	CorrectSymbols int
+	// WrongSymbols is the number of substitution characters that the student assigned to a wrong letter.
+	WrongSymbols int
+
+	// alignedExpected and alignedAnswer are the plaintexts with [missingCharacter] at the positions
+	// of characters that are missing in the other plaintext. They have the same length.
+	alignedExpected []byte
+	alignedAnswer   []byte
+}
+
+// ******** Public constants ********
+
+// UnknownLetter replaces a character that is not in the key of a student.
+const UnknownLetter = '?'
+
+// ******** Private constants ********
+
+// missingCharacter is shown in a diff for a character that is missing.
+const missingCharacter = '-'
+
+// Steps of the alignment of two plaintexts.
+const (
+	// stepPair pairs a character of the expected plaintext with a character of the answer.
+	stepPair byte = iota
+	// stepMissing is a character of the expected plaintext that is missing in the answer.
+	stepMissing
+	// stepAdditional is a character of the answer that is not in the expected plaintext.
+	stepAdditional
+)
+
+// letterCount is the number of letters A-Z.
+const letterCount = 26
+
+// ******** Public functions ********
+
+// Grade grades an answer for a ciphertext that has been encrypted with substitutor.
+// The plaintexts are normalized as the encryption does, i.e. only the letters are compared in upper case.
+// A key answer is compared with the substitutions of the letters without a predecessor.
+func Grade(substitutor *homosubst.Substitutor, ciphertext []byte, answer []byte, kind Kind) (*Result, error) {
+	if kind == Auto {
+		kind = DetectKind(answer)
+	}
+
+	var decrypted bytes.Buffer
+	err := substitutor.DecryptStream(bytes.NewReader(ciphertext), &decrypted)
+	if err != nil {
+		return nil, err
+	}
+
+	homophones := make([][]byte, letterCount)
+	var realLetters [256]byte
+	for i := range homophones {
+		homophones[i] = substitutor.Homophones(byte(i) + 'A')
+		for _, symbol := range homophones[i] {
+			realLetters[symbol] = byte(i) + 'A'
+		}
+	}
+
+	result := &Result{Kind: kind}
+	for i, b := range ciphertext {
+		if realLetters[b] != 0 {
+			result.Expected = append(result.Expected, decrypted.Bytes()[i])
+		}
+	}
+
+	switch kind {
+	case Plaintext:
+		result.Answer = normalize(answer)
+		result.alignedExpected, result.alignedAnswer = align(result.Expected, result.Answer)
+		result.gradePlaintext()
+
+	case KeyAnswer:
+		var key *Key
+		key, err = ParseKey(answer)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, b := range ciphertext {
+			if realLetters[b] != 0 {
+				result.Answer = append(result.Answer, letterOrUnknown(key.Letter(b)))
+			}
+		}
+
+		// The answer has a character for each character of the ciphertext, so it is already aligned.
+		result.alignedExpected, result.alignedAnswer = result.Expected, result.Answer
+		result.countCorrect()
+		result.gradeKey(key, homophones, &realLetters)
+
+	default:
+		return nil, fmt.Errorf(`%s: %w`, kind, ErrUnknownKind)
+	}
+
+	return result, nil
+}
+
+// ******** Public type functions ********
+
+// Total returns the number of characters that are compared. It is the length of the aligned plaintexts,
+// so missing and additional characters are errors.
+func (r *Result) Total() int {
+	return len(r.alignedExpected)
+}
+
+// Accuracy returns the share of correct plaintext characters between 0 and 1. It is the score of the answer.
+func (r *Result) Accuracy() float64 {
+	if r.Total() == 0 {
+		return 1
+	}
+
+	return float64(r.Correct) / float64(r.Total())
+}
+
+// KeyAccuracy returns the share of the substitution characters that are assigned to the correct letter.
+// It is 0 for a plaintext answer.
+func (r *Result) KeyAccuracy() float64 {
+	if r.KeySymbols == 0 {
+		return 0
+	}
+
+	return float64(r.CorrectSymbols) / float64(r.KeySymbols)
+}
+
+// WriteDiff writes the lines of the aligned plaintexts with differences in blocks of width characters.
+// The expected line is followed by the line of the answer and a line that marks the differences with '^'.
+// Characters that are missing in one of the plaintexts are shown as '-'.
+// It returns the number of blocks with differences.
+func (r *Result) WriteDiff(w io.Writer, width int) int {
+	width = max(width, 1)
+
+	result := 0
+	for start := 0; start < r.Total(); start += width {
+		end := min(start+width, r.Total())
+
+		expected := r.alignedExpected[start:end]
+		answer := r.alignedAnswer[start:end]
+		if bytes.Equal(expected, answer) {
+			continue
+		}
+
+		markers := bytes.Repeat([]byte{' '}, end-start)
+		for i := range markers {
+			if expected[i] != answer[i] {
+				markers[i] = '^'
+			}
+		}
+
+		_, _ = fmt.Fprintf(w, "%6d: %s\n", start+1, expected)
+		_, _ = fmt.Fprintf(w, "        %s\n", answer)
+		_, _ = fmt.Fprintf(w, "        %s\n", bytes.TrimRight(markers, ` `))
+		result++
+	}
+
+	return result
+}
+
+// ******** Private type functions ********
+
+// gradePlaintext grades an aligned plaintext answer.
+func (r *Result) gradePlaintext() {
+	r.countCorrect()
+
+	totals := make([]int, letterCount)
+	corrects := make([]int, letterCount)
+	for i, b := range r.alignedExpected {
+		if b == missingCharacter {
+			continue
+		}
+
+		index := b - 'A'
+		totals[index]++
+		if r.alignedAnswer[i] == b {
+			corrects[index]++
+		}
+	}
+
+	for i, total := range totals {
+		if total != 0 {
+			r.Letters = append(r.Letters, LetterResult{Letter: byte(i) + 'A', Total: total, Correct: corrects[i]})
+		}
+	}
+}
+
+// gradeKey grades a key answer.
+func (r *Result) gradeKey(key *Key, homophones [][]byte, realLetters *[256]byte) {
+	for i, symbols := range homophones {
+		if len(symbols) == 0 {
+			continue
+		}
+
+		letter := byte(i) + 'A'
+		letterResult := LetterResult{Letter: letter, Total: len(symbols)}
+		for _, symbol := range symbols {
+			if key.Letter(symbol) == letter {
+				letterResult.Correct++
+			}
+		}
+
+		r.Letters = append(r.Letters, letterResult)
+		r.KeySymbols += letterResult.Total
+		r.CorrectSymbols += letterResult.Correct
+	}
+
+	for symbol, letter := range key.letters {
+		if letter != 0 && letter != realLetters[symbol] {
+			r.WrongSymbols++
+		}
+	}
+}
+
+// countCorrect counts the correct characters of the aligned answer.
+func (r *Result) countCorrect() {
+	for i, b := range r.alignedExpected {
+		if b != missingCharacter && r.alignedAnswer[i] == b {
+			r.Correct++
+		}
+	}
+}
+
+// ******** Private functions ********
+
+// normalize returns the letters of a text in upper case, as the encryption does.
+func normalize(text []byte) []byte {
+	result := make([]byte, 0, len(text))
+	for _, b := range text {
+		switch {
+		case b >= 'a' && b <= 'z':
+			result = append(result, b^('a'^'A'))
+
+		case b >= 'A' && b <= 'Z':
+			result = append(result, b)
+		}
+	}
+
+	return result
+}
+
+// letterOrUnknown returns the letter, or [UnknownLetter], if it is 0.
+func letterOrUnknown(letter byte) byte {
+	if letter == 0 {
+		return UnknownLetter
+	}
+
+	return letter
+}
+
+// align aligns the answer with the expected plaintext with the fewest edits, i.e. wrong, missing and
+// additional characters, so a missing or additional character does not shift the rest of the answer.
+// The aligned plaintexts have [missingCharacter] where a character is missing.
+// The memory needed is proportional to the product of the lengths of the plaintexts.
+func align(expected []byte, answer []byte) ([]byte, []byte) {
+	columns := len(answer) + 1
+	steps := make([]byte, (len(expected)+1)*columns)
+
+	// Edit distances of the prefixes of the expected plaintext with the previous and the actual length.
+	previous := make([]int, columns)
+	actual := make([]int, columns)
+	for j := 1; j < columns; j++ {
+		previous[j] = j
+		steps[j] = stepAdditional
+	}
+
+	for i := 1; i <= len(expected); i++ {
+		actual[0] = i
+		steps[i*columns] = stepMissing
+		for j := 1; j < columns; j++ {
+			distance := previous[j-1]
+			step := stepPair
+			if expected[i-1] != answer[j-1] {
+				distance++
+			}
+
+			if previous[j]+1 < distance {
+				distance = previous[j] + 1
+				step = stepMissing
+			}
+
+			if actual[j-1]+1 < distance {
+				distance = actual[j-1] + 1
+				step = stepAdditional
+			}
+
+			actual[j] = distance
+			steps[i*columns+j] = step
+		}
+
+		previous, actual = actual, previous
+	}
+
+	// Follow the steps back from the end of both plaintexts.
+	alignedExpected := make([]byte, 0, max(len(expected), len(answer)))
+	alignedAnswer := make([]byte, 0, cap(alignedExpected))
+	for i, j := len(expected), len(answer); i > 0 || j > 0; {
+		e, a := byte(missingCharacter), byte(missingCharacter)
+		switch steps[i*columns+j] {
+		case stepPair:
+			i--
+			j--
+			e, a = expected[i], answer[j]
+
+		case stepMissing:
+			i--
+			e = expected[i]
+
+		case stepAdditional:
+			j--
+			a = answer[j]
+		}
+
+		alignedExpected = append(alignedExpected, e)
+		alignedAnswer = append(alignedAnswer, a)
+	}
+
+	slices.Reverse(alignedExpected)
+	slices.Reverse(alignedAnswer)
+
+	return alignedExpected, alignedAnswer
+}
@@ -0,0 +1,222 @@
+//
+// SPDX-FileCopyrightText: Copyright 2026 Frank Schwab
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileType: SOURCE
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+//
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Author: Frank Schwab
+//
+// Version: 1.1.0
+//
+// Change history:
+//    2026-10-18: V1.0.0: Created.
+//    2026-10-18: V1.1.0: Test missing and additional letters.
+//
+
+package grade
+
+import (
+	"bytes"
+	"errors"
+	"homophone/homosubst"
+	"strings"
+	"testing"
+)
+
+// ******** Private constants ********
+
+// testClearText is the clear text of the tests.
+const testClearText = "The quick brown fox jumps over the lazy dog.\nPack my box with five dozen liquor jugs!\n"
+
+// ******** Test functions ********
+
+func TestParseKey(t *testing.T) {
+	for _, text := range []string{
+		"# Key\nE: a b\n\nt = X,y\n",
+		"   E (  12.70%): ab\n   T (   9.06%): Xy\n",
+		`{"E": "ab", "t": ["X", "y"]}`,
+	} {
+		key, err := ParseKey([]byte(text))
+		if err != nil {
+			t.Fatalf(`'%s': Error parsing key: %v`, text, err)
+		}
+
+		if key.Letter('a') != 'E' || key.Letter('b') != 'E' || key.Letter('X') != 'T' || key.Letter('y') != 'T' || key.Letter('c') != 0 {
+			t.Fatalf(`'%s': Wrong key`, text)
+		}
+
+		if DetectKind([]byte(text)) != KeyAnswer {
+			t.Fatalf(`'%s': Not detected as key`, text)
+		}
+	}
+
+	for _, text := range []string{
+		"E: ab\nT: b\n",
+		"E: a1\n",
+		"Dear Sir: hello\n",
+		`{"E": 1}`,
+		`{"EE": "a"}`,
+	} {
+		_, err := ParseKey([]byte(text))
+		if !errors.Is(err, ErrInvalidKey) {
+			t.Fatalf(`'%s': Expected error '%v', got '%v'`, text, ErrInvalidKey, err)
+		}
+	}
+
+	if DetectKind([]byte("Dear Sir: hello\n")) != Plaintext || DetectKind([]byte(``)) != Plaintext {
+		t.Fatal(`Plaintext detected as key`)
+	}
+}
+
+func TestGradePlaintext(t *testing.T) {
+	s, ciphertext := encryptTestText(t)
+
+	result := grade(t, s, ciphertext, testClearText, Auto)
+	if result.Kind != Plaintext || result.Accuracy() != 1 || result.KeySymbols != 0 {
+		t.Fatalf(`Correct plaintext has kind %s and accuracy %f`, result.Kind, result.Accuracy())
+	}
+
+	if result.WriteDiff(&bytes.Buffer{}, 60) != 0 {
+		t.Fatal(`Correct plaintext has differences`)
+	}
+
+	// One wrong and one missing letter.
+	answer := strings.Replace(testClearText, `quick`, `quack`, 1)
+	answer = strings.TrimSuffix(answer, "s!\n")
+	result = grade(t, s, ciphertext, answer, Plaintext)
+	total := len(normalize([]byte(testClearText)))
+	if result.Correct != total-2 || result.Total() != total {
+		t.Fatalf(`Expected %d of %d correct, got %d of %d`, total-2, total, result.Correct, result.Total())
+	}
+
+	for _, letter := range result.Letters {
+		expected := letter.Total
+		if letter.Letter == 'I' || letter.Letter == 'S' {
+			expected--
+		}
+
+		if letter.Correct != expected {
+			t.Fatalf(`'%c': Expected %d correct, got %d`, letter.Letter, expected, letter.Correct)
+		}
+	}
+
+	var diff bytes.Buffer
+	if result.WriteDiff(&diff, 60) != 2 || !strings.Contains(diff.String(), "     1: THEQUICK") || !strings.Contains(diff.String(), "        THEQUACK") {
+		t.Fatalf("Wrong diff:\n%s", diff.String())
+	}
+}
+
+// TestGradeOmittedLetter checks that a missing letter only counts as one error and does not shift the rest of the answer.
+func TestGradeOmittedLetter(t *testing.T) {
+	s, ciphertext := encryptTestText(t)
+	total := len(normalize([]byte(testClearText)))
+
+	answer := strings.Replace(testClearText, `quick`, `quck`, 1)
+	result := grade(t, s, ciphertext, answer, Plaintext)
+	if result.Correct != total-1 || result.Total() != total {
+		t.Fatalf(`Expected %d of %d correct, got %d of %d`, total-1, total, result.Correct, result.Total())
+	}
+
+	for _, letter := range result.Letters {
+		expected := letter.Total
+		if letter.Letter == 'I' {
+			expected--
+		}
+
+		if letter.Correct != expected {
+			t.Fatalf(`'%c': Expected %d correct, got %d`, letter.Letter, expected, letter.Correct)
+		}
+	}
+
+	var diff bytes.Buffer
+	if result.WriteDiff(&diff, 60) != 1 ||
+		!strings.Contains(diff.String(), "     1: THEQUICKBROWN") ||
+		!strings.Contains(diff.String(), "        THEQU-CKBROWN") ||
+		!strings.Contains(diff.String(), "\n             ^\n") {
+		t.Fatalf("Wrong diff:\n%s", diff.String())
+	}
+
+	// An additional letter is an error, too.
+	answer = strings.Replace(testClearText, `brown`, `broown`, 1)
+	result = grade(t, s, ciphertext, answer, Plaintext)
+	if result.Correct != total || result.Total() != total+1 {
+		t.Fatalf(`Expected %d of %d correct, got %d of %d`, total, total+1, result.Correct, result.Total())
+	}
+
+	diff.Reset()
+	if result.WriteDiff(&diff, 60) != 1 || !strings.Contains(diff.String(), "     1: THEQUICKBR-OWN") {
+		t.Fatalf("Wrong diff:\n%s", diff.String())
+	}
+}
+
+func TestGradeKey(t *testing.T) {
+	s, ciphertext := encryptTestText(t)
+
+	var key strings.Builder
+	for letter := byte('A'); letter <= 'Z'; letter++ {
+		key.WriteString(string(letter) + ": " + string(s.Homophones(letter)) + "\n")
+	}
+
+	result := grade(t, s, ciphertext, key.String(), Auto)
+	if result.Kind != KeyAnswer || result.Accuracy() != 1 || result.KeyAccuracy() != 1 || result.WrongSymbols != 0 {
+		t.Fatalf(`Correct key has kind %s, accuracy %f and key accuracy %f`, result.Kind, result.Accuracy(), result.KeyAccuracy())
+	}
+
+	// Only the first homophone of 'E' is known and it is assigned to 'T'.
+	first := s.Homophones('E')[0]
+	partialKey := "T: " + string(first) + "\n"
+	result = grade(t, s, ciphertext, partialKey, KeyAnswer)
+	if result.CorrectSymbols != 0 || result.WrongSymbols != 1 || result.KeySymbols != 52 {
+		t.Fatalf(`Wrong key result: %d correct and %d wrong of %d`, result.CorrectSymbols, result.WrongSymbols, result.KeySymbols)
+	}
+
+	if result.Correct != 0 || !bytes.Contains(result.Answer, []byte{UnknownLetter}) {
+		t.Fatalf(`Wrong answer '%s' with %d correct characters`, result.Answer, result.Correct)
+	}
+}
+
+// ******** Private functions ********
+
+// encryptTestText creates a substitutor for the test text and returns it with the ciphertext.
+func encryptTestText(t *testing.T) (*homosubst.Substitutor, []byte) {
+	t.Helper()
+
+	s, err := homosubst.NewSubstitutorFromReader(strings.NewReader(testClearText), homosubst.Options{})
+	if err != nil {
+		t.Fatalf(`Error creating substitutor: %v`, err)
+	}
+
+	var ciphertext bytes.Buffer
+	err = s.EncryptStream(strings.NewReader(testClearText), &ciphertext, true)
+	if err != nil {
+		t.Fatalf(`Error encrypting: %v`, err)
+	}
+
+	return s, ciphertext.Bytes()
+}
+
+// grade grades an answer and fails on errors.
+func grade(t *testing.T, s *homosubst.Substitutor, ciphertext []byte, answer string, kind Kind) *Result {
+	t.Helper()
+
+	result, err := Grade(s, ciphertext, []byte(answer), kind)
+	if err != nil {
+		t.Fatalf(`Error grading: %v`, err)
+	}
+
+	return result
+}
@@ -0,0 +1,270 @@
+//
+// SPDX-FileCopyrightText: Copyright 2026 Frank Schwab
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileType: SOURCE
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+//
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Author: Frank Schwab
+//
+// Version: 1.0.0
+//
+// Change history:
+//    2026-10-18: V1.0.0: Created.
+//
+
+package grade
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"homophone/nametable"
+	"regexp"
+	"strings"
+)
+
+// ******** Public types ********
+
+// Key is a key made by a student. It maps substitution characters to letters.
+type Key struct {
+	// letters maps each substitution character to its letter, or 0 if it is not known.
+	letters [256]byte
+}
+
+// Kind is the kind of an answer.
+type Kind byte
+
+// ******** Public constants ********
+
+// Known kinds of answers.
+const (
+	// Auto detects the kind of the answer with [DetectKind].
+	Auto Kind = iota
+
+	// Plaintext is a recovered plaintext.
+	Plaintext
+
+	// KeyAnswer is a recovered key in the text or JSON key format.
+	KeyAnswer
+)
+
+// ErrUnknownKind is returned when a kind of answer is not known.
+var ErrUnknownKind = errors.New(`unknown kind of answer`)
+
+// ErrInvalidKey is returned when a key can not be parsed.
+var ErrInvalidKey = errors.New(`invalid key`)
+
+// ******** Private variables ********
+
+// kindNames contains the names of the kinds in the order of their values.
+var kindNames = nametable.Table[Kind]{
+	{Value: Auto, Name: `auto`},
+	{Value: Plaintext, Name: `plaintext`},
+	{Value: KeyAnswer, Name: `key`},
+}
+
+// keyLinePattern matches a line of the text key format, e.g. "E: abc", "E = a b c" or "E (12.70%): abc".
+var keyLinePattern = regexp.MustCompile(`^([A-Za-z])\s*(?:\([^)]*\))?\s*[:=](.*)$`)
+
+// ******** Public functions ********
+
+// ParseKind returns the kind of answer with the given name.
+func ParseKind(name string) (Kind, error) {
+	return kindNames.Parse(name, ErrUnknownKind)
+}
+
+// KindNames returns the names of all known kinds of answers.
+func KindNames() []string {
+	return kindNames.Names()
+}
+
+// DetectKind returns [KeyAnswer], if data is a JSON object or all lines that are neither empty
+// nor comments are lines of the text key format. Otherwise, it returns [Plaintext].
+func DetectKind(data []byte) Kind {
+	trimmed := bytes.TrimSpace(data)
+	if bytes.HasPrefix(trimmed, []byte(`{`)) {
+		return KeyAnswer
+	}
+
+	lines := keyLines(trimmed)
+	for _, line := range lines {
+		if !keyLinePattern.MatchString(line) {
+			return Plaintext
+		}
+	}
+
+	if len(lines) == 0 {
+		return Plaintext
+	}
+
+	return KeyAnswer
+}
+
+// ParseKey parses a key in the text or the JSON key format.
+//
+// In the text format each line contains a letter, a colon or an equals sign and the substitution characters
+// of the letter, optionally separated by spaces or commas, e.g. "E: a b c".
+// A percentage in parentheses after the letter is ignored, so the output of the key table can be used.
+// Empty lines and lines starting with '#' are ignored.
+//
+// In the JSON format the key is an object that maps letters to strings or arrays of substitution characters,
+// e.g. {"E": "abc", "T": ["x", "y"]}.
+//
+// A substitution character must not belong to more than one letter.
+func ParseKey(data []byte) (*Key, error) {
+	trimmed := bytes.TrimSpace(data)
+	if bytes.HasPrefix(trimmed, []byte(`{`)) {
+		return parseJSONKey(trimmed)
+	}
+
+	return parseTextKey(trimmed)
+}
+
+// ******** Public type functions ********
+
+// String returns the name of the kind of answer.
+func (k Kind) String() string {
+	return kindNames.Name(k)
+}
+
+// Letter returns the letter of a substitution character, or 0, if it is not known.
+func (k *Key) Letter(symbol byte) byte {
+	return k.letters[symbol]
+}
+
+// Decrypt decrypts a ciphertext. Substitution characters that are not known are replaced by unknown.
+// Other characters are copied.
+func (k *Key) Decrypt(ciphertext []byte, unknown byte) []byte {
+	result := make([]byte, len(ciphertext))
+	for i, b := range ciphertext {
+		switch {
+		case k.letters[b] != 0:
+			result[i] = k.letters[b]
+
+		case isSymbol(b):
+			result[i] = unknown
+
+		default:
+			result[i] = b
+		}
+	}
+
+	return result
+}
+
+// ******** Private type functions ********
+
+// add adds substitution characters of a letter.
+func (k *Key) add(letter byte, symbols string) error {
+	if letter >= 'a' && letter <= 'z' {
+		letter ^= 'a' ^ 'A'
+	}
+
+	if letter < 'A' || letter > 'Z' {
+		return fmt.Errorf(`%w: '%c' is not a letter`, ErrInvalidKey, letter)
+	}
+
+	for _, symbol := range []byte(symbols) {
+		switch {
+		case symbol == ' ' || symbol == ',' || symbol == '\t':
+			continue
+
+		case !isSymbol(symbol):
+			return fmt.Errorf(`%w: '%c' of '%c' is not a substitution character`, ErrInvalidKey, symbol, letter)
+
+		case k.letters[symbol] != 0 && k.letters[symbol] != letter:
+			return fmt.Errorf(`%w: '%c' belongs to '%c' and '%c'`, ErrInvalidKey, symbol, k.letters[symbol], letter)
+		}
+
+		k.letters[symbol] = letter
+	}
+
+	return nil
+}
+
+// ******** Private functions ********
+
+// parseTextKey parses a key in the text format.
+func parseTextKey(data []byte) (*Key, error) {
+	result := &Key{}
+	for _, line := range keyLines(data) {
+		match := keyLinePattern.FindStringSubmatch(line)
+		if match == nil {
+			return nil, fmt.Errorf(`%w: line '%s' is not in the format 'letter: characters'`, ErrInvalidKey, line)
+		}
+
+		err := result.add(match[1][0], match[2])
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return result, nil
+}
+
+// parseJSONKey parses a key in the JSON format.
+func parseJSONKey(data []byte) (*Key, error) {
+	var entries map[string]json.RawMessage
+	err := json.Unmarshal(data, &entries)
+	if err != nil {
+		return nil, fmt.Errorf(`%w: %w`, ErrInvalidKey, err)
+	}
+
+	result := &Key{}
+	for letter, value := range entries {
+		if len(letter) != 1 {
+			return nil, fmt.Errorf(`%w: '%s' is not a letter`, ErrInvalidKey, letter)
+		}
+
+		var symbols string
+		if json.Unmarshal(value, &symbols) != nil {
+			var list []string
+			err = json.Unmarshal(value, &list)
+			if err != nil {
+				return nil, fmt.Errorf(`%w: the characters of '%s' are neither a string nor an array of strings`, ErrInvalidKey, letter)
+			}
+
+			symbols = strings.Join(list, ``)
+		}
+
+		err = result.add(letter[0], symbols)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return result, nil
+}
+
+// keyLines returns the trimmed lines of data that are neither empty nor comments.
+func keyLines(data []byte) []string {
+	var result []string
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if len(line) != 0 && !strings.HasPrefix(line, `#`) {
+			result = append(result, line)
+		}
+	}
+
+	return result
+}
+
+// isSymbol returns true, if b is a substitution character, i.e. a letter in the range A-Z or a-z.
+func isSymbol(b byte) bool {
+	return (b >= 'A' && b <= 'Z') || (b >= 'a' && b <= 'z')
+}
@@ -20,67 +20,592 @@
 //
 // Author: Frank Schwab
 //
-// Version: 1.0.1
+// Version: 1.15.0
 //
 // Change history:
 //    2025-01-02: V1.0.0: Created.
 //    2025-01-05: V1.0.1: Added forgotten colon in message.
+//    2026-10-18: V1.1.0: Pass overwrite flag.
+//    2026-10-18: V1.2.0: Wipe substitutions after use. Warn if the substitution file is readable by others.
+//    2026-10-18: V1.3.0: Set hash algorithm of key file.
+//    2026-10-18: V1.4.0: Write to the output stream of the program.
+//    2026-10-18: V1.5.0: Pass apportionment method.
+//    2026-10-18: V1.6.0: Pass substitutor options.
+//    2026-10-18: V1.7.0: Print expected flatness.
+//    2026-10-18: V1.8.0: Add analysis.
+//    2026-10-18: V1.9.0: Add server.
+//    2026-10-18: V1.10.0: Add exercise.
+//    2026-10-18: V1.11.0: Add grading.
+//    2026-10-18: V1.12.0: Add crib attacks.
+//    2026-10-18: V1.13.0: Add homophone groups to the analysis.
+//    2026-10-18: V1.14.0: Add building of language models.
+//    2026-10-18: V1.15.0: Compare flatness of cipher modes.
 //
 
 package main
 
 import (
+	"bytes"
+	"context"
+	"errors"
 	"fmt"
+	"homophone/analysis"
+	"homophone/crib"
+	"homophone/exercise"
+	"homophone/filehelper"
+	"homophone/grade"
 	"homophone/homosubst"
+	"homophone/integritycheckedfile"
+	"homophone/langmodel"
+	"homophone/server"
+	"io"
+	"net"
+	"os"
+	"os/signal"
+	"slices"
+	"strings"
+	"syscall"
 )
 
+// ******** Private constants ********
+
+// sheetFilePermissions are the permissions of an exercise sheet without the answer key.
+const sheetFilePermissions = 0644
+
+// diffWidth is the number of characters in a line of the differences of a grading.
+const diffWidth = 60
+
+// answerFilePermissions are the permissions of a file with the answer key. It is only readable by its owner.
+const answerFilePermissions = 0600
+
+// modelFilePermissions are the permissions of a language model file.
+const modelFilePermissions = 0644
+
+// ******** Private functions ********
+
 // doEncryption encryptions the contents of a file.
-func doEncryption(clearFileName string, encryptedFileName string, substitutionFileName string, keepOthers bool) int {
-	fmt.Printf("Source file: '%s'\n", clearFileName)
+// If protect is true, the encrypted file is written in the protected format.
+func doEncryption(
+	clearFileName string,
+	encryptedFileName string,
+	substitutionFileName string,
+	keepOthers bool,
+	overwrite bool,
+	protect bool,
+	hashAlgorithm integritycheckedfile.HashAlgorithm,
+	options homosubst.Options,
+) int {
+	_, _ = fmt.Fprintf(outWriter, "Source file: '%s'\n", clearFileName)
 
-	substitutor, err := homosubst.NewSubstitutor(clearFileName)
+	substitutor, err := homosubst.NewSubstitutorWithOptions(clearFileName, options)
 	if err != nil {
 		return printErrorf(`Error creating substitutor: %v`, err)
 	}
+	defer closeSubstitutor(substitutor)
 
-	fmt.Println(`Substitutions:`)
-	substitutor.Print()
+	err = substitutor.SetHashAlgorithm(hashAlgorithm)
+	if err != nil {
+		return printErrorf(`Error setting hash algorithm: %v`, err)
+	}
+
+	_, _ = fmt.Fprintln(outWriter, `Substitutions:`)
+	substitutor.Fprint(outWriter)
+	printFlatness(substitutor)
 
-	err = substitutor.Encrypt(clearFileName, encryptedFileName, keepOthers)
+	if protect {
+		err = substitutor.EncryptProtected(clearFileName, encryptedFileName, keepOthers, overwrite)
+	} else {
+		err = substitutor.Encrypt(clearFileName, encryptedFileName, keepOthers, overwrite)
+	}
 	if err != nil {
 		return printErrorf(`Error encrypting file: %v`, err)
 	}
-	fmt.Printf("Encrypted file: '%s'\n", outFileName)
+	_, _ = fmt.Fprintf(outWriter, "Encrypted file: '%s'\n", encryptedFileName)
 
-	err = substitutor.Save(substitutionFileName)
+	err = substitutor.Save(substitutionFileName, overwrite)
 	if err != nil {
 		return printErrorf(`Error saving substitution file: %v`, err)
 	}
 
-	fmt.Printf("Substitution file: '%s'\n", substFileName)
+	_, _ = fmt.Fprintf(outWriter, "Substitution file: '%s'\n", substitutionFileName)
 
 	return rcOK
 }
 
 // doDecryption decrypts the contents of an encrypted file.
-func doDecryption(encryptedFileName string, decryptedFileName string, substitutionFileName string) int {
-	fmt.Printf("Encrypted file: '%s'\n", encryptedFileName)
+// If protect is true, the encrypted file is verified and decrypted in the protected format.
+func doDecryption(encryptedFileName string, decryptedFileName string, substitutionFileName string, overwrite bool, protect bool) int {
+	_, _ = fmt.Fprintf(outWriter, "Encrypted file: '%s'\n", encryptedFileName)
+
+	warnIfReadableByOthers(substitutionFileName)
 
 	substitutor, err := homosubst.NewFromFile(substitutionFileName)
 	if err != nil {
 		return printErrorf(`Error loading substitution file: %v`, err)
 	}
-	fmt.Printf("Loaded substitution file: '%s'\n", substitutionFileName)
+	defer closeSubstitutor(substitutor)
+	_, _ = fmt.Fprintf(outWriter, "Loaded substitution file: '%s'\n", substitutionFileName)
 
-	fmt.Println(`Substitutions:`)
-	substitutor.Print()
+	_, _ = fmt.Fprintln(outWriter, `Substitutions:`)
+	substitutor.Fprint(outWriter)
 
-	err = substitutor.Decrypt(encryptedFileName, decryptedFileName)
+	if protect {
+		err = substitutor.DecryptProtected(encryptedFileName, decryptedFileName, overwrite)
+	} else {
+		err = substitutor.Decrypt(encryptedFileName, decryptedFileName, overwrite)
+	}
 	if err != nil {
 		return printErrorf(`Error decrypting file: %v`, err)
 	}
 
-	fmt.Printf("Decrypted file: '%s'\n", decryptedFileName)
+	_, _ = fmt.Fprintf(outWriter, "Decrypted file: '%s'\n", decryptedFileName)
+
+	return rcOK
+}
+
+// doExercise creates an exercise sheet from a clear text file.
+// If answerFileName is empty, the answer key is part of the sheet.
+// If substitutionFileName is not empty, the key is saved to this file.
+func doExercise(
+	clearFileName string,
+	sheetFileName string,
+	answerFileName string,
+	substitutionFileName string,
+	format exercise.Format,
+	options exercise.Options,
+	overwrite bool,
+) int {
+	_, _ = fmt.Fprintf(outWriter, "Source file: '%s'\n", clearFileName)
+
+	substitutor, err := homosubst.NewSubstitutorWithOptions(clearFileName, homosubst.Options{})
+	if err != nil {
+		return printErrorf(`Error creating substitutor: %v`, err)
+	}
+	defer closeSubstitutor(substitutor)
+
+	var clearFile *os.File
+	clearFile, err = os.Open(clearFileName)
+	if err != nil {
+		return printErrorf(`Error opening clear text file: %v`, err)
+	}
+	defer filehelper.CloseWithName(clearFile)
+
+	var sheet *exercise.Sheet
+	sheet, err = exercise.New(substitutor, clearFile, options)
+	if err != nil {
+		return printErrorf(`Error creating exercise: %v`, err)
+	}
+
+	if len(answerFileName) == 0 {
+		err = writeOutputFile(sheetFileName, answerFilePermissions, overwrite, func(w io.Writer) error {
+			return sheet.Write(w, format)
+		})
+	} else {
+		err = writeOutputFile(sheetFileName, sheetFilePermissions, overwrite, func(w io.Writer) error {
+			return sheet.WriteExercise(w, format)
+		})
+	}
+	if err != nil {
+		return printErrorf(`Error writing exercise sheet: %v`, err)
+	}
+
+	_, _ = fmt.Fprintf(outWriter, "Exercise sheet: '%s' (%s, %d of %d letters revealed)\n", sheetFileName, format, sheet.RevealedCount(), sheet.LetterCount())
+
+	if len(answerFileName) != 0 {
+		err = writeOutputFile(answerFileName, answerFilePermissions, overwrite, func(w io.Writer) error {
+			return sheet.WriteAnswerKey(w, format)
+		})
+		if err != nil {
+			return printErrorf(`Error writing answer key: %v`, err)
+		}
+
+		_, _ = fmt.Fprintf(outWriter, "Answer key: '%s'\n", answerFileName)
+	}
+
+	if len(substitutionFileName) != 0 {
+		err = substitutor.Save(substitutionFileName, overwrite)
+		if err != nil {
+			return printErrorf(`Error saving substitution file: %v`, err)
+		}
+
+		_, _ = fmt.Fprintf(outWriter, "Substitution file: '%s'\n", substitutionFileName)
+	}
+
+	return rcOK
+}
+
+// doGrade grades the answer of a student for an encrypted file.
+func doGrade(substitutionFileName string, encryptedFileName string, answerFileName string, kind grade.Kind) int {
+	substitutor, err := homosubst.NewFromFile(substitutionFileName)
+	if err != nil {
+		return printErrorf(`Error loading substitution file: %v`, err)
+	}
+	defer closeSubstitutor(substitutor)
+
+	var ciphertext []byte
+	ciphertext, err = os.ReadFile(encryptedFileName)
+	if err != nil {
+		return printErrorf(`Error reading encrypted file: %v`, err)
+	}
+
+	var answer []byte
+	answer, err = os.ReadFile(answerFileName)
+	if err != nil {
+		return printErrorf(`Error reading answer file: %v`, err)
+	}
+
+	var result *grade.Result
+	result, err = grade.Grade(substitutor, ciphertext, answer, kind)
+	if err != nil {
+		return printErrorf(`Error grading answer: %v`, err)
+	}
+
+	_, _ = fmt.Fprintf(outWriter, "Encrypted file: '%s'\n", encryptedFileName)
+	_, _ = fmt.Fprintf(outWriter, "Answer file: '%s' (%s)\n", answerFileName, result.Kind)
+	printGrade(result)
+
+	return rcOK
+}
+
+// doCrib finds the positions of a crib in an encrypted file and exports the partial key of one of them.
+// position is the 1-based position of the alignment to show and to export, or 0 for all alignments.
+func doCrib(
+	encryptedFileName string,
+	cribText string,
+	maxCount int,
+	position int,
+	substitutionFileName string,
+	overwrite bool,
+) int {
+	ciphertext, err := os.ReadFile(encryptedFileName)
+	if err != nil {
+		return printErrorf(`Error reading encrypted file: %v`, err)
+	}
+
+	var alignments []crib.Alignment
+	var positionCount int
+	alignments, positionCount, err = crib.Find(ciphertext, cribText)
+	if err != nil {
+		return printErrorf(`Error finding crib: %v`, err)
+	}
+
+	_, _ = fmt.Fprintf(outWriter, "Encrypted file: '%s'\n", encryptedFileName)
+	_, _ = fmt.Fprintf(outWriter, "Crib: %s\n", crib.NormalizeCrib(cribText))
+	_, _ = fmt.Fprintf(outWriter, "Possible positions: %d of %d\n", len(alignments), positionCount)
+
+	if position != 0 {
+		index := slices.IndexFunc(alignments, func(a crib.Alignment) bool { return a.Position == position-1 })
+		if index < 0 {
+			return printErrorf(`Crib is not possible at position %d`, position)
+		}
+
+		alignments = alignments[index : index+1]
+	}
+
+	for i, alignment := range alignments {
+		if i == maxCount {
+			_, _ = fmt.Fprintf(outWriter, "... and %d more\n", len(alignments)-maxCount)
+			break
+		}
+
+		printAlignment(alignment)
+	}
+
+	if len(substitutionFileName) == 0 {
+		return rcOK
+	}
+
+	if len(alignments) == 0 {
+		return printErrorf(`There is no possible position to export a partial key from`)
+	}
+
+	if len(alignments) > 1 {
+		return printErrorf(`There are %d possible positions. Specify 'pos' to export the partial key of one of them`, len(alignments))
+	}
+
+	var substitutor *homosubst.Substitutor
+	substitutor, err = homosubst.NewPartial(alignments[0].Homophones())
+	if err != nil {
+		return printErrorf(`Error creating partial key: %v`, err)
+	}
+	defer closeSubstitutor(substitutor)
+
+	err = substitutor.Save(substitutionFileName, overwrite)
+	if err != nil {
+		return printErrorf(`Error saving substitution file: %v`, err)
+	}
+
+	_, _ = fmt.Fprintf(outWriter, "Partial key file: '%s'\n", substitutionFileName)
+
+	return rcOK
+}
+
+// doBuildLanguageModel builds a language model from a corpus and writes it to a model file.
+func doBuildLanguageModel(corpusPath string, modelFileName string, minCount uint64, overwrite bool) int {
+	builder := langmodel.NewBuilder()
+	fileCount, err := builder.AddPath(corpusPath)
+	if err != nil {
+		return printErrorf(`Error reading corpus: %v`, err)
+	}
+
+	_, _ = fmt.Fprintf(outWriter, "Corpus: '%s' (%d files, %d letters)\n", corpusPath, fileCount, builder.Letters())
+
+	var model *langmodel.Model
+	model, err = builder.Model(minCount)
+	if err != nil {
+		return printErrorf(`Error building language model: %v`, err)
+	}
+
+	err = writeOutputFile(modelFileName, modelFilePermissions, overwrite, func(w io.Writer) error {
+		_, err := model.WriteTo(w)
+		return err
+	})
+	if err != nil {
+		return printErrorf(`Error writing language model file: %v`, err)
+	}
+
+	_, _ = fmt.Fprintf(outWriter, "Trigrams: %d, different: %d of %d\n", model.TrigramTotal(), model.DistinctTrigrams(), langmodel.TrigramCount)
+	_, _ = fmt.Fprintf(outWriter, "Quadgrams: %d, different: %d of %d\n", model.QuadgramTotal(), model.DistinctQuadgrams(), langmodel.QuadgramCount)
+	_, _ = fmt.Fprintf(outWriter, "Language model file: '%s'\n", modelFileName)
+
+	return rcOK
+}
+
+// doAnalysis prints the statistics of the characters and bigrams of an encrypted file and groups of probable homophones.
+// If substitutionFileName is not empty, the groups are compared with the homophones of the key.
+// If clearFileName is not empty, the expected flatness of all modes for this clear text is compared.
+func doAnalysis(encryptedFileName string, topCount int, groupCount int, substitutionFileName string, clearFileName string) int {
+	var substitutor *homosubst.Substitutor
+	var err error
+	if len(substitutionFileName) != 0 {
+		substitutor, err = homosubst.NewFromFile(substitutionFileName)
+		if err != nil {
+			return printErrorf(`Error loading substitution file: %v`, err)
+		}
+		defer closeSubstitutor(substitutor)
+	}
+
+	_, _ = fmt.Fprintf(outWriter, "Encrypted file: '%s'\n", encryptedFileName)
+
+	var file *os.File
+	file, err = os.Open(encryptedFileName)
+	if err != nil {
+		return printErrorf(`Error opening encrypted file: %v`, err)
+	}
+	defer filehelper.CloseWithName(file)
+
+	var statistics *analysis.Statistics
+	statistics, err = analysis.FromReader(file)
+	if err != nil {
+		return printErrorf(`Error reading encrypted file: %v`, err)
+	}
+
+	printStatistics(statistics, topCount)
+
+	if groupCount != 0 {
+		printGroups(statistics.Groups(groupCount), substitutor)
+	}
+
+	if len(clearFileName) != 0 {
+		return printModeFlatness(clearFileName)
+	}
 
 	return rcOK
 }
+
+// doServe serves the HTTP endpoints until the program is interrupted or terminated.
+func doServe(address string, maxRequestSize int64) int {
+	listener, err := net.Listen(`tcp`, address)
+	if err != nil {
+		return printErrorf(`Error listening on '%s': %v`, address, err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	_, _ = fmt.Fprintf(outWriter, "Listening on '%s'\n", listener.Addr())
+
+	err = server.Serve(ctx, listener, server.Config{MaxRequestSize: maxRequestSize})
+	if err != nil {
+		return printErrorf(`Error serving requests: %v`, err)
+	}
+
+	_, _ = fmt.Fprintln(outWriter, `Server stopped`)
+
+	return rcOK
+}
+
+// writeOutputFile writes an output file atomically with the given write function.
+func writeOutputFile(fileName string, perm os.FileMode, overwrite bool, write func(w io.Writer) error) error {
+	file, err := filehelper.CreateAtomic(fileName, perm, overwrite)
+	if err != nil {
+		return err
+	}
+	defer filehelper.CloseWithName(file)
+
+	err = write(file)
+	if err != nil {
+		return err
+	}
+
+	return file.Commit()
+}
+
+// closeSubstitutor closes a substitutor and thereby wipes its data.
+func closeSubstitutor(substitutor *homosubst.Substitutor) {
+	_ = substitutor.Close()
+}
+
+// warnIfReadableByOthers prints a warning if the substitution file is readable by group or others.
+// Other errors are reported when the file is loaded.
+func warnIfReadableByOthers(substitutionFileName string) {
+	err := homosubst.CheckFilePermissions(substitutionFileName)
+	if errors.Is(err, homosubst.ErrReadableByOthers) {
+		printWarningf(`Warning: %v`, err)
+	}
+}
+
+// printFlatness prints the achieved and the ideal flatness of the substitution character frequencies.
+func printFlatness(substitutor *homosubst.Substitutor) {
+	flatness, err := substitutor.Flatness()
+	if err != nil {
+		return
+	}
+
+	_, _ = fmt.Fprintf(outWriter, "Expected frequencies of the substitution characters: ideal %.3f%%, min %.3f%%, max %.3f%%, deviation %.3f%%, %d of %d ideal\n",
+		flatness.Ideal*100,
+		flatness.Min*100,
+		flatness.Max*100,
+		flatness.Deviation*100,
+		flatness.IdealCount,
+		flatness.Count)
+}
+
+// printGrade prints the score, the results per letter and the differences of a grading.
+func printGrade(result *grade.Result) {
+	_, _ = fmt.Fprintf(outWriter, "Score: %.2f%% (%d of %d characters correct)\n", result.Accuracy()*100, result.Correct, result.Total())
+
+	if result.Kind == grade.KeyAnswer {
+		_, _ = fmt.Fprintf(outWriter, "Key: %.2f%% (%d of %d substitution characters correct, %d wrong)\n",
+			result.KeyAccuracy()*100,
+			result.CorrectSymbols,
+			result.KeySymbols,
+			result.WrongSymbols)
+		_, _ = fmt.Fprintln(outWriter, `Correct homophones per letter:`)
+	} else {
+		_, _ = fmt.Fprintln(outWriter, `Correct occurrences per letter:`)
+	}
+
+	for _, letter := range result.Letters {
+		_, _ = fmt.Fprintf(outWriter, "   %c: %3d of %3d (%6.2f%%)\n", letter.Letter, letter.Correct, letter.Total, float64(letter.Correct)/float64(letter.Total)*100)
+	}
+
+	var diff bytes.Buffer
+	if result.WriteDiff(&diff, diffWidth) == 0 {
+		_, _ = fmt.Fprintln(outWriter, `No differences`)
+		return
+	}
+
+	_, _ = fmt.Fprintln(outWriter, `Differences (expected, answer):`)
+	_, _ = outWriter.Write(diff.Bytes())
+}
+
+// printAlignment prints the position, the substitution characters and the partial key of an alignment of a crib.
+func printAlignment(alignment crib.Alignment) {
+	var key strings.Builder
+	for i, list := range alignment.Homophones() {
+		if len(list) != 0 {
+			_, _ = fmt.Fprintf(&key, " %c:%s", i+'A', list)
+		}
+	}
+
+	_, _ = fmt.Fprintf(outWriter, "   Position %d: %s%s (%d repeated)\n", alignment.Position+1, alignment.Ciphertext, key.String(), alignment.Repeats)
+}
+
+// printModeFlatness prints the expected flatness of the substitution character frequencies of all modes for a clear text.
+func printModeFlatness(clearFileName string) int {
+	_, _ = fmt.Fprintf(outWriter, "Expected frequencies of the modes for clear text file '%s':\n", clearFileName)
+	for _, name := range homosubst.ModeNames() {
+		mode, _ := homosubst.ParseMode(name)
+
+		substitutor, err := homosubst.NewSubstitutorWithOptions(clearFileName, homosubst.Options{Mode: mode})
+		if err != nil {
+			return printErrorf(`Error creating substitutor: %v`, err)
+		}
+
+		var flatness analysis.Flatness
+		flatness, err = substitutor.Flatness()
+		closeSubstitutor(substitutor)
+		if err != nil {
+			return printErrorf(`Error calculating flatness: %v`, err)
+		}
+
+		_, _ = fmt.Fprintf(outWriter, "   %-12s ideal %.3f%%, min %.3f%%, max %.3f%%, deviation %.3f%%, %d of %d ideal\n",
+			mode.String()+`:`,
+			flatness.Ideal*100,
+			flatness.Min*100,
+			flatness.Max*100,
+			flatness.Deviation*100,
+			flatness.IdealCount,
+			flatness.Count)
+	}
+
+	return rcOK
+}
+
+// printGroups prints groups of probable homophones and compares them with the homophones of the substitutor, if it is not nil.
+func printGroups(groups []analysis.Group, substitutor *homosubst.Substitutor) {
+	var letters [256]byte
+	if substitutor != nil {
+		for letter := byte('A'); letter <= 'Z'; letter++ {
+			for _, b := range substitutor.Homophones(letter) {
+				letters[b] = letter
+			}
+		}
+	}
+
+	_, _ = fmt.Fprintln(outWriter, `Groups of probable homophones:`)
+	for i, group := range groups {
+		_, _ = fmt.Fprintf(outWriter, "   %2d: %-12s confidence %5.1f%%, %d occurrences", i+1, group.Characters, group.Confidence*100, group.Count)
+		if substitutor != nil {
+			letter, count := group.MajorityLetter(&letters)
+			if letter != 0 {
+				_, _ = fmt.Fprintf(outWriter, ", key: %d of %d are %c", count, len(group.Characters), letter)
+			}
+		}
+		_, _ = fmt.Fprintln(outWriter)
+	}
+
+	if substitutor == nil {
+		return
+	}
+
+	score := analysis.ScoreGroups(groups, &letters)
+	_, _ = fmt.Fprintf(outWriter, "Pairs in a group that are homophones: %.2f%%, pairs of homophones in a group: %.2f%%\n", score.Precision*100, score.Recall*100)
+}
+
+// printStatistics prints the statistics of the characters and bigrams of a text.
+func printStatistics(statistics *analysis.Statistics, topCount int) {
+	alphabetSize := len(statistics.Alphabet())
+	flatness := statistics.Flatness()
+
+	_, _ = fmt.Fprintf(outWriter, "Characters: %d, different: %d of %d\n", statistics.Total(), statistics.DistinctCharacters(), alphabetSize)
+	_, _ = fmt.Fprintf(outWriter, "Frequencies: ideal %.3f%%, min %.3f%%, max %.3f%%, deviation %.3f%%\n",
+		flatness.Ideal*100,
+		flatness.Min*100,
+		flatness.Max*100,
+		flatness.Deviation*100)
+	_, _ = fmt.Fprintf(outWriter, "Index of coincidence: %.3f (random text: 1.000)\n", statistics.IndexOfCoincidence())
+	_, _ = fmt.Fprintf(outWriter, "Bigrams: %d, different: %d of %d\n", statistics.BigramTotal(), statistics.DistinctBigrams(), alphabetSize*alphabetSize)
+	_, _ = fmt.Fprintf(outWriter, "Bigram index of coincidence: %.3f (random text: 1.000)\n", statistics.BigramIndexOfCoincidence())
+
+	topBigrams := statistics.TopBigrams(topCount)
+	if len(topBigrams) == 0 {
+		return
+	}
+
+	_, _ = fmt.Fprintln(outWriter, `Most frequent bigrams:`)
+	for _, bc := range topBigrams {
+		_, _ = fmt.Fprintf(outWriter, "   %s: %d (%.3f%%)\n", bc.Bigram[:], bc.Count, float64(bc.Count)/float64(statistics.BigramTotal())*100)
+	}
+}
@@ -0,0 +1,78 @@
+//
+// SPDX-FileCopyrightText: Copyright 2026 Frank Schwab
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileType: SOURCE
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+//
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Author: Frank Schwab
+//
+// Version: 1.2.0
+//
+// Change history:
+//    2026-10-18: V1.0.0: Created.
+//    2026-10-18: V1.1.0: Wipe letter shares and the selection data of the substitution lists.
+//    2026-10-18: V1.2.0: Wipe context-dependent substitutions.
+//
+
+package homosubst
+
+import "homophone/slicehelper"
+
+// ******** Public type functions ********
+
+// Close wipes all substitution data from memory.
+// The Substitutor must not be used after it has been closed.
+func (s *Substitutor) Close() error {
+	for _, list := range s.substitutions {
+		list.Clear()
+	}
+
+	slicehelper.ClearNumber(s.letterCounts)
+	slicehelper.ClearNumber(s.proportions)
+	slicehelper.ClearNumber(s.letterShares)
+	slicehelper.ClearNumber(s.decryptionTable)
+
+	for _, substitutions := range s.contextSubstitutions {
+		for _, list := range substitutions {
+			list.Clear()
+		}
+	}
+
+	for _, table := range s.contextDecryptionTables {
+		slicehelper.ClearNumber(table)
+	}
+
+	for _, shares := range s.contextShares {
+		slicehelper.ClearNumber(shares)
+	}
+
+	slicehelper.ClearNumber(s.contextClasses)
+
+	s.substitutions = nil
+	s.letterCounts = nil
+	s.proportions = nil
+	s.letterShares = nil
+	s.decryptionTable = nil
+	s.contextClasses = nil
+	s.contextSubstitutions = nil
+	s.contextShares = nil
+	s.contextDecryptionTables = nil
+	s.previousLetter = 0
+	s.substitutionAlphabetSize = 0
+
+	return nil
+}
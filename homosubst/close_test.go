@@ -0,0 +1,127 @@
+//
+// SPDX-FileCopyrightText: Copyright 2026 Frank Schwab
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileType: SOURCE
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+//
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Author: Frank Schwab
+//
+// Version: 1.1.0
+//
+// Change history:
+//    2026-10-18: V1.0.0: Created.
+//    2026-10-18: V1.1.0: Check the weighted tables.
+//
+
+package homosubst
+
+import (
+	"homophone/oshelper"
+	"homophone/randomlist"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// ******** Test functions ********
+
+func TestCloseWipesData(t *testing.T) {
+	s := newTestSubstitutor(t)
+	lists := make([][]byte, len(s.substitutions))
+	for i, list := range s.substitutions {
+		lists[i] = list.BaseList()
+	}
+	decryptionTable := s.getDecryptionTable()
+
+	err := s.Close()
+	if err != nil {
+		t.Fatalf(`Error closing substitutor: %v`, err)
+	}
+
+	for i, list := range lists {
+		expectAllZero(t, list, string(rune(i+'A')))
+	}
+	expectAllZero(t, decryptionTable, `decryption table`)
+
+	if s.substitutions != nil || s.decryptionTable != nil {
+		t.Fatal(`Closed substitutor still references its data`)
+	}
+}
+
+func TestCloseWipesWeightedData(t *testing.T) {
+	s := newTestSubstitutorWithOptions(t, testClearText, Options{Weighted: true})
+	weightedLists := make([]*randomlist.WeightedList[byte], len(s.substitutions))
+	lists := make([][]byte, len(s.substitutions))
+	for i, list := range s.substitutions {
+		w, ok := list.(*randomlist.WeightedList[byte])
+		if !ok {
+			t.Fatalf(`Substitution list of '%c' is not a weighted list`, rune(i+'A'))
+		}
+		weightedLists[i] = w
+		lists[i] = w.BaseList()
+	}
+
+	err := s.Close()
+	if err != nil {
+		t.Fatalf(`Error closing substitutor: %v`, err)
+	}
+
+	for i, w := range weightedLists {
+		name := string(rune(i + 'A'))
+		expectAllZero(t, lists[i], name)
+		if w.Len() != 0 || len(w.Probabilities()) != 0 {
+			t.Fatalf(`Weighted list of '%s' still has its tables`, name)
+		}
+	}
+}
+
+func TestSaveFilePermissions(t *testing.T) {
+	if !oshelper.HasFilePermissions {
+		t.Skip(`File permissions are not supported on this platform`)
+	}
+
+	s := newTestSubstitutor(t)
+
+	substFileName := filepath.Join(t.TempDir(), `test.subst`)
+	err := s.Save(substFileName, false)
+	if err != nil {
+		t.Fatalf(`Error saving: %v`, err)
+	}
+
+	var fi os.FileInfo
+	fi, err = os.Stat(substFileName)
+	if err != nil {
+		t.Fatalf(`Error getting file information: %v`, err)
+	}
+
+	if fi.Mode().Perm() != substitutionFilePermissions {
+		t.Fatalf(`Expected permissions %04o, got %04o`, substitutionFilePermissions, fi.Mode().Perm())
+	}
+}
+
+// ******** Private functions ********
+
+// expectAllZero checks that all bytes of a slice are zero.
+func expectAllZero(t *testing.T, data []byte, name string) {
+	t.Helper()
+
+	for _, b := range data {
+		if b != 0 {
+			t.Fatalf(`Data of '%s' have not been wiped: %v`, name, data)
+		}
+	}
+}
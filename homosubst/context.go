@@ -0,0 +1,211 @@
+//
+// SPDX-FileCopyrightText: Copyright 2026 Frank Schwab
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileType: SOURCE
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+//
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Author: Frank Schwab
+//
+// Version: 1.1.0
+//
+// Change history:
+//    2026-10-18: V1.0.0: Created.
+//    2026-10-18: V1.1.0: Bigram frequencies are counted with the character frequencies.
+//
+
+package homosubst
+
+import (
+	"fmt"
+	"homophone/randomlist"
+	"slices"
+)
+
+// ******** Public constants ********
+
+// MaxContextClassCount is the maximum number of predecessor classes.
+const MaxContextClassCount = int(sourceAlphabetSize)
+
+// ******** Public type functions ********
+
+// HasContexts returns true, if the substitution of a character depends on the class of its predecessor.
+func (s *Substitutor) HasContexts() bool {
+	return s.contextClasses != nil
+}
+
+// ResetContext forgets the predecessor, so the next character is substituted as the first one of a text.
+func (s *Substitutor) ResetContext() {
+	s.previousLetter = 0
+}
+
+// ******** Private type functions ********
+
+// selectorFor returns the substitution list for the upper case letter b and remembers b as the predecessor.
+func (s *Substitutor) selectorFor(b byte) randomlist.Selector[byte] {
+	index := b - 'A'
+	if s.contextClasses == nil {
+		return s.substitutions[index]
+	}
+
+	previous := s.previousLetter
+	s.previousLetter = b
+	if previous == 0 {
+		return s.substitutions[index]
+	}
+
+	return s.contextSubstitutions[s.contextClasses[previous-'A']][index]
+}
+
+// decryptionTableFor returns the decryption table for a character whose decrypted predecessor is previous.
+// previous is 0, if there is no predecessor.
+func (s *Substitutor) decryptionTableFor(previous byte) []byte {
+	if previous == 0 || s.contextClasses == nil {
+		return s.getDecryptionTable()
+	}
+
+	if s.contextDecryptionTables == nil {
+		s.contextDecryptionTables = make([][]byte, len(s.contextSubstitutions))
+		for i, substitutions := range s.contextSubstitutions {
+			s.contextDecryptionTables[i] = buildDecryptionTable(substitutions)
+		}
+	}
+
+	return s.contextDecryptionTables[s.contextClasses[previous-'A']]
+}
+
+// buildContexts builds the context-dependent substitution lists from the bigram frequencies of the source.
+func (s *Substitutor) buildContexts(
+	sourceFrequencies []uint,
+	bigramFrequencies []uint,
+	substitutionAlphabet []byte,
+	options Options) error {
+	var err error
+
+	classCount := options.ContextClassCount
+
+	s.contextClasses = assignContextClasses(sourceFrequencies, classCount)
+	s.contextSubstitutions = make([][]randomlist.Selector[byte], classCount)
+	s.contextShares = make([][]float64, classCount)
+
+	bigramCount := uint(0)
+	for _, f := range bigramFrequencies {
+		bigramCount += f
+	}
+
+	substitutionAlphabetSize := s.substitutionAlphabetSize
+	for class := range classCount {
+		classFrequencies, classTotal := contextFrequencies(bigramFrequencies, s.contextClasses, byte(class))
+
+		s.contextShares[class] = make([]float64, sourceAlphabetSize)
+		for i, f := range classFrequencies {
+			s.contextShares[class][i] = float64(f) / float64(max(bigramCount, 1))
+		}
+
+		// A class whose characters are never followed by another one gets the lists of the whole source.
+		if classTotal == 0 {
+			classFrequencies = sourceFrequencies
+			for _, f := range sourceFrequencies {
+				classTotal += f
+			}
+		}
+
+		var substitutionLengths []uint16
+		substitutionLengths, err = getSubstitutionLengths(classFrequencies, substitutionAlphabetSize, options)
+		if err != nil {
+			return fmt.Errorf(`predecessor class %d: %w`, class+1, err)
+		}
+
+		lists := generateSubstitutions(substitutionLengths, substitutionAlphabet, substitutionAlphabetSize)
+		s.contextSubstitutions[class], err = makeSelectors(lists, classFrequencies, classTotal, substitutionAlphabetSize, options)
+		if err != nil {
+			return fmt.Errorf(`predecessor class %d: %w`, class+1, err)
+		}
+	}
+
+	return nil
+}
+
+// contextSymbolFrequencies adds the expected frequencies of the substitution characters
+// of the context-dependent substitution lists to frequencies.
+func (s *Substitutor) contextSymbolFrequencies(frequencies map[byte]float64) {
+	for class, substitutions := range s.contextSubstitutions {
+		for i, list := range substitutions {
+			share := s.contextShares[class][i]
+			for j, p := range list.Probabilities() {
+				frequencies[list.BaseList()[j]] += share * p
+			}
+		}
+	}
+}
+
+// ******** Private functions ********
+
+// assignContextClasses assigns each character to one of classCount predecessor classes.
+// The characters are assigned in the order of decreasing frequency to the class with the
+// lowest total frequency, so all classes get similar numbers of successors.
+func assignContextClasses(sourceFrequencies []uint, classCount int) []byte {
+	order := make([]int, len(sourceFrequencies))
+	for i := range order {
+		order[i] = i
+	}
+	slices.SortStableFunc(order, func(a, b int) int {
+		switch {
+		case sourceFrequencies[a] > sourceFrequencies[b]:
+			return -1
+		case sourceFrequencies[a] < sourceFrequencies[b]:
+			return 1
+		default:
+			return 0
+		}
+	})
+
+	result := make([]byte, len(sourceFrequencies))
+	load := make([]uint, classCount)
+	for _, i := range order {
+		class := 0
+		for c := 1; c < classCount; c++ {
+			if load[c] < load[class] {
+				class = c
+			}
+		}
+
+		result[i] = byte(class)
+		load[class] += sourceFrequencies[i]
+	}
+
+	return result
+}
+
+// contextFrequencies returns the frequencies of the characters that follow a character of the given class
+// and the total of these frequencies.
+func contextFrequencies(bigramFrequencies []uint, classes []byte, class byte) ([]uint, uint) {
+	result := make([]uint, sourceAlphabetSize)
+	total := uint(0)
+	for previous, previousClass := range classes {
+		if previousClass != class {
+			continue
+		}
+
+		for i := range result {
+			f := bigramFrequencies[previous*int(sourceAlphabetSize)+i]
+			result[i] += f
+			total += f
+		}
+	}
+
+	return result, total
+}
@@ -0,0 +1,242 @@
+//
+// SPDX-FileCopyrightText: Copyright 2026 Frank Schwab
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileType: SOURCE
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+//
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Author: Frank Schwab
+//
+// Version: 1.0.0
+//
+// Change history:
+//    2026-10-18: V1.0.0: Created.
+//
+
+package homosubst
+
+import (
+	"bytes"
+	"errors"
+	"homophone/analysis"
+	"homophone/randomlist"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// ******** Test functions ********
+
+func TestAssignContextClasses(t *testing.T) {
+	frequencies := []uint{10, 1, 9, 2, 8, 3, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 7}
+	classes := assignContextClasses(frequencies, 3)
+
+	load := make([]uint, 3)
+	for i, class := range classes {
+		if class >= 3 {
+			t.Fatalf(`'%c' has class %d`, i+'A', class)
+		}
+
+		load[class] += frequencies[i]
+	}
+
+	// The greedy assignment guarantees that the loads differ by at most the largest frequency.
+	if slices.Min(load) == 0 || slices.Max(load)-slices.Min(load) > slices.Max(frequencies) {
+		t.Fatalf(`Classes are not balanced: %v`, load)
+	}
+}
+
+func TestContextRoundTrip(t *testing.T) {
+	for _, classCount := range []int{1, 5, MaxContextClassCount} {
+		for _, keepOthers := range []bool{false, true} {
+			s := newTestSubstitutorWithOptions(t, flatnessTestText, Options{ContextClassCount: classCount})
+			if !s.HasContexts() || len(s.contextSubstitutions) != classCount {
+				t.Fatalf(`%d classes: Substitutor has %d classes`, classCount, len(s.contextSubstitutions))
+			}
+
+			for _, substitutions := range s.contextSubstitutions {
+				total := 0
+				for _, list := range substitutions {
+					total += list.Len()
+				}
+
+				if total != int(s.substitutionAlphabetSize) {
+					t.Fatalf(`%d classes: A class has %d substitutions instead of %d`, classCount, total, s.substitutionAlphabetSize)
+				}
+			}
+
+			data, err := s.MarshalBinary()
+			if err != nil {
+				t.Fatalf(`%d classes: Error marshalling: %v`, classCount, err)
+			}
+
+			if data[len(fileMagic)] != contextVersion {
+				t.Fatalf(`%d classes: File version is %d instead of %d`, classCount, data[len(fileMagic)], contextVersion)
+			}
+
+			loaded := &Substitutor{}
+			err = loaded.UnmarshalBinary(data)
+			if err != nil {
+				t.Fatalf(`%d classes: Error unmarshalling: %v`, classCount, err)
+			}
+
+			expectEqualSubstitutions(t, s, loaded)
+			if !slices.Equal(s.contextClasses, loaded.contextClasses) {
+				t.Fatalf(`%d classes: Loaded classes %v instead of %v`, classCount, loaded.contextClasses, s.contextClasses)
+			}
+
+			decrypted := encryptAndDecrypt(t, s, loaded, flatnessTestText, keepOthers)
+			expected := expectedClearText(flatnessTestText, keepOthers)
+			if decrypted != expected {
+				t.Fatalf("%d classes, keep %t: Decrypted\n%s\ninstead of\n%s", classCount, keepOthers, decrypted, expected)
+			}
+		}
+	}
+}
+
+func TestContextTooManyClasses(t *testing.T) {
+	_, err := newSubstitutorFromText(t, flatnessTestText, Options{ContextClassCount: MaxContextClassCount + 1})
+	if err == nil {
+		t.Fatal(`Too many predecessor classes were accepted`)
+	}
+}
+
+// TestContextFlattensBigrams checks that context-dependent substitutions make the bigrams of the encrypted text more even.
+func TestContextFlattensBigrams(t *testing.T) {
+	clearText := strings.Repeat(flatnessTestText, 20)
+
+	plain := newTestSubstitutorWithOptions(t, clearText, Options{})
+	context := newTestSubstitutorWithOptions(t, clearText, Options{ContextClassCount: MaxContextClassCount})
+
+	plainStatistics := encryptedStatistics(t, plain, clearText)
+	contextStatistics := encryptedStatistics(t, context, clearText)
+
+	if contextStatistics.BigramIndexOfCoincidence() >= plainStatistics.BigramIndexOfCoincidence() {
+		t.Fatalf(`Bigram index of coincidence with contexts %.3f is not lower than without %.3f`,
+			contextStatistics.BigramIndexOfCoincidence(),
+			plainStatistics.BigramIndexOfCoincidence())
+	}
+}
+
+func TestContextCorruptClasses(t *testing.T) {
+	s := newTestSubstitutorWithOptions(t, flatnessTestText, Options{ContextClassCount: 2})
+	s.contextClasses[3] = 7
+
+	data, err := s.MarshalBinary()
+	if err != nil {
+		t.Fatalf(`Error marshalling: %v`, err)
+	}
+
+	err = (&Substitutor{}).UnmarshalBinary(data)
+	if err == nil || !strings.Contains(err.Error(), `invalid predecessor class`) {
+		t.Fatalf(`Invalid predecessor class resulted in error %v`, err)
+	}
+}
+
+func TestContextDataLength(t *testing.T) {
+	for classCount := 1; classCount <= MaxContextClassCount; classCount++ {
+		dataLen := contextDataLength(classCount)
+		if !isValidDataLength(dataLen, contextVersion) {
+			t.Fatalf(`Data length %d of %d predecessor classes is not valid`, dataLen, classCount)
+		}
+
+		for _, wrongLen := range []int64{dataLen - 1, dataLen + 1, dataLen - contextHeaderLength} {
+			if isValidDataLength(wrongLen, contextVersion) {
+				t.Fatalf(`Wrong data length %d of %d predecessor classes is valid`, wrongLen, classCount)
+			}
+		}
+	}
+
+	for _, wrongLen := range []int64{substitutionDataLength, contextDataLength(0), contextDataLength(MaxContextClassCount + 1)} {
+		if isValidDataLength(wrongLen, contextVersion) {
+			t.Fatalf(`Wrong data length %d is valid`, wrongLen)
+		}
+	}
+}
+
+func TestContextWithSelection(t *testing.T) {
+	for _, options := range []Options{
+		{ContextClassCount: 2, Weighted: true},
+		{ContextClassCount: 2, Selection: randomlist.Window},
+	} {
+		_, err := newSubstitutorFromText(t, flatnessTestText, options)
+		if !errors.Is(err, ErrContextSelection) {
+			t.Fatalf(`%+v: Got error %v instead of %v`, options, err, ErrContextSelection)
+		}
+	}
+}
+
+// ******** Private functions ********
+
+// encryptAndDecrypt encrypts a clear text with one substitutor and decrypts it with another one.
+func encryptAndDecrypt(t *testing.T, encryptor *Substitutor, decryptor *Substitutor, clearText string, keepOthers bool) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	clearFileName := filepath.Join(dir, `clear.txt`)
+	encryptedFileName := filepath.Join(dir, `encrypted.txt`)
+	decryptedFileName := filepath.Join(dir, `decrypted.txt`)
+
+	err := os.WriteFile(clearFileName, []byte(clearText), 0600)
+	if err != nil {
+		t.Fatalf(`Error writing clear text file: %v`, err)
+	}
+
+	err = encryptor.Encrypt(clearFileName, encryptedFileName, keepOthers, false)
+	if err != nil {
+		t.Fatalf(`Error encrypting: %v`, err)
+	}
+
+	err = decryptor.Decrypt(encryptedFileName, decryptedFileName, false)
+	if err != nil {
+		t.Fatalf(`Error decrypting: %v`, err)
+	}
+
+	var decrypted []byte
+	decrypted, err = os.ReadFile(decryptedFileName)
+	if err != nil {
+		t.Fatalf(`Error reading decrypted file: %v`, err)
+	}
+
+	return string(decrypted)
+}
+
+// encryptedStatistics returns the statistics of the encrypted clear text.
+func encryptedStatistics(t *testing.T, s *Substitutor, clearText string) *analysis.Statistics {
+	t.Helper()
+
+	result := analysis.NewStatistics(analysis.SubstitutionAlphabet)
+	s.ResetContext()
+	for _, b := range bytes.ToUpper([]byte(clearText)) {
+		result.Add(s.SubstituteByte(b))
+	}
+
+	return result
+}
+
+// expectedClearText returns the text that results from encrypting and decrypting a clear text.
+func expectedClearText(clearText string, keepOthers bool) string {
+	var result strings.Builder
+	for _, b := range bytes.ToUpper([]byte(clearText)) {
+		if (b >= 'A' && b <= 'Z') || keepOthers {
+			result.WriteByte(b)
+		}
+	}
+
+	return result.String()
+}
@@ -20,12 +20,17 @@
 //
 // Author: Frank Schwab
 //
-// Version: 1.2.0
+// Version: 1.6.0
 //
 // Change history:
 //    2024-09-17: V1.0.0: Created.
 //    2025-01-02: V1.1.0: Refactored for less complexity.
 //    2025-02-17: V1.2.0: Simplified file reader.
+//    2026-10-18: V1.3.0: Write decrypted file atomically.
+//    2026-10-18: V1.4.0: Use a decryption table that can be wiped.
+//    2026-10-18: V1.4.1: Use selector interface for substitution lists.
+//    2026-10-18: V1.5.0: Decrypt context-dependent substitutions.
+//    2026-10-18: V1.6.0: Add streaming decryption.
 //
 
 package homosubst
@@ -39,45 +44,88 @@ import (
 	"os"
 )
 
+// ******** Private constants ********
+
+// decryptionTableSize is the size of the decryption table, i.e. the number of possible byte values.
+const decryptionTableSize = 256
+
 // ******** Public type functions ********
 
 // Decrypt decrypts the given file with the loaded homophone substitution.
-func (s *Substitutor) Decrypt(encryptedFileName string, decryptedFileName string) error {
-	encryptedFile, err := os.Open(encryptedFileName)
+// The decrypted file is only replaced when the decryption has been successful.
+// If overwrite is false, an existing decrypted file is not overwritten.
+func (s *Substitutor) Decrypt(encryptedFileName string, decryptedFileName string, overwrite bool) error {
+	return s.decryptToFile(encryptedFileName, decryptedFileName, overwrite, false)
+}
+
+// DecryptStream decrypts the text that is read from source and writes the decrypted text to destination.
+// Characters that are not substitution characters are copied.
+func (s *Substitutor) DecryptStream(source io.Reader, destination io.Writer) error {
+	return s.decryptStream(source, destination, ``, ``)
+}
+
+// ******** Private type functions ********
+
+// decryptToFile decrypts a file into another file.
+// If protect is true, the encrypted file is read in the protected format.
+func (s *Substitutor) decryptToFile(encryptedFileName string, decryptedFileName string, overwrite bool, protect bool) error {
+	err := checkDifferentFiles(encryptedFileName, decryptedFileName)
+	if err != nil {
+		return err
+	}
+
+	var encryptedFile *os.File
+	encryptedFile, err = os.Open(encryptedFileName)
 	if err != nil {
 		return makeFileError(`open`, `in`, encryptedFileName, err)
 	}
 	defer filehelper.CloseWithName(encryptedFile)
 
-	var decryptedFile *os.File
-	decryptedFile, err = os.OpenFile(decryptedFileName, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0644)
+	var decryptedFile *filehelper.AtomicFile
+	decryptedFile, err = filehelper.CreateAtomic(decryptedFileName, outputFilePermissions, overwrite)
 	if err != nil {
-		return makeFileError(`open`, `out`, decryptedFileName, err)
+		return makeFileError(`create`, `out`, decryptedFileName, err)
 	}
 	defer filehelper.CloseWithName(decryptedFile)
 
-	decryptionMap := buildDecryptionMap(s.substitutions)
-
-	err = s.decryptFile(encryptedFile, decryptedFile, decryptionMap)
+	if protect {
+		err = s.decryptProtectedFile(encryptedFile, decryptedFile)
+	} else {
+		err = s.decryptFile(encryptedFile, decryptedFile)
+	}
 	if err != nil {
 		return err
 	}
 
+	err = decryptedFile.Commit()
+	if err != nil {
+		return makeFileError(`write`, `out`, decryptedFileName, err)
+	}
+
 	return nil
 }
 
-// ******** Private type functions ********
-
 // decryptFile decrypts inFile and writes the decrypted to outFile.
 func (s *Substitutor) decryptFile(
 	inFile *os.File,
-	outFile *os.File,
-	decryptionMap map[byte]byte,
+	outFile *filehelper.AtomicFile,
+) error {
+	return s.decryptStream(inFile, outFile, inFile.Name(), outFile.Name())
+}
+
+// decryptStream decrypts a stream. The names of the streams are used for error messages and may be empty.
+func (s *Substitutor) decryptStream(
+	source io.Reader,
+	destination io.Writer,
+	sourceName string,
+	destinationName string,
 ) error {
 	var err error
 
-	reader := bufio.NewReader(inFile)
-	writer := bufio.NewWriter(outFile)
+	reader := bufio.NewReader(source)
+	writer := bufio.NewWriter(destination)
+
+	decryptionTable := s.getDecryptionTable()
 
 	for {
 		var r byte
@@ -88,33 +136,47 @@ func (s *Substitutor) decryptFile(
 				break
 			}
 
-			return makeFileError(`read from`, `in`, inFile.Name(), err)
+			return makeStreamError(`read from`, `in`, sourceName, err)
 		}
 
-		decrypted, found := decryptionMap[r]
-		if !found {
+		decrypted := decryptionTable[r]
+		if decrypted == 0 {
 			decrypted = r
+		} else if s.contextClasses != nil {
+			// The next character is decrypted with the table of the class of this one.
+			decryptionTable = s.decryptionTableFor(decrypted)
 		}
 
 		err = writer.WriteByte(decrypted)
 		if err != nil {
-			return makeFileError(`write to`, `out`, outFile.Name(), err)
+			return makeStreamError(`write to`, `out`, destinationName, err)
 		}
 	}
 
 	err = writer.Flush()
 	if err != nil {
-		return makeFileError(`flush`, `out`, outFile.Name(), err)
+		return makeStreamError(`flush`, `out`, destinationName, err)
 	}
 
 	return nil
 }
 
+// getDecryptionTable returns the decryption table and builds it, if it does not exist, yet.
+func (s *Substitutor) getDecryptionTable() []byte {
+	if s.decryptionTable == nil {
+		s.decryptionTable = buildDecryptionTable(s.substitutions)
+	}
+
+	return s.decryptionTable
+}
+
 // ******** Private functions ********
 
-// buildDecryptionMap builds the decryption map from the substitution lists.
-func buildDecryptionMap(substitutions []*randomlist.RandomList[byte]) map[byte]byte {
-	result := make(map[byte]byte)
+// buildDecryptionTable builds the decryption table from the substitution lists.
+// The table maps each substitution byte to the byte it substitutes.
+// Bytes that are not substitutions are mapped to 0.
+func buildDecryptionTable(substitutions []randomlist.Selector[byte]) []byte {
+	result := make([]byte, decryptionTableSize)
 	destinationByte := byte('A')
 	for _, list := range substitutions {
 		for _, substitution := range list.BaseList() {
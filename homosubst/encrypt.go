@@ -20,7 +20,7 @@
 //
 // Author: Frank Schwab
 //
-// Version: 2.0.0
+// Version: 2.4.0
 //
 // Change history:
 //    2024-09-17: V1.0.0: Created.
@@ -28,59 +28,117 @@
 //    2025-02-10: V1.2.0: Corrected rune substitution.
 //    2025-02-17: V1.3.0: Simplified function call.
 //    2025-02-17: V2.0.0: Handle only bytes.
+//    2026-10-18: V2.1.0: Write encrypted file atomically.
+//    2026-10-18: V2.2.0: Start each encryption without a predecessor.
+//    2026-10-18: V2.3.0: Add streaming encryption.
+//    2026-10-18: V2.4.0: Return an error for letters without substitutions.
 //
 
 package homosubst
 
 import (
 	"bufio"
+	"errors"
+	"fmt"
 	"homophone/filehelper"
+	"io"
 	"os"
 )
 
+// ******** Public constants ********
+
+// ErrNoSubstitution is returned when a letter is to be encrypted that has no substitutions.
+// This happens when a key is used for a text with letters that did not occur in the text the key was created from.
+var ErrNoSubstitution = errors.New(`no substitution for letter`)
+
 // ******** Public type functions ********
 
 // Encrypt encrypts the file named in the creation call with the built homophone substitution.
-func (s *Substitutor) Encrypt(clearFileName string, encryptedFileName string, keepOthers bool) error {
-	clearFile, err := os.Open(clearFileName)
+// The encrypted file is only replaced when the encryption has been successful.
+// If overwrite is false, an existing encrypted file is not overwritten.
+func (s *Substitutor) Encrypt(clearFileName string, encryptedFileName string, keepOthers bool, overwrite bool) error {
+	return s.encryptToFile(clearFileName, encryptedFileName, keepOthers, overwrite, false)
+}
+
+// EncryptStream encrypts the text that is read from source and writes the encrypted text to destination.
+// If keepOthers is true, characters that are not in the range A-Z or a-z are copied, otherwise they are discarded.
+func (s *Substitutor) EncryptStream(source io.Reader, destination io.Writer, keepOthers bool) error {
+	return s.encryptStream(source, destination, keepOthers, ``, ``)
+}
+
+// ******** Private type functions ********
+
+// encryptToFile encrypts a file into another file.
+// If protect is true, the encrypted file is written in the protected format.
+func (s *Substitutor) encryptToFile(clearFileName string, encryptedFileName string, keepOthers bool, overwrite bool, protect bool) error {
+	err := checkDifferentFiles(clearFileName, encryptedFileName)
+	if err != nil {
+		return err
+	}
+
+	var clearFile *os.File
+	clearFile, err = os.Open(clearFileName)
 	if err != nil {
 		return makeFileError(`open`, `in`, clearFileName, err)
 	}
 	defer filehelper.CloseWithName(clearFile)
 
-	var encryptedFile *os.File
-	encryptedFile, err = os.OpenFile(encryptedFileName, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0644)
+	var encryptedFile *filehelper.AtomicFile
+	encryptedFile, err = filehelper.CreateAtomic(encryptedFileName, outputFilePermissions, overwrite)
 	if err != nil {
-		return makeFileError(`open`, `out`, encryptedFileName, err)
+		return makeFileError(`create`, `out`, encryptedFileName, err)
 	}
 	defer filehelper.CloseWithName(encryptedFile)
 
-	err = s.encryptFile(clearFile, encryptedFile, keepOthers)
+	if protect {
+		err = s.encryptProtectedFile(clearFile, encryptedFile, keepOthers)
+	} else {
+		err = s.encryptFile(clearFile, encryptedFile, keepOthers)
+	}
 	if err != nil {
 		return err
 	}
 
+	err = encryptedFile.Commit()
+	if err != nil {
+		return makeFileError(`write`, `out`, encryptedFileName, err)
+	}
+
 	return nil
 }
 
-// ******** Private type functions ********
-
 // encryptFile encrypts a file.
 func (s *Substitutor) encryptFile(
 	inFile *os.File,
-	outFile *os.File,
+	outFile *filehelper.AtomicFile,
 	keepOthers bool,
+) error {
+	return s.encryptStream(inFile, outFile, keepOthers, inFile.Name(), outFile.Name())
+}
+
+// encryptStream encrypts a stream. The names of the streams are used for error messages and may be empty.
+func (s *Substitutor) encryptStream(
+	source io.Reader,
+	destination io.Writer,
+	keepOthers bool,
+	sourceName string,
+	destinationName string,
 ) error {
 	var err error
 
-	reader := bufio.NewReader(inFile)
-	writer := bufio.NewWriter(outFile)
+	reader := bufio.NewReader(source)
+	writer := bufio.NewWriter(destination)
 
+	s.ResetContext()
 	for {
 		var value byte
 		value, err = reader.ReadByte()
 		if err != nil {
-			break
+			if errors.Is(err, io.EOF) {
+				break
+			}
+
+			return makeStreamError(`read from`, `in`, sourceName, err)
 		}
 
 		switch {
@@ -89,7 +147,12 @@ func (s *Substitutor) encryptFile(
 			fallthrough
 
 		case value >= 'A' && value <= 'Z':
-			_ = writer.WriteByte(s.SubstituteByte(value))
+			selector := s.selectorFor(value)
+			if selector.Len() == 0 {
+				return fmt.Errorf(`'%c': %w`, value, ErrNoSubstitution)
+			}
+
+			_ = writer.WriteByte(selector.RandomElement())
 
 		default:
 			if keepOthers {
@@ -100,7 +163,7 @@ func (s *Substitutor) encryptFile(
 
 	err = writer.Flush()
 	if err != nil {
-		return makeFileError(`flush`, `out`, outFile.Name(), err)
+		return makeStreamError(`flush`, `out`, destinationName, err)
 	}
 
 	return nil
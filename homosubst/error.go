@@ -20,17 +20,58 @@
 //
 // Author: Frank Schwab
 //
-// Version: 1.0.0
+// Version: 1.3.0
 //
 // Change history:
 //    2025-01-02: V1.0.0: Created.
+//    2026-10-18: V1.1.0: Added check for identical input and output files.
+//    2026-10-18: V1.2.0: Added error for substitution files that are readable by others.
+//    2026-10-18: V1.3.0: Add stream errors.
 //
 
 package homosubst
 
-import "fmt"
+import (
+	"errors"
+	"fmt"
+	"homophone/filehelper"
+)
+
+// ******** Public constants ********
+
+// ErrSameFile is returned when the input and the output file are the same file.
+var ErrSameFile = errors.New(`input and output file are the same file`)
+
+// ErrReadableByOthers is returned when a substitution file is readable by group or others.
+var ErrReadableByOthers = errors.New(`substitution file is readable by group or others`)
+
+// ******** Private functions ********
 
 // makeFileError builds an error for a file error.
 func makeFileError(operation string, direction string, fileName string, err error) error {
 	return fmt.Errorf(`could not %s %sput file '%s': %w`, operation, direction, fileName, err)
 }
+
+// makeStreamError builds an error for a stream error.
+// If the stream has a name, it is a file error.
+func makeStreamError(operation string, direction string, name string, err error) error {
+	if len(name) != 0 {
+		return makeFileError(operation, direction, name, err)
+	}
+
+	return fmt.Errorf(`could not %s %sput: %w`, operation, direction, err)
+}
+
+// checkDifferentFiles checks that the input and the output file are not the same file.
+func checkDifferentFiles(inFileName string, outFileName string) error {
+	isSame, err := filehelper.IsSameFile(inFileName, outFileName)
+	if err != nil {
+		return err
+	}
+
+	if isSame {
+		return fmt.Errorf(`'%s' and '%s': %w`, inFileName, outFileName, ErrSameFile)
+	}
+
+	return nil
+}
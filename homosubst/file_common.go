@@ -20,25 +20,107 @@
 //
 // Author: Frank Schwab
 //
-// Version: 1.1.0
+// Version: 2.3.0
 //
 // Change history:
 //    2025-01-03: V1.0.0: Created.
 //    2025-01-05: V1.1.0: Correct substitution data length.
+//    2026-10-18: V1.2.0: Added output file permissions.
+//    2026-10-18: V1.3.0: Added substitution file permissions.
+//    2026-10-18: V2.0.0: Version 1 with hash algorithm in header.
+//    2026-10-18: V2.1.0: Version 3 with context section.
+//    2026-10-18: V2.2.0: Generate the integrity key only once.
+//    2026-10-18: V2.3.0: Version 4 with partial key.
 //
 
 package homosubst
 
+import (
+	"homophone/compressedinteger"
+	"homophone/integritycheckedfile"
+	"homophone/keygenerator"
+	"sync"
+)
+
 // ******** Private constants ********
 
 // fileMagic is the magic bytes of a substitution file.
 var fileMagic = []byte(`HFDF`)
 
+// versionWithoutHashAlgorithm is the version number of files that do not contain a hash algorithm.
+// These files always use the [defaultHashAlgorithm].
+const versionWithoutHashAlgorithm byte = 0
+
 // actVersion is the current version number.
-const actVersion byte = 0
+// Files with this version have the hash algorithm in the byte after the version number.
+const actVersion byte = 1
+
+// attributeVersion is the version number of files with an attribute section.
+// Files with this version have the hash algorithm in the byte after the version number and
+// an attribute section after the substitution lists. Each attribute is its identifier followed by its value.
+// The substitution lists of a baseline mode contain only one substitution per character.
+const attributeVersion byte = 2
+
+// Identifiers of the attributes in the attribute section.
+const (
+	// strategyAttribute is the selection strategy of the substitution lists.
+	strategyAttribute uint32 = iota + 1
+
+	// weightedAttribute indicates that the substitutions are selected with weights. It has no value.
+	weightedAttribute
+
+	// letterCountsAttribute contains the number of occurrences of each character A-Z in the source.
+	// The weights and the frequencies of the substitution characters are calculated from them.
+	letterCountsAttribute
+
+	// modeAttribute is the mode of the substitution. It is only written, if the mode is not [Homophonic].
+	modeAttribute
+)
+
+// maxAttributeSectionLength is the maximum length of the attribute section.
+const maxAttributeSectionLength = 2 + 1 + 1 + int(sourceAlphabetSize)*compressedinteger.MaxLength64 + 2
+
+// contextVersion is the version number of files with context-dependent substitutions.
+// Files with this version have the hash algorithm in the byte after the version number and
+// a context section after the substitution lists.
+const contextVersion byte = 3
+
+// partialVersion is the version number of files with a partial key.
+// Files with this version have the hash algorithm in the byte after the version number and
+// substitution lists that do not contain all characters of the substitution alphabet.
+const partialVersion byte = 4
 
-// substitutionDataLength is the length of the substitution data in a substitution file.
-const substitutionDataLength = 136
+// defaultHashAlgorithm is the hash algorithm used for the integrity check, if none is specified.
+const defaultHashAlgorithm = integritycheckedfile.HashSHA3_256
+
+// substitutionDataLength is the length of the substitution data after the header in a substitution file.
+const substitutionDataLength = 131
+
+// baselineDataLength is the length of the substitution data of a baseline mode, where each character
+// has exactly one substitution.
+const baselineDataLength = 1 + 3*int64(sourceAlphabetSize)
+
+// minAttributeDataLength is the minimum length of the substitution data of a file with an attribute section.
+// It is the length of a key of a baseline mode without letter counts, whose attribute section only contains the mode.
+const minAttributeDataLength = baselineDataLength + 2
+
+// contextHeaderLength is the length of the number of predecessor classes and the classes of the characters
+// at the start of the context section.
+const contextHeaderLength = 1 + int64(sourceAlphabetSize)
+
+// contextListsLength is the length of the substitution lists of one predecessor class in the context section.
+// They are the substitution data without the size of the substitution alphabet.
+const contextListsLength = substitutionDataLength - 1
+
+// outputFilePermissions are the permissions of encrypted and decrypted files.
+const outputFilePermissions = 0644
+
+// substitutionFilePermissions are the permissions of substitution files.
+// Only the owner may read and write them.
+const substitutionFilePermissions = 0600
+
+// othersReadPermissions are the permission bits that allow group and others to read a file.
+const othersReadPermissions = 0044
 
 // generator is the starter value for the integrity key generation.
 var generator = []byte{
@@ -59,3 +141,9 @@ var salt = []byte{
 
 // additionalData is the additional data needed for the integrity check.
 var additionalData = []byte(`HoTzpLoZ`)
+
+// integrityKey returns the key for the integrity check.
+// It is always the same, so the expensive key generation is done only once.
+var integrityKey = sync.OnceValue(func() []byte {
+	return keygenerator.GenerateKey(generator, salt)
+})
@@ -20,7 +20,7 @@
 //
 // Author: Frank Schwab
 //
-// Version: 3.0.0
+// Version: 4.4.0
 //
 // Change history:
 //    2024-09-17: V1.0.0: Created.
@@ -29,30 +29,51 @@
 //    2025-01-06: V2.1.0: Check file header before checking file integrity.
 //    2025-01-06: V2.1.1: Do not calculate header length twice.
 //    2025-01-06: V3.0.0: Rename creation function to "NewFromFile".
+//    2026-10-18: V3.1.0: Separate loading from file handling.
+//    2026-10-18: V3.2.0: Check if substitution file is readable by others.
+//    2026-10-18: V4.0.0: Read hash algorithm from file header.
+//    2026-10-18: V4.1.0: Read substitution data as a stream.
+//    2026-10-18: V4.2.0: Reject substitution entries that are not in the substitution alphabet.
+//    2026-10-18: V4.2.1: Use selector interface for substitution lists.
+//    2026-10-18: V4.3.0: Read context section.
+//    2026-10-18: V4.3.1: Use the integrity key that is generated only once.
+//    2026-10-18: V4.4.0: Load partial keys.
 //
 
 package homosubst
 
 import (
+	"bufio"
 	"bytes"
 	"errors"
 	"fmt"
-	"golang.org/x/crypto/sha3"
+	"hash"
 	"homophone/compressedinteger"
 	"homophone/filehelper"
 	"homophone/integritycheckedfile"
-	"homophone/keygenerator"
+	"homophone/oshelper"
 	"homophone/randomlist"
 	"io"
+	"math"
 	"os"
+	"strings"
 )
 
 // NewFromFile creates a new Substitutor from a substitution file.
+// It does not check the permissions of the file. This is done by [CheckFilePermissions].
 func NewFromFile(substFileName string) (*Substitutor, error) {
 	var err error
 
 	var headerLen int64
-	headerLen, err = checkHeader(substFileName)
+	var version byte
+	var hashAlgorithm integritycheckedfile.HashAlgorithm
+	headerLen, version, hashAlgorithm, err = checkHeader(substFileName)
+	if err != nil {
+		return nil, err
+	}
+
+	var hashFunc func() hash.Hash
+	hashFunc, err = hashAlgorithm.HashFunc()
 	if err != nil {
 		return nil, err
 	}
@@ -60,37 +81,160 @@ func NewFromFile(substFileName string) (*Substitutor, error) {
 	var r *integritycheckedfile.Reader
 	r, err = integritycheckedfile.NewReader(
 		substFileName,
-		sha3.New256,
-		keygenerator.GenerateKey(generator, salt),
+		hashFunc,
+		integrityKey(),
 		additionalData)
 	if err != nil {
 		return nil, err
 	}
 	defer filehelper.CloseWithName(r)
 
-	// Check data length.
-	if r.DataLen() != substitutionDataLength {
+	return loadFromReader(r, headerLen, version, hashAlgorithm)
+}
+
+// CheckFilePermissions checks that a substitution file is not readable by group or others.
+// It returns an error that wraps [ErrReadableByOthers], if it is.
+// The check is only done on systems that have file permissions.
+func CheckFilePermissions(substFileName string) error {
+	if !oshelper.HasFilePermissions {
+		return nil
+	}
+
+	fi, err := os.Stat(substFileName)
+	if err != nil {
+		return err
+	}
+
+	perm := fi.Mode().Perm()
+	if perm&othersReadPermissions != 0 {
+		return fmt.Errorf(`'%s' has permissions %04o: %w`, substFileName, perm, ErrReadableByOthers)
+	}
+
+	return nil
+}
+
+// ******** Private functions ********
+
+// loadFromReader creates a new Substitutor from an integrity-checked reader
+// whose header has a length of headerLen and has already been checked.
+func loadFromReader(
+	r *integritycheckedfile.Reader,
+	headerLen int64,
+	version byte,
+	hashAlgorithm integritycheckedfile.HashAlgorithm,
+) (*Substitutor, error) {
+	var err error
+
+	// Check data length. Files with a context or attribute section are longer, files with a partial key are shorter.
+	dataLen := r.DataLen() - headerLen
+	if !isValidDataLength(dataLen, version) {
 		return nil, errors.New(`wrong file size`)
 	}
 
-	// Skip the header that has been checked at the start of this function.
+	// Skip the header that has already been checked.
 	_, err = r.Seek(headerLen, io.SeekStart)
+	if err != nil {
+		return nil, err
+	}
+
+	// Load substitutions from the rest of the file.
+	source := compressedinteger.NewReader(bufio.NewReader(r))
+	switch version {
+	case contextVersion:
+		return loadContextSubstitutionData(source, hashAlgorithm, dataLen)
+
+	case attributeVersion:
+		return loadAttributeSubstitutionData(source, hashAlgorithm)
+
+	default:
+		var substitutionAlphabetSize uint32
+		var substitutions []randomlist.Selector[byte]
+		substitutionAlphabetSize, substitutions, err = loadSubstitutionData(source, version == partialVersion)
+		if err != nil {
+			return nil, err
+		}
+
+		return &Substitutor{
+			substitutions:            substitutions,
+			substitutionAlphabetSize: uint16(substitutionAlphabetSize),
+			hashAlgorithm:            hashAlgorithm,
+		}, nil
+	}
+}
+
+// isValidDataLength checks whether the data length of a file fits its version.
+func isValidDataLength(dataLen int64, version byte) bool {
+	switch version {
+	case contextVersion:
+		return contextClassCountOfLength(dataLen) > 0
+
+	case partialVersion:
+		return dataLen < substitutionDataLength
+
+	case attributeVersion:
+		return dataLen >= minAttributeDataLength && dataLen <= substitutionDataLength+int64(maxAttributeSectionLength)
+
+	default:
+		return dataLen == substitutionDataLength
+	}
+}
+
+// loadSubstitutionData loads all substitution data of a file without a context section.
+// If partial is true, the substitution lists need not contain all characters of the substitution alphabet.
+func loadSubstitutionData(source *compressedinteger.Reader, partial bool) (uint32, []randomlist.Selector[byte], error) {
+	substitutionAlphabetSize, substitutions, err := readSubstitutionData(source, partial)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	err = expectEnd(source)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	return substitutionAlphabetSize, substitutions, nil
+}
+
+// contextClassCountOfLength returns the number of predecessor classes of a file with a context section
+// of the data length dataLen. It is 0, if there is no valid number.
+func contextClassCountOfLength(dataLen int64) int {
+	classesLen := dataLen - substitutionDataLength - contextHeaderLength
+	if classesLen <= 0 || classesLen%contextListsLength != 0 || classesLen/contextListsLength > int64(MaxContextClassCount) {
+		return 0
+	}
+
+	return int(classesLen / contextListsLength)
+}
 
-	// Read the rest of the file.
-	substitutionData := make([]byte, int(r.DataLen()-headerLen))
-	var readBytes int
-	readBytes, err = r.Read(substitutionData)
+// contextDataLength returns the data length of a file with a context section with classCount predecessor classes.
+func contextDataLength(classCount int) int64 {
+	return substitutionDataLength + contextHeaderLength + int64(classCount)*contextListsLength
+}
+
+// loadContextSubstitutionData loads all substitution data of a file with a context section.
+// The number of predecessor classes must fit the data length dataLen.
+func loadContextSubstitutionData(
+	source *compressedinteger.Reader,
+	hashAlgorithm integritycheckedfile.HashAlgorithm,
+	dataLen int64,
+) (*Substitutor, error) {
+	substitutionAlphabetSize, substitutions, err := readSubstitutionData(source, false)
 	if err != nil {
 		return nil, err
 	}
-	if readBytes != len(substitutionData) {
-		return nil, errors.New(`could not read all substitution data`)
+
+	var classes []byte
+	var contextSubstitutions [][]randomlist.Selector[byte]
+	classes, contextSubstitutions, err = readContexts(source, substitutionAlphabetSize)
+	if err != nil {
+		return nil, err
 	}
 
-	// Load substitutions from read data.
-	var substitutionAlphabetSize uint32
-	var substitutions []*randomlist.RandomList[byte]
-	substitutionAlphabetSize, substitutions, err = loadSubstitutionData(substitutionData)
+	if contextDataLength(len(contextSubstitutions)) != dataLen {
+		return nil, fmt.Errorf(`wrong file size for %d predecessor classes`, len(contextSubstitutions))
+	}
+
+	err = expectEnd(source)
 	if err != nil {
 		return nil, err
 	}
@@ -98,27 +242,242 @@ func NewFromFile(substFileName string) (*Substitutor, error) {
 	return &Substitutor{
 		substitutions:            substitutions,
 		substitutionAlphabetSize: uint16(substitutionAlphabetSize),
+		hashAlgorithm:            hashAlgorithm,
+		contextClasses:           classes,
+		contextSubstitutions:     contextSubstitutions,
 	}, nil
 }
 
-// loadSubstitutionData loads all substitution data.
-func loadSubstitutionData(substitutionData []byte) (uint32, []*randomlist.RandomList[byte], error) {
-	var err error
-	var readBytes int
+// loadAttributeSubstitutionData loads all substitution data of a file with an attribute section.
+// The selectors of the substitution lists are rebuilt with the selection strategy and the weights from the attributes.
+// Only the substitution lists of the baseline modes need not contain all characters of the substitution alphabet.
+func loadAttributeSubstitutionData(source *compressedinteger.Reader, hashAlgorithm integritycheckedfile.HashAlgorithm) (*Substitutor, error) {
+	substitutionAlphabetSize, substitutions, err := readSubstitutionData(source, true)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &Substitutor{
+		substitutionAlphabetSize: uint16(substitutionAlphabetSize),
+		hashAlgorithm:            hashAlgorithm,
+	}
+
+	// The attribute section extends to the end of the data.
+	err = result.readAttributes(source)
+	if err != nil {
+		return nil, err
+	}
+
+	if result.mode != Homophonic {
+		return result.withLoadedBaselineSubstitutions(substitutions)
+	}
+
+	substitutionCount := 0
+	for _, list := range substitutions {
+		substitutionCount += list.Len()
+	}
+
+	if substitutionCount < int(substitutionAlphabetSize) {
+		return nil, errors.New(`not enough substitutions`)
+	}
+
+	totalCount := uint(0)
+	for _, count := range result.letterCounts {
+		totalCount += count
+	}
+
+	lists := make([][]byte, len(substitutions))
+	for i, list := range substitutions {
+		lists[i] = list.BaseList()
+	}
+
+	result.substitutions, err = makeSelectors(lists, result.letterCounts, totalCount, result.substitutionAlphabetSize, Options{
+		Selection: result.selection,
+		Weighted:  result.weighted,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result.proportions = makeProportions(result.letterCounts, totalCount)
+	result.letterShares = makeLetterShares(result.letterCounts, totalCount)
+
+	return result, nil
+}
+
+// withLoadedBaselineSubstitutions sets the loaded substitution lists of a baseline mode.
+// The frequencies are only known, if the letter counts have been loaded.
+func (s *Substitutor) withLoadedBaselineSubstitutions(substitutions []randomlist.Selector[byte]) (*Substitutor, error) {
+	if s.selection != randomlist.DefaultStrategy || s.weighted {
+		return nil, fmt.Errorf(`selection attributes in mode '%s'`, s.mode)
+	}
+
+	err := checkBaselineSubstitutions(s.mode, substitutions)
+	if err != nil {
+		return nil, err
+	}
+
+	s.substitutions = substitutions
+
+	if s.letterCounts != nil {
+		totalCount := uint(0)
+		for _, count := range s.letterCounts {
+			totalCount += count
+		}
+
+		s.proportions = makeProportions(s.letterCounts, totalCount)
+		s.letterShares = makeLetterShares(s.letterCounts, totalCount)
+	}
+
+	return s, nil
+}
+
+// readAttributes reads the attribute section up to the end of the data.
+// Each attribute may occur only once and the letter counts are required, if the mode is homophonic.
+func (s *Substitutor) readAttributes(source *compressedinteger.Reader) error {
+	seen := make(map[uint32]bool)
+	for {
+		attribute, err := source.ReadUInt32()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+
+			return err
+		}
+
+		if seen[attribute] {
+			return fmt.Errorf(`duplicate attribute: %d`, attribute)
+		}
+		seen[attribute] = true
+
+		switch attribute {
+		case strategyAttribute:
+			var strategy uint32
+			strategy, err = source.ReadUInt32()
+			if err != nil {
+				return unexpectedEOF(err)
+			}
+
+			if strategy >= uint32(len(randomlist.StrategyNames())) {
+				return fmt.Errorf(`invalid selection strategy: %d`, strategy)
+			}
+
+			s.selection = randomlist.Strategy(strategy)
+
+		case weightedAttribute:
+			s.weighted = true
+
+		case letterCountsAttribute:
+			s.letterCounts, err = readLetterCounts(source)
+			if err != nil {
+				return err
+			}
+
+		case modeAttribute:
+			var mode uint32
+			mode, err = source.ReadUInt32()
+			if err != nil {
+				return unexpectedEOF(err)
+			}
+
+			if mode == uint32(Homophonic) || mode >= uint32(len(modeNames)) {
+				return fmt.Errorf(`invalid mode: %d`, mode)
+			}
+
+			s.mode = Mode(mode)
+
+		default:
+			return fmt.Errorf(`unknown attribute: %d`, attribute)
+		}
+	}
+
+	if s.letterCounts == nil && s.mode == Homophonic {
+		return errors.New(`letter counts are missing`)
+	}
+
+	return nil
+}
+
+// readLetterCounts reads the number of occurrences of each character A-Z.
+// At least one count must not be 0 and the total must fit into an uint.
+func readLetterCounts(source *compressedinteger.Reader) ([]uint, error) {
+	result := make([]uint, sourceAlphabetSize)
+	totalCount := uint64(0)
+	for i := range result {
+		count, err := source.ReadUInt64()
+		if err != nil {
+			return nil, unexpectedEOF(err)
+		}
+
+		totalCount += count
+		if totalCount < count || totalCount > math.MaxUint {
+			return nil, errors.New(`letter counts are too large`)
+		}
+
+		result[i] = uint(count)
+	}
+
+	if totalCount == 0 {
+		return nil, errors.New(`all letter counts are 0`)
+	}
+
+	return result, nil
+}
+
+// readContexts reads the context section, i.e. the number of predecessor classes, the class
+// of each character and the substitution lists of each class.
+func readContexts(source *compressedinteger.Reader, substitutionAlphabetSize uint32) ([]byte, [][]randomlist.Selector[byte], error) {
+	classCount, err := source.ReadUInt32()
+	if err != nil {
+		return nil, nil, unexpectedEOF(err)
+	}
+
+	if classCount == 0 || classCount > uint32(MaxContextClassCount) {
+		return nil, nil, fmt.Errorf(`invalid number of predecessor classes: %d`, classCount)
+	}
+
+	classes := make([]byte, sourceAlphabetSize)
+	for i := range classes {
+		var class uint32
+		class, err = source.ReadUInt32()
+		if err != nil {
+			return nil, nil, unexpectedEOF(err)
+		}
+
+		if class >= classCount {
+			return nil, nil, fmt.Errorf(`invalid predecessor class of '%c': %d`, i+'A', class)
+		}
 
+		classes[i] = byte(class)
+	}
+
+	contextSubstitutions := make([][]randomlist.Selector[byte], classCount)
+	for class := range contextSubstitutions {
+		contextSubstitutions[class], err = loadSubstitutionLists(source, substitutionAlphabetSize, false)
+		if err != nil {
+			return nil, nil, fmt.Errorf(`predecessor class %d: %w`, class+1, err)
+		}
+	}
+
+	return classes, contextSubstitutions, nil
+}
+
+// readSubstitutionData reads the substitution alphabet size and the substitution lists.
+// If partial is true, the substitution lists need not contain all characters of the substitution alphabet.
+func readSubstitutionData(source *compressedinteger.Reader, partial bool) (uint32, []randomlist.Selector[byte], error) {
 	// Check size of substitution alphabet.
-	var substitutionAlphabetSize uint32
-	substitutionAlphabetSize, readBytes, err = compressedinteger.ToUInt32(substitutionData)
+	substitutionAlphabetSize, err := source.ReadUInt32()
 	if err != nil {
-		return 0, nil, err
+		return 0, nil, unexpectedEOF(err)
 	}
 
 	if substitutionAlphabetSize != requiredSubstitutionAlphabetSize {
 		return 0, nil, fmt.Errorf(`wrong substitution alphabet size: %d`, substitutionAlphabetSize)
 	}
 
-	var substitutions []*randomlist.RandomList[byte]
-	substitutions, err = loadSubstitutionLists(substitutionData[readBytes:], substitutionAlphabetSize)
+	var substitutions []randomlist.Selector[byte]
+	substitutions, err = loadSubstitutionLists(source, substitutionAlphabetSize, partial)
 	if err != nil {
 		return 0, nil, err
 	}
@@ -126,53 +485,42 @@ func loadSubstitutionData(substitutionData []byte) (uint32, []*randomlist.Random
 	return substitutionAlphabetSize, substitutions, nil
 }
 
-// loadSubstitutionLists loads all substitution lists from the substitution data.
-func loadSubstitutionLists(substitutionData []byte, substitutionAlphabetSize uint32) ([]*randomlist.RandomList[byte], error) {
-	var err error
-	var readBytes int
-
+// loadSubstitutionLists loads the substitution lists of all characters from the substitution data.
+// If partial is true, the substitution lists need not contain all characters of the substitution alphabet.
+func loadSubstitutionLists(source *compressedinteger.Reader, substitutionAlphabetSize uint32, partial bool) ([]randomlist.Selector[byte], error) {
 	// Read all substitution lists.
-	substitutions := make([]*randomlist.RandomList[byte], sourceAlphabetSize)
+	substitutions := make([]randomlist.Selector[byte], sourceAlphabetSize)
 	check := make(map[byte]bool)
-	listCount := 0
 	substitutionCount := 0
-	for len(substitutionData) != 0 {
+	for i := range substitutions {
 		// Get size of substitution list.
-		var listSize uint32
-		listSize, readBytes, err = compressedinteger.ToUInt32(substitutionData)
+		listSize, err := source.ReadUInt32()
 		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil, errors.New(`not enough substitution entries`)
+			}
+
 			return nil, err
 		}
 
-		substitutionData = substitutionData[readBytes:]
-		listCount++
 		substitutionCount += int(listSize)
 
-		if listCount > int(sourceAlphabetSize) {
-			return nil, errors.New(`too many substitution entries`)
-		}
-
 		if substitutionCount > int(substitutionAlphabetSize) {
 			return nil, errors.New(`too many substitutions`)
 		}
 
 		// Get the substitution list.
 		var list []byte
-		list, substitutionData, err = loadOneSubstitutionList(listSize, substitutionData, check)
+		list, err = loadOneSubstitutionList(listSize, source, check)
 		if err != nil {
 			return nil, err
 		}
 
-		substitutions[listCount-1] = randomlist.New(list)
-	}
-
-	// Check list size ...
-	if listCount < int(sourceAlphabetSize) {
-		return nil, errors.New(`not enough substitution entries`)
+		substitutions[i] = randomlist.New(list)
 	}
 
-	// ... and number of substitutions.
-	if substitutionCount < int(substitutionAlphabetSize) {
+	// Check number of substitutions.
+	if !partial && substitutionCount < int(substitutionAlphabetSize) {
 		return nil, errors.New(`not enough substitutions`)
 	}
 
@@ -180,63 +528,114 @@ func loadSubstitutionLists(substitutionData []byte, substitutionAlphabetSize uin
 }
 
 // loadOneSubstitutionList loads one substitution list from the substitution data.
-func loadOneSubstitutionList(listSize uint32, substitutionData []byte, check map[byte]bool) ([]byte, []byte, error) {
-	var err error
-
+func loadOneSubstitutionList(listSize uint32, source *compressedinteger.Reader, check map[byte]bool) ([]byte, error) {
 	list := make([]byte, listSize)
 
-	var readBytes int
-	var entry uint32
 	for i := range listSize {
-		entry, readBytes, err = compressedinteger.ToUInt32(substitutionData)
+		entry, err := source.ReadUInt32()
 		if err != nil {
-			return nil, nil, err
+			return nil, unexpectedEOF(err)
+		}
+
+		if !isSubstitutionCharacter(entry) {
+			return nil, fmt.Errorf(`invalid substitution entry: %d`, entry)
 		}
 
-		substitutionData = substitutionData[readBytes:]
 		entryByte := byte(entry)
 
 		if check[entryByte] {
-			return nil, nil, fmt.Errorf(`duplicate substitution entry: '%c'`, entryByte)
+			return nil, fmt.Errorf(`duplicate substitution entry: '%c'`, entryByte)
 		}
 
 		list[i] = entryByte
 		check[entryByte] = true
 	}
 
-	return list, substitutionData, nil
+	return list, nil
+}
+
+// isSubstitutionCharacter checks whether entry is a character of the substitution alphabet.
+func isSubstitutionCharacter(entry uint32) bool {
+	return entry <= math.MaxUint8 && strings.IndexByte(substitutionAlphabet, byte(entry)) >= 0
+}
+
+// expectEnd checks that there are no more data.
+func expectEnd(source *compressedinteger.Reader) error {
+	_, err := source.ReadUInt32()
+	if errors.Is(err, io.EOF) {
+		return nil
+	}
+
+	return errors.New(`too many substitution entries`)
+}
+
+// unexpectedEOF converts an [io.EOF] into an [io.ErrUnexpectedEOF], as the data end too early.
+func unexpectedEOF(err error) error {
+	if errors.Is(err, io.EOF) {
+		return io.ErrUnexpectedEOF
+	}
+
+	return err
 }
 
 // checkHeader checks the file header.
-func checkHeader(filePath string) (int64, error) {
+func checkHeader(filePath string) (int64, byte, integritycheckedfile.HashAlgorithm, error) {
 	f, err := os.Open(filePath)
 	if err != nil {
-		return 0, err
+		return 0, 0, 0, err
 	}
 	defer filehelper.CloseWithName(f)
 
+	return checkHeaderFromReader(f)
+}
+
+// checkHeaderFromReader checks the header at the current position of a reader.
+// It returns the header length, the version and the hash algorithm of the integrity check.
+func checkHeaderFromReader(r io.Reader) (int64, byte, integritycheckedfile.HashAlgorithm, error) {
+	var err error
 	var totalLen int
 	var readLen int
+
 	// Check magic bytes.
 	buffer := make([]byte, len(fileMagic))
-	readLen, err = f.Read(buffer)
+	readLen, err = io.ReadFull(r, buffer)
 	if err != nil {
-		return 0, err
+		return 0, 0, 0, err
 	}
 	if !bytes.Equal(buffer, fileMagic) {
-		return 0, errors.New(`invalid file type`)
+		return 0, 0, 0, errors.New(`invalid file type`)
 	}
 	totalLen = readLen
 
 	// Check version number.
-	readLen, err = f.Read(buffer[:1])
+	readLen, err = io.ReadFull(r, buffer[:1])
 	if err != nil {
-		return 0, err
-	}
-	if buffer[0] != actVersion {
-		return 0, fmt.Errorf(`unknown file version`)
+		return 0, 0, 0, err
 	}
 	totalLen += readLen
 
-	return int64(totalLen), nil
+	version := buffer[0]
+	switch version {
+	case versionWithoutHashAlgorithm:
+		return int64(totalLen), version, defaultHashAlgorithm, nil
+
+	case actVersion, contextVersion, partialVersion, attributeVersion:
+		// Get hash algorithm.
+		readLen, err = io.ReadFull(r, buffer[:1])
+		if err != nil {
+			return 0, 0, 0, err
+		}
+		totalLen += readLen
+
+		hashAlgorithm := integritycheckedfile.HashAlgorithm(buffer[0])
+		_, err = hashAlgorithm.HashFunc()
+		if err != nil {
+			return 0, 0, 0, err
+		}
+
+		return int64(totalLen), version, hashAlgorithm, nil
+
+	default:
+		return 0, 0, 0, fmt.Errorf(`unknown file version`)
+	}
 }
@@ -0,0 +1,173 @@
+//
+// SPDX-FileCopyrightText: Copyright 2026 Frank Schwab
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileType: SOURCE
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+//
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Author: Frank Schwab
+//
+// Version: 1.4.0
+//
+// Change history:
+//    2026-10-18: V1.0.0: Created.
+//    2026-10-18: V1.1.0: Check the semantic round trip of older versions.
+//    2026-10-18: V1.2.0: Check the attributes in the round trip.
+//    2026-10-18: V1.3.0: Check the context-dependent substitutions in the round trip.
+//    2026-10-18: V1.4.0: Check the mode in the round trip.
+//
+
+package homosubst
+
+import (
+	"bufio"
+	"bytes"
+	"homophone/compressedinteger"
+	"slices"
+	"testing"
+)
+
+// ******** Fuzz functions ********
+
+// FuzzLoadSubstitutionData feeds arbitrary substitution data into the parser, bypassing the integrity check.
+// Every accepted input must be written back to identical bytes.
+func FuzzLoadSubstitutionData(f *testing.F) {
+	f.Fuzz(func(t *testing.T, data []byte) {
+		alphabetSize, substitutions, err := loadSubstitutionData(compressedinteger.NewReader(bytes.NewReader(data)), false)
+		if err != nil {
+			return
+		}
+
+		if len(substitutions) != int(sourceAlphabetSize) {
+			t.Fatalf(`Accepted %d substitution lists instead of %d`, len(substitutions), sourceAlphabetSize)
+		}
+
+		total := 0
+		for _, list := range substitutions {
+			total += list.Len()
+		}
+		if total != int(alphabetSize) {
+			t.Fatalf(`Accepted %d substitutions instead of %d`, total, alphabetSize)
+		}
+
+		var buffer bytes.Buffer
+		w := compressedinteger.NewWriter(&buffer)
+		err = w.WriteUInt32(alphabetSize)
+		if err != nil {
+			t.Fatalf(`Error writing alphabet size: %v`, err)
+		}
+
+		err = saveSubstitutions(w, substitutions)
+		if err != nil {
+			t.Fatalf(`Error saving accepted substitutions: %v`, err)
+		}
+
+		if !bytes.Equal(buffer.Bytes(), data) {
+			t.Fatalf("Accepted data 0x%x are saved as 0x%x", data, buffer.Bytes())
+		}
+	})
+}
+
+// FuzzCheckHeader feeds arbitrary data into the header parser.
+func FuzzCheckHeader(f *testing.F) {
+	f.Fuzz(func(t *testing.T, data []byte) {
+		headerLen, _, hashAlgorithm, err := checkHeaderFromReader(bufio.NewReader(bytes.NewReader(data)))
+		if err != nil {
+			return
+		}
+
+		if headerLen > int64(len(data)) {
+			t.Fatalf(`Header length %d is larger than data length %d`, headerLen, len(data))
+		}
+
+		_, err = hashAlgorithm.HashFunc()
+		if err != nil {
+			t.Fatalf(`Accepted header has an unusable hash algorithm: %v`, err)
+		}
+	})
+}
+
+// FuzzUnmarshalBinary feeds arbitrary data into the complete substitution file parser.
+// Every accepted input must be marshaled to data that are unmarshaled to the same substitutor.
+// Inputs of older versions are written with the current version, so only inputs that already
+// have the version the substitutor is saved with must be written back to identical bytes.
+func FuzzUnmarshalBinary(f *testing.F) {
+	f.Fuzz(func(t *testing.T, data []byte) {
+		var s Substitutor
+		err := s.UnmarshalBinary(data)
+		if err != nil {
+			return
+		}
+
+		var saved []byte
+		saved, err = s.MarshalBinary()
+		if err != nil {
+			t.Fatalf(`Error marshaling accepted data: %v`, err)
+		}
+
+		var reloaded Substitutor
+		err = reloaded.UnmarshalBinary(saved)
+		if err != nil {
+			t.Fatalf(`Error unmarshaling marshaled data: %v`, err)
+		}
+
+		expectSameSubstitutor(t, &s, &reloaded)
+
+		version := data[len(fileMagic)]
+		if version == s.fileVersion() && !bytes.Equal(saved, data) {
+			t.Fatalf(`Accepted data of version %d are not marshaled to identical bytes`, version)
+		}
+	})
+}
+
+// ******** Private functions ********
+
+// expectSameSubstitutor checks that two substitutors have the same substitutions and attributes.
+func expectSameSubstitutor(t *testing.T, expected *Substitutor, got *Substitutor) {
+	t.Helper()
+
+	expectEqualSubstitutions(t, expected, got)
+
+	if expected.hashAlgorithm != got.hashAlgorithm {
+		t.Fatalf(`Expected hash algorithm %s, got %s`, expected.hashAlgorithm, got.hashAlgorithm)
+	}
+
+	if expected.mode != got.mode || expected.selection != got.selection || expected.weighted != got.weighted {
+		t.Fatalf(`Expected mode '%s', selection '%s' and weighted %t, got '%s', '%s' and %t`,
+			expected.mode, expected.selection, expected.weighted,
+			got.mode, got.selection, got.weighted)
+	}
+
+	if !slices.Equal(expected.letterCounts, got.letterCounts) {
+		t.Fatalf(`Expected letter counts %v, got %v`, expected.letterCounts, got.letterCounts)
+	}
+
+	if !slices.Equal(expected.contextClasses, got.contextClasses) {
+		t.Fatalf(`Expected context classes %v, got %v`, expected.contextClasses, got.contextClasses)
+	}
+
+	if len(expected.contextSubstitutions) != len(got.contextSubstitutions) {
+		t.Fatalf(`Expected %d context classes, got %d`, len(expected.contextSubstitutions), len(got.contextSubstitutions))
+	}
+
+	for class, substitutions := range expected.contextSubstitutions {
+		for i, list := range substitutions {
+			if !slices.Equal(list.BaseList(), got.contextSubstitutions[class][i].BaseList()) {
+				t.Fatalf(`Substitutions for '%c' in context class %d differ`, i+'A', class)
+			}
+		}
+	}
+}
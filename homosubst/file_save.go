@@ -20,95 +20,240 @@
 //
 // Author: Frank Schwab
 //
-// Version: 2.0.1
+// Version: 4.2.0
 //
 // Change history:
 //    2024-09-17: V1.0.0: Created.
 //    2025-01-04: V2.0.0: Restructured.
 //    2025-01-05: V2.0.1: Use interface, instead of type.
+//    2026-10-18: V2.1.0: Separate saving from file handling.
+//    2026-10-18: V2.2.0: Write substitution file atomically.
+//    2026-10-18: V2.3.0: Substitution file is only accessible by its owner.
+//    2026-10-18: V3.0.0: Write hash algorithm into header.
+//    2026-10-18: V3.1.0: Write substitution lists in one piece.
+//    2026-10-18: V4.0.0: Write substitution data as a stream.
+//    2026-10-18: V4.0.1: Use selector interface for substitution lists.
+//    2026-10-18: V4.1.0: Write context section.
+//    2026-10-18: V4.1.1: Use the integrity key that is generated only once.
+//    2026-10-18: V4.2.0: Write partial keys.
 //
 
 package homosubst
 
 import (
-	"golang.org/x/crypto/sha3"
+	"bytes"
 	"homophone/compressedinteger"
-	"homophone/filehelper"
 	"homophone/integritycheckedfile"
-	"homophone/keygenerator"
 	"homophone/randomlist"
-	"io"
+	"homophone/slicehelper"
 )
 
 // Save saves substitution data to a substitution file.
-func (s *Substitutor) Save(filePath string) error {
-	w, err := integritycheckedfile.NewWriter(
+// The file is only replaced when all data have been written successfully.
+// If overwrite is false, an existing file is not overwritten.
+func (s *Substitutor) Save(filePath string, overwrite bool) error {
+	hashFunc, err := s.hashAlgorithm.HashFunc()
+	if err != nil {
+		return err
+	}
+
+	var w *integritycheckedfile.Writer
+	w, err = integritycheckedfile.NewWriter(
 		filePath,
-		sha3.New256,
-		keygenerator.GenerateKey(generator, salt),
+		substitutionFilePermissions,
+		overwrite,
+		hashFunc,
+		integrityKey(),
 		additionalData)
 	if err != nil {
 		return err
 	}
-	defer filehelper.CloseWithName(w)
 
+	err = s.saveToWriter(w)
+	if err != nil {
+		_ = w.Abort()
+		return err
+	}
+
+	return w.Close()
+}
+
+// ******** Private type functions ********
+
+// saveToWriter writes the substitution data to an integrity-checked writer.
+func (s *Substitutor) saveToWriter(w *integritycheckedfile.Writer) error {
 	// Write magic bytes.
-	_, err = w.Write(fileMagic)
+	_, err := w.Write(fileMagic)
 	if err != nil {
 		return err
 	}
 
-	// Write version.
-	_, err = w.Write([]byte{actVersion})
+	// Write version and hash algorithm.
+	version := s.fileVersion()
+
+	_, err = w.Write([]byte{version, byte(s.hashAlgorithm)})
 	if err != nil {
 		return err
 	}
 
+	// Collect the substitution data, so they can be written in one piece and wiped afterward.
+	var buffer bytes.Buffer
+	buffer.Grow(substitutionDataLength)
+	defer func() { slicehelper.ClearNumber(buffer.Bytes()) }()
+
+	cw := compressedinteger.NewWriter(&buffer)
+
 	// Write size of substitution alphabet.
-	var size []byte
-	size, err = compressedinteger.FromUInt32(uint32(s.substitutionAlphabetSize))
+	err = cw.WriteUInt32(uint32(s.substitutionAlphabetSize))
 	if err != nil {
 		return err
 	}
-	_, err = w.Write(size)
+
+	// Save substitution lists.
+	err = saveSubstitutions(cw, s.substitutions)
 	if err != nil {
 		return err
 	}
 
-	// Save substitution lists.
-	err = saveSubstitutions(w, s.substitutions, size)
+	switch version {
+	case contextVersion:
+		// Save context-dependent substitution lists.
+		err = saveContexts(cw, s.contextClasses, s.contextSubstitutions)
+
+	case attributeVersion:
+		// Save the attributes that are needed to rebuild the selection.
+		err = s.saveAttributes(cw)
+	}
+	if err != nil {
+		return err
+	}
+
+	_, err = w.Write(buffer.Bytes())
 
 	return err
 }
 
-// saveSubstitutions saves the substitution lists.
-func saveSubstitutions(w io.Writer, substitutions []*randomlist.RandomList[byte], size []byte) error {
+// fileVersion returns the version of the file format the substitutor is saved with.
+// Keys of the baseline modes and complete keys without contexts whose source is known are saved with attributes.
+func (s *Substitutor) fileVersion() byte {
+	switch {
+	case s.contextClasses != nil:
+		return contextVersion
+
+	case s.mode != Homophonic:
+		return attributeVersion
+
+	case s.IsPartial():
+		return partialVersion
+
+	case s.letterCounts != nil:
+		return attributeVersion
+
+	default:
+		return actVersion
+	}
+}
+
+// saveAttributes saves the attribute section.
+// The mode, the selection strategy and the weighted flag are only written, if they differ from the default.
+func (s *Substitutor) saveAttributes(w *compressedinteger.Writer) error {
 	var err error
 
-	// Save all substitution lists.
-	for _, substitutionList := range substitutions {
-		// Write length of substitution list.
-		size, err = compressedinteger.FromUInt32(uint32(substitutionList.Len()))
+	if s.mode != Homophonic {
+		err = writeAttribute(w, modeAttribute, uint32(s.mode))
 		if err != nil {
 			return err
 		}
+	}
 
-		_, err = w.Write(size)
+	if s.selection != randomlist.DefaultStrategy {
+		err = writeAttribute(w, strategyAttribute, uint32(s.selection))
+		if err != nil {
+			return err
+		}
+	}
+
+	if s.weighted {
+		err = w.WriteUInt32(weightedAttribute)
+		if err != nil {
+			return err
+		}
+	}
+
+	if s.letterCounts == nil {
+		return nil
+	}
+
+	err = w.WriteUInt32(letterCountsAttribute)
+	if err != nil {
+		return err
+	}
+
+	for _, count := range s.letterCounts {
+		err = w.WriteUInt64(uint64(count))
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ******** Private functions ********
+
+// writeAttribute writes an attribute with a single value.
+func writeAttribute(w *compressedinteger.Writer, attribute uint32, value uint32) error {
+	err := w.WriteUInt32(attribute)
+	if err != nil {
+		return err
+	}
+
+	return w.WriteUInt32(value)
+}
+
+// saveSubstitutions saves the substitution lists.
+func saveSubstitutions(w *compressedinteger.Writer, substitutions []randomlist.Selector[byte]) error {
+	var err error
+
+	// Save all substitution lists.
+	for _, substitutionList := range substitutions {
+		// Write length of substitution list.
+		err = w.WriteUInt32(uint32(substitutionList.Len()))
 		if err != nil {
 			return err
 		}
 
 		// Write each substitution character.
 		for _, r := range substitutionList.BaseList() {
-			size, err = compressedinteger.FromUInt32(uint32(r))
+			err = w.WriteUInt32(uint32(r))
 			if err != nil {
 				return err
 			}
+		}
+	}
 
-			_, err = w.Write(size)
-			if err != nil {
-				return err
-			}
+	return nil
+}
+
+// saveContexts saves the context section, i.e. the number of predecessor classes, the class
+// of each character and the substitution lists of each class.
+func saveContexts(w *compressedinteger.Writer, classes []byte, contextSubstitutions [][]randomlist.Selector[byte]) error {
+	err := w.WriteUInt32(uint32(len(contextSubstitutions)))
+	if err != nil {
+		return err
+	}
+
+	for _, class := range classes {
+		err = w.WriteUInt32(uint32(class))
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, substitutions := range contextSubstitutions {
+		err = saveSubstitutions(w, substitutions)
+		if err != nil {
+			return err
 		}
 	}
 
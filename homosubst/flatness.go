@@ -0,0 +1,78 @@
+//
+// SPDX-FileCopyrightText: Copyright 2026 Frank Schwab
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileType: SOURCE
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+//
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Author: Frank Schwab
+//
+// Version: 1.1.0
+//
+// Change history:
+//    2026-10-18: V1.0.0: Created.
+//    2026-10-18: V1.1.0: Use the flatness of the analysis package.
+//
+
+package homosubst
+
+import (
+	"errors"
+	"homophone/analysis"
+)
+
+// ******** Public constants ********
+
+// ErrNoFrequencies is returned when the character frequencies of a substitutor are not known,
+// i.e. when it has been loaded from a file without the letter counts of its source.
+// This is always the case for a substitutor with predecessor classes that has been loaded from a file.
+var ErrNoFrequencies = errors.New(`character frequencies are not known`)
+
+// ******** Public type functions ********
+
+// Flatness returns the expected flatness of the frequencies of the substitution characters
+// in the encrypted text of the source the substitutor has been created from.
+func (s *Substitutor) Flatness() (analysis.Flatness, error) {
+	if s.letterShares == nil {
+		return analysis.Flatness{}, ErrNoFrequencies
+	}
+
+	return analysis.CalculateFlatness(s.SymbolFrequencies(), substitutionAlphabet), nil
+}
+
+// SymbolFrequencies returns the expected frequency of each substitution character, indexed by the character.
+// With contexts, the first character of the source, which has no predecessor, is neglected.
+// It returns nil, if the character frequencies are not known.
+func (s *Substitutor) SymbolFrequencies() map[byte]float64 {
+	if s.letterShares == nil {
+		return nil
+	}
+
+	result := make(map[byte]float64, s.substitutionAlphabetSize)
+	if s.contextSubstitutions != nil {
+		s.contextSymbolFrequencies(result)
+		return result
+	}
+
+	for i, list := range s.substitutions {
+		share := s.letterShares[i]
+		for j, p := range list.Probabilities() {
+			result[list.BaseList()[j]] = share * p
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,176 @@
+//
+// SPDX-FileCopyrightText: Copyright 2026 Frank Schwab
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileType: SOURCE
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+//
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Author: Frank Schwab
+//
+// Version: 1.0.0
+//
+// Change history:
+//    2026-10-18: V1.0.0: Created.
+//
+
+package homosubst
+
+import (
+	"errors"
+	"homophone/analysis"
+	"homophone/randomlist"
+	"math"
+	"testing"
+)
+
+// ******** Private constants ********
+
+const flatnessTestText = `Shall I compare thee to a summer's day? Thou art more lovely and more temperate.
+Rough winds do shake the darling buds of May, and summer's lease hath all too short a date.`
+
+// ******** Test functions ********
+
+func TestQuotaWeights(t *testing.T) {
+	tests := []struct {
+		quota    float64
+		count    int
+		expected []float64
+	}{
+		{2.5, 3, []float64{1, 1, 0.5}},
+		{2, 3, []float64{1, 0.5, 0.5}},
+		{0.25, 2, []float64{0.125, 0.125}},
+		{3.5, 3, []float64{1, 1, 1}},
+		{0, 2, []float64{1, 1}},
+		{0.4, 1, []float64{0.4}},
+	}
+
+	for _, tc := range tests {
+		weights := quotaWeights(tc.quota, tc.count)
+		for j, w := range weights {
+			if math.Abs(w-tc.expected[j]) > 1e-12 {
+				t.Errorf(`Quota %g with %d substitutions: Got weights %v instead of %v`, tc.quota, tc.count, weights, tc.expected)
+				break
+			}
+		}
+	}
+}
+
+func TestFlatnessWeighted(t *testing.T) {
+	for _, weighted := range []bool{false, true} {
+		s := newTestSubstitutorWithOptions(t, flatnessTestText, Options{Weighted: weighted})
+
+		frequencies := s.SymbolFrequencies()
+		sum := 0.0
+		for _, f := range frequencies {
+			sum += f
+		}
+		if math.Abs(sum-1) > 1e-9 {
+			t.Fatalf(`Weighted %t: Sum of the expected frequencies is %g instead of 1`, weighted, sum)
+		}
+
+		flatness, err := s.Flatness()
+		if err != nil {
+			t.Fatalf(`Weighted %t: Error calculating flatness: %v`, weighted, err)
+		}
+
+		if flatness.Count != int(s.substitutionAlphabetSize) || flatness.Ideal != 1/float64(flatness.Count) {
+			t.Fatalf(`Weighted %t: Wrong ideal flatness %+v`, weighted, flatness)
+		}
+
+		if flatness.Min > flatness.Ideal || flatness.Max < flatness.Ideal {
+			t.Fatalf(`Weighted %t: Ideal frequency is not between minimum and maximum: %+v`, weighted, flatness)
+		}
+
+		// Each substitution with a weight of 1 must occur with exactly the ideal frequency.
+		expectedIdealCount := 0
+		if weighted {
+			for _, list := range s.substitutions {
+				p := list.Probabilities()
+				for _, pj := range p {
+					if pj > p[len(p)-1]*(1+1e-9) {
+						expectedIdealCount++
+					}
+				}
+			}
+		}
+
+		if flatness.IdealCount < expectedIdealCount {
+			t.Fatalf(`Weighted %t: %d substitutions have the ideal frequency instead of at least %d`, weighted, flatness.IdealCount, expectedIdealCount)
+		}
+
+		if weighted && flatness.IdealCount == 0 {
+			t.Fatal(`No substitution has the ideal frequency`)
+		}
+	}
+}
+
+func TestFlatnessLoaded(t *testing.T) {
+	for _, options := range []Options{{}, {Weighted: true}, {Selection: randomlist.Window}} {
+		s := newTestSubstitutorWithOptions(t, flatnessTestText, options)
+		expected, err := s.Flatness()
+		if err != nil {
+			t.Fatalf(`%+v: Error calculating flatness: %v`, options, err)
+		}
+
+		var data []byte
+		data, err = s.MarshalBinary()
+		if err != nil {
+			t.Fatalf(`%+v: Error marshalling substitutor: %v`, options, err)
+		}
+
+		loaded := &Substitutor{}
+		err = loaded.UnmarshalBinary(data)
+		if err != nil {
+			t.Fatalf(`%+v: Error unmarshalling substitutor: %v`, options, err)
+		}
+
+		if loaded.selection != options.Selection || loaded.weighted != options.Weighted {
+			t.Fatalf(`%+v: Loaded selection is '%s' and weighted is %t`, options, loaded.selection, loaded.weighted)
+		}
+
+		var got analysis.Flatness
+		got, err = loaded.Flatness()
+		if err != nil {
+			t.Fatalf(`%+v: Error calculating flatness of loaded substitutor: %v`, options, err)
+		}
+
+		if got != expected {
+			t.Fatalf("%+v: Flatness of loaded substitutor differs\nexpected: %+v\ngot:      %+v", options, expected, got)
+		}
+	}
+}
+
+func TestFlatnessUnknown(t *testing.T) {
+	s := newTestSubstitutor(t)
+
+	// A key without attributes does not contain the letter counts.
+	s.letterCounts = nil
+	data, err := s.MarshalBinary()
+	if err != nil {
+		t.Fatalf(`Error marshalling substitutor: %v`, err)
+	}
+
+	loaded := &Substitutor{}
+	err = loaded.UnmarshalBinary(data)
+	if err != nil {
+		t.Fatalf(`Error unmarshalling substitutor: %v`, err)
+	}
+
+	_, err = loaded.Flatness()
+	if !errors.Is(err, ErrNoFrequencies) {
+		t.Fatalf(`Got error %v instead of %v`, err, ErrNoFrequencies)
+	}
+}
@@ -0,0 +1,599 @@
+//
+// SPDX-FileCopyrightText: Copyright 2026 Frank Schwab
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileType: SOURCE
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+//
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Author: Frank Schwab
+//
+// Version: 1.5.0
+//
+// Change history:
+//    2026-10-18: V1.0.0: Created.
+//    2026-10-18: V1.1.0: Add golden file with attribute section.
+//    2026-10-18: V1.2.0: Add golden file with context section.
+//    2026-10-18: V1.3.0: Use the common integrity key and character counting.
+//    2026-10-18: V1.4.0: Add golden file with partial key.
+//    2026-10-18: V1.5.0: Add golden files of the baseline modes.
+//
+
+package homosubst
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"homophone/compressedinteger"
+	"homophone/integritycheckedfile"
+	"homophone/randomlist"
+	"io/fs"
+	"math/rand/v2"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// The golden files guard the layout of substitution files. They must never change.
+// Golden files for new formats can be written with "go test -run Golden -update".
+// Existing golden files are never rewritten, so a format change can not silently update them.
+//
+// The golden files are created by [NewSubstitutorWithOptions] from the baseline clear text with a
+// random source that has a fixed seed. All homophonic golden files have the same substitutions,
+// which are listed in the golden key file.
+//
+// The baseline files have been written by the first version of this program (commit 45c97e3) with
+// "homophone encrypt -in clear.txt -out encrypted.txt -key baseline.subst -keep".
+// They guard that key files of this version can still be read.
+
+// ******** Private constants ********
+
+// goldenDir is the directory of the golden files.
+const goldenDir = `testdata/golden`
+
+// goldenKeyFileName is the name of the file with the substitutions of the golden files.
+const goldenKeyFileName = `key.txt`
+
+// baselineDir is the directory of the files that have been written by the first version of this program.
+const baselineDir = `testdata/baseline`
+
+// goldenSourceFileName is the name of the clear text that the golden substitutions are created from.
+var goldenSourceFileName = filepath.Join(baselineDir, `clear.txt`)
+
+// goldenSeed1 and goldenSeed2 are the seed of the random generator that generates the golden substitutions.
+const goldenSeed1 = 0x486f6d6f70686f6e
+const goldenSeed2 = 0x65476f6c64656e21
+
+// goldenContextClassCount is the number of predecessor classes of the golden file with contexts.
+const goldenContextClassCount = 3
+
+// goldenPartialLetters are the letters that have substitutions in the golden file with a partial key.
+const goldenPartialLetters = `AEINOST`
+
+// goldenShift is the shift of the golden file in the Caesar mode.
+const goldenShift = 3
+
+// ******** Private types ********
+
+// goldenFile describes a golden file and how it is created and checked.
+type goldenFile struct {
+	name    string
+	version byte
+
+	// create creates the substitutor of the golden file from the seeded random source.
+	create func(t *testing.T) *Substitutor
+
+	// marshal returns the content of the golden file. If it is nil, [Substitutor.MarshalBinary] is used.
+	marshal func(s *Substitutor) ([]byte, error)
+
+	// check checks the loaded substitutor against the golden key.
+	check func(t *testing.T, key *Substitutor, s *Substitutor)
+}
+
+// ******** Private variables ********
+
+// updateGolden indicates that the golden files are to be rewritten.
+var updateGolden = flag.Bool(`update`, false, `rewrite the golden files`)
+
+// ******** Test functions ********
+
+func TestGoldenFiles(t *testing.T) {
+	if *updateGolden {
+		writeGoldenFiles(t)
+	}
+
+	key := readGoldenKey(t)
+	defaultData := readGoldenFile(t, filepath.Join(goldenDir, defaultHashAlgorithm.String()+`.subst`))
+
+	for _, golden := range goldenFiles() {
+		data := readGoldenFile(t, filepath.Join(goldenDir, golden.name))
+		if data[len(fileMagic)] != golden.version {
+			t.Fatalf(`'%s': Golden file has version %d instead of %d`, golden.name, data[len(fileMagic)], golden.version)
+		}
+
+		s := loadGoldenFile(t, golden.name)
+		golden.check(t, key, s)
+
+		// Files of the first version are saved in the actual version with the default hash algorithm.
+		expected := data
+		if golden.version == versionWithoutHashAlgorithm {
+			expected = defaultData
+		}
+
+		savedFileName := filepath.Join(t.TempDir(), golden.name)
+		err := s.Save(savedFileName, false)
+		if err != nil {
+			t.Fatalf(`'%s': Error saving: %v`, golden.name, err)
+		}
+
+		got := readGoldenFile(t, savedFileName)
+		if !bytes.Equal(expected, got) {
+			t.Fatalf("'%s': Saved file differs from golden file\nexpected: %x\ngot:      %x", golden.name, expected, got)
+		}
+	}
+}
+
+func TestBaselineFile(t *testing.T) {
+	data := readGoldenFile(t, filepath.Join(baselineDir, `baseline.subst`))
+	if data[len(fileMagic)] != versionWithoutHashAlgorithm {
+		t.Fatalf(`Baseline file has version %d instead of %d`, data[len(fileMagic)], versionWithoutHashAlgorithm)
+	}
+
+	dir := t.TempDir()
+	keyFileName := filepath.Join(dir, `baseline.subst`)
+	err := os.WriteFile(keyFileName, data, substitutionFilePermissions)
+	if err != nil {
+		t.Fatalf(`Error copying baseline file: %v`, err)
+	}
+
+	var s *Substitutor
+	s, err = NewFromFile(keyFileName)
+	if err != nil {
+		t.Fatalf(`Error loading baseline file: %v`, err)
+	}
+
+	if s.HashAlgorithm() != defaultHashAlgorithm {
+		t.Fatalf(`Expected hash algorithm '%s', got '%s'`, defaultHashAlgorithm, s.HashAlgorithm())
+	}
+
+	decryptedFileName := filepath.Join(dir, `decrypted.txt`)
+	err = s.Decrypt(filepath.Join(baselineDir, `encrypted.txt`), decryptedFileName, false)
+	if err != nil {
+		t.Fatalf(`Error decrypting baseline file: %v`, err)
+	}
+
+	expected := strings.ToUpper(string(readGoldenFile(t, goldenSourceFileName)))
+	got := string(readGoldenFile(t, decryptedFileName))
+	if got != expected {
+		t.Fatalf("Decrypted baseline file differs\nexpected: %q\ngot:      %q", expected, got)
+	}
+
+	// The key is saved in the actual format and reads back the same.
+	savedFileName := filepath.Join(dir, `saved.subst`)
+	err = s.Save(savedFileName, false)
+	if err != nil {
+		t.Fatalf(`Error saving: %v`, err)
+	}
+
+	var saved *Substitutor
+	saved, err = NewFromFile(savedFileName)
+	if err != nil {
+		t.Fatalf(`Error loading saved file: %v`, err)
+	}
+
+	expectEqualSubstitutions(t, s, saved)
+}
+
+// ******** Private functions ********
+
+// goldenFiles returns the descriptions of all golden substitution files.
+func goldenFiles() []goldenFile {
+	result := []goldenFile{{
+		name:    `version0.subst`,
+		version: versionWithoutHashAlgorithm,
+		create: func(t *testing.T) *Substitutor {
+			return newGoldenKey(t, defaultHashAlgorithm)
+		},
+		marshal: marshalVersion0,
+		check:   checkGoldenKey,
+	}}
+
+	for _, name := range integritycheckedfile.HashAlgorithmNames() {
+		hashAlgorithm, _ := integritycheckedfile.ParseHashAlgorithm(name)
+		result = append(result, goldenFile{
+			name:    name + `.subst`,
+			version: actVersion,
+			create: func(t *testing.T) *Substitutor {
+				return newGoldenKey(t, hashAlgorithm)
+			},
+			check: func(t *testing.T, key *Substitutor, s *Substitutor) {
+				t.Helper()
+
+				checkGoldenKey(t, key, s)
+				if s.HashAlgorithm() != hashAlgorithm {
+					t.Fatalf(`'%s': Expected hash algorithm '%s', got '%s'`, name, hashAlgorithm, s.HashAlgorithm())
+				}
+			},
+		})
+	}
+
+	return append(result,
+		goldenFile{
+			name:    `attributes.subst`,
+			version: attributeVersion,
+			create: func(t *testing.T) *Substitutor {
+				return newGoldenSubstitutor(t, Options{Selection: randomlist.Window, Weighted: true})
+			},
+			check: checkGoldenAttributes,
+		},
+		goldenFile{
+			name:    `context.subst`,
+			version: contextVersion,
+			create: func(t *testing.T) *Substitutor {
+				return newGoldenSubstitutor(t, Options{ContextClassCount: goldenContextClassCount})
+			},
+			check: checkGoldenContexts,
+		},
+		goldenFile{
+			name:    `partial.subst`,
+			version: partialVersion,
+			create:  newGoldenPartial,
+			check:   checkGoldenPartial,
+		},
+		goldenFile{
+			name:    `monoalphabetic.subst`,
+			version: attributeVersion,
+			create: func(t *testing.T) *Substitutor {
+				return newGoldenSubstitutor(t, Options{Mode: Monoalphabetic})
+			},
+			check: checkGoldenMonoalphabetic,
+		},
+		goldenFile{
+			name:    `caesar.subst`,
+			version: attributeVersion,
+			create: func(t *testing.T) *Substitutor {
+				return newGoldenSubstitutor(t, Options{Mode: Caesar, Shift: goldenShift})
+			},
+			check: checkGoldenCaesar,
+		},
+	)
+}
+
+// newGoldenSubstitutor creates a substitutor from the golden clear text with a random source that has the golden seed.
+func newGoldenSubstitutor(t *testing.T, options Options) *Substitutor {
+	t.Helper()
+
+	rng := rand.New(rand.NewPCG(goldenSeed1, goldenSeed2))
+	randomIntN = rng.IntN
+	defer func() { randomIntN = rand.IntN }()
+
+	s, err := NewSubstitutorWithOptions(goldenSourceFileName, options)
+	if err != nil {
+		t.Fatalf(`Error creating golden substitutor: %v`, err)
+	}
+
+	return s
+}
+
+// newGoldenKey creates the golden substitutor with the default options and a hash algorithm.
+// The letter counts are removed, so the substitutor is saved like a key whose source is not known.
+func newGoldenKey(t *testing.T, hashAlgorithm integritycheckedfile.HashAlgorithm) *Substitutor {
+	t.Helper()
+
+	s := newGoldenSubstitutor(t, Options{})
+	s.letterCounts = nil
+
+	err := s.SetHashAlgorithm(hashAlgorithm)
+	if err != nil {
+		t.Fatalf(`Error setting hash algorithm '%s': %v`, hashAlgorithm, err)
+	}
+
+	return s
+}
+
+// newGoldenPartial creates a partial key with the golden substitutions of the golden partial letters.
+func newGoldenPartial(t *testing.T) *Substitutor {
+	t.Helper()
+
+	key := newGoldenSubstitutor(t, Options{})
+
+	homophones := make([][]byte, sourceAlphabetSize)
+	for _, letter := range []byte(goldenPartialLetters) {
+		homophones[letter-'A'] = key.Homophones(letter)
+	}
+
+	s, err := NewPartial(homophones)
+	if err != nil {
+		t.Fatalf(`Error creating golden partial key: %v`, err)
+	}
+
+	return s
+}
+
+// marshalVersion0 returns the substitution data in the layout of the first version, which has no hash algorithm.
+func marshalVersion0(s *Substitutor) ([]byte, error) {
+	hashFunc, err := defaultHashAlgorithm.HashFunc()
+	if err != nil {
+		return nil, err
+	}
+
+	var buffer bytes.Buffer
+	w := integritycheckedfile.NewWriterFromWriter(&buffer, hashFunc, integrityKey(), additionalData)
+
+	_, err = w.Write(append(slices.Clone(fileMagic), versionWithoutHashAlgorithm))
+	if err != nil {
+		return nil, err
+	}
+
+	var data bytes.Buffer
+	cw := compressedinteger.NewWriter(&data)
+	err = cw.WriteUInt32(uint32(s.substitutionAlphabetSize))
+	if err != nil {
+		return nil, err
+	}
+
+	err = saveSubstitutions(cw, s.substitutions)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = w.Write(data.Bytes())
+	if err != nil {
+		return nil, err
+	}
+
+	err = w.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	return buffer.Bytes(), nil
+}
+
+// checkGoldenKey checks that a loaded substitutor has the golden substitutions.
+func checkGoldenKey(t *testing.T, key *Substitutor, s *Substitutor) {
+	t.Helper()
+
+	expectEqualSubstitutions(t, key, s)
+
+	if s.HasContexts() || s.Mode() != Homophonic || s.letterCounts != nil {
+		t.Fatalf(`Substitutor has contexts %t, mode '%s' and letter counts %v`, s.HasContexts(), s.Mode(), s.letterCounts)
+	}
+}
+
+// checkGoldenAttributes checks the golden file with an attribute section.
+func checkGoldenAttributes(t *testing.T, key *Substitutor, s *Substitutor) {
+	t.Helper()
+
+	expectEqualSubstitutions(t, key, s)
+
+	letterCounts := goldenLetterCounts(t)
+	if s.selection != randomlist.Window || !s.weighted || !slices.Equal(s.letterCounts, letterCounts) {
+		t.Fatalf(`Wrong attributes: selection '%s', weighted %t, letter counts %v`, s.selection, s.weighted, s.letterCounts)
+	}
+
+	if _, isWeighted := s.substitutions[0].(*randomlist.WeightedList[byte]); !isWeighted {
+		t.Fatal(`Substitutions are not weighted`)
+	}
+}
+
+// checkGoldenContexts checks the golden file with a context section.
+func checkGoldenContexts(t *testing.T, key *Substitutor, s *Substitutor) {
+	t.Helper()
+
+	expectEqualSubstitutions(t, key, s)
+
+	if len(s.contextSubstitutions) != goldenContextClassCount || len(s.contextClasses) != int(sourceAlphabetSize) {
+		t.Fatalf(`Expected %d predecessor classes, got %d with classes %v`, goldenContextClassCount, len(s.contextSubstitutions), s.contextClasses)
+	}
+
+	for class, substitutions := range s.contextSubstitutions {
+		total := 0
+		for _, list := range substitutions {
+			total += list.Len()
+		}
+
+		if total != int(s.substitutionAlphabetSize) {
+			t.Fatalf(`Predecessor class %d has %d substitutions instead of %d`, class, total, s.substitutionAlphabetSize)
+		}
+	}
+}
+
+// checkGoldenPartial checks the golden file with a partial key.
+func checkGoldenPartial(t *testing.T, key *Substitutor, s *Substitutor) {
+	t.Helper()
+
+	if !s.IsPartial() {
+		t.Fatal(`Partial key is not partial`)
+	}
+
+	for i, list := range s.substitutions {
+		var expected []byte
+		if strings.IndexByte(goldenPartialLetters, byte(i+'A')) >= 0 {
+			expected = key.substitutions[i].BaseList()
+		}
+
+		if !slices.Equal(list.BaseList(), expected) {
+			t.Fatalf(`Substitutions for '%c' differ: expected '%s', got '%s'`, i+'A', expected, list.BaseList())
+		}
+	}
+}
+
+// checkGoldenMonoalphabetic checks the golden file in the monoalphabetic mode.
+func checkGoldenMonoalphabetic(t *testing.T, _ *Substitutor, s *Substitutor) {
+	t.Helper()
+
+	if s.Mode() != Monoalphabetic {
+		t.Fatalf(`Expected mode '%s', got '%s'`, Monoalphabetic, s.Mode())
+	}
+
+	used := make(map[byte]bool)
+	for i, list := range s.substitutions {
+		if list.Len() != 1 || used[list.BaseList()[0]] {
+			t.Fatalf(`Substitutions for '%c' are not monoalphabetic: '%s'`, i+'A', list.BaseList())
+		}
+		used[list.BaseList()[0]] = true
+	}
+}
+
+// checkGoldenCaesar checks the golden file in the Caesar mode.
+func checkGoldenCaesar(t *testing.T, _ *Substitutor, s *Substitutor) {
+	t.Helper()
+
+	if s.Mode() != Caesar {
+		t.Fatalf(`Expected mode '%s', got '%s'`, Caesar, s.Mode())
+	}
+
+	for i, list := range s.substitutions {
+		expected := []byte{byte('A' + (i+goldenShift)%int(sourceAlphabetSize))}
+		if !slices.Equal(list.BaseList(), expected) {
+			t.Fatalf(`Substitutions for '%c' differ: expected '%s', got '%s'`, i+'A', expected, list.BaseList())
+		}
+	}
+}
+
+// goldenLetterCounts returns the letter counts of the golden clear text.
+func goldenLetterCounts(t *testing.T) []uint {
+	t.Helper()
+
+	letterCounts, _, _, err := countCharacters(bytes.NewReader(readGoldenFile(t, goldenSourceFileName)))
+	if err != nil {
+		t.Fatalf(`Error counting letters of golden clear text: %v`, err)
+	}
+
+	return letterCounts
+}
+
+// loadGoldenFile loads a golden file with [NewFromFile].
+// The file is copied to a file that is only readable by its owner, so there is no warning.
+func loadGoldenFile(t *testing.T, fileName string) *Substitutor {
+	t.Helper()
+
+	copyFileName := filepath.Join(t.TempDir(), fileName)
+	err := os.WriteFile(copyFileName, readGoldenFile(t, filepath.Join(goldenDir, fileName)), substitutionFilePermissions)
+	if err != nil {
+		t.Fatalf(`Error copying golden file '%s': %v`, fileName, err)
+	}
+
+	var s *Substitutor
+	s, err = NewFromFile(copyFileName)
+	if err != nil {
+		t.Fatalf(`Error loading golden file '%s': %v`, fileName, err)
+	}
+
+	return s
+}
+
+// readGoldenFile reads a file.
+func readGoldenFile(t *testing.T, filePath string) []byte {
+	t.Helper()
+
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		t.Fatalf(`Error reading '%s': %v`, filePath, err)
+	}
+
+	return data
+}
+
+// readGoldenKey reads the substitutions of the golden files.
+// Each line contains the source character, a colon and the substitution characters.
+func readGoldenKey(t *testing.T) *Substitutor {
+	t.Helper()
+
+	lines := strings.Split(strings.TrimSpace(string(readGoldenFile(t, filepath.Join(goldenDir, goldenKeyFileName)))), "\n")
+	if len(lines) != int(sourceAlphabetSize) {
+		t.Fatalf(`Golden key has %d lines instead of %d`, len(lines), sourceAlphabetSize)
+	}
+
+	result := &Substitutor{
+		substitutions:            make([]randomlist.Selector[byte], sourceAlphabetSize),
+		substitutionAlphabetSize: uint16(requiredSubstitutionAlphabetSize),
+	}
+	for i, line := range lines {
+		prefix := fmt.Sprintf(`%c:`, i+'A')
+		substitution, found := strings.CutPrefix(strings.TrimSpace(line), prefix)
+		if !found {
+			t.Fatalf(`Line %d of golden key does not start with '%s'`, i+1, prefix)
+		}
+
+		result.substitutions[i] = randomlist.New([]byte(strings.TrimSpace(substitution)))
+	}
+
+	return result
+}
+
+// writeGoldenFiles writes the missing golden files.
+func writeGoldenFiles(t *testing.T) {
+	t.Helper()
+
+	key := newGoldenSubstitutor(t, Options{})
+
+	var keyText strings.Builder
+	for i, list := range key.substitutions {
+		_, _ = fmt.Fprintf(&keyText, "%c: %s\n", i+'A', list.BaseList())
+	}
+
+	writeGoldenFile(t, goldenKeyFileName, []byte(keyText.String()))
+
+	for _, golden := range goldenFiles() {
+		s := golden.create(t)
+
+		marshal := golden.marshal
+		if marshal == nil {
+			marshal = (*Substitutor).MarshalBinary
+		}
+
+		data, err := marshal(s)
+		if err != nil {
+			t.Fatalf(`Error marshaling golden file '%s': %v`, golden.name, err)
+		}
+
+		writeGoldenFile(t, golden.name, data)
+	}
+}
+
+// writeGoldenFile writes a golden file, if it does not exist, yet.
+func writeGoldenFile(t *testing.T, fileName string, data []byte) {
+	t.Helper()
+
+	err := os.MkdirAll(goldenDir, 0755)
+	if err != nil {
+		t.Fatalf(`Error creating golden directory: %v`, err)
+	}
+
+	var f *os.File
+	f, err = os.OpenFile(filepath.Join(goldenDir, fileName), os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+	if errors.Is(err, fs.ErrExist) {
+		t.Logf(`Golden file '%s' exists and is not rewritten`, fileName)
+		return
+	}
+
+	if err == nil {
+		_, err = f.Write(data)
+		closeErr := f.Close()
+		if err == nil {
+			err = closeErr
+		}
+	}
+
+	if err != nil {
+		t.Fatalf(`Error writing golden file '%s': %v`, fileName, err)
+	}
+}
@@ -0,0 +1,50 @@
+//
+// SPDX-FileCopyrightText: Copyright 2026 Frank Schwab
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileType: SOURCE
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+//
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Author: Frank Schwab
+//
+// Version: 1.0.0
+//
+// Change history:
+//    2026-10-18: V1.0.0: Created.
+//
+
+package homosubst
+
+import "homophone/integritycheckedfile"
+
+// ******** Public type functions ********
+
+// HashAlgorithm returns the hash algorithm that is used for the integrity check of the substitution file.
+func (s *Substitutor) HashAlgorithm() integritycheckedfile.HashAlgorithm {
+	return s.hashAlgorithm
+}
+
+// SetHashAlgorithm sets the hash algorithm that is used for the integrity check of the substitution file.
+func (s *Substitutor) SetHashAlgorithm(hashAlgorithm integritycheckedfile.HashAlgorithm) error {
+	_, err := hashAlgorithm.HashFunc()
+	if err != nil {
+		return err
+	}
+
+	s.hashAlgorithm = hashAlgorithm
+
+	return nil
+}
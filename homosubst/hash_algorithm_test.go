@@ -0,0 +1,128 @@
+//
+// SPDX-FileCopyrightText: Copyright 2026 Frank Schwab
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileType: SOURCE
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+//
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Author: Frank Schwab
+//
+// Version: 1.0.0
+//
+// Change history:
+//    2026-10-18: V1.0.0: Created.
+//
+
+package homosubst
+
+import (
+	"bytes"
+	"homophone/integritycheckedfile"
+	"testing"
+)
+
+// ******** Test functions ********
+
+func TestHashAlgorithmRoundTrip(t *testing.T) {
+	s := newTestSubstitutor(t)
+
+	for _, name := range integritycheckedfile.HashAlgorithmNames() {
+		hashAlgorithm, err := integritycheckedfile.ParseHashAlgorithm(name)
+		if err != nil {
+			t.Fatalf(`Error parsing hash algorithm '%s': %v`, name, err)
+		}
+
+		err = s.SetHashAlgorithm(hashAlgorithm)
+		if err != nil {
+			t.Fatalf(`Error setting hash algorithm '%s': %v`, name, err)
+		}
+
+		var data []byte
+		data, err = s.MarshalBinary()
+		if err != nil {
+			t.Fatalf(`Error marshaling with hash algorithm '%s': %v`, name, err)
+		}
+
+		var u Substitutor
+		err = u.UnmarshalBinary(data)
+		if err != nil {
+			t.Fatalf(`Error unmarshaling with hash algorithm '%s': %v`, name, err)
+		}
+
+		if u.HashAlgorithm() != hashAlgorithm {
+			t.Fatalf(`Expected hash algorithm '%s', got '%s'`, hashAlgorithm, u.HashAlgorithm())
+		}
+
+		expectEqualSubstitutions(t, s, &u)
+	}
+}
+
+func TestLoadVersionWithoutHashAlgorithm(t *testing.T) {
+	s := newTestSubstitutor(t)
+
+	// The format without hash algorithm has no attribute section.
+	s.letterCounts = nil
+
+	data, err := s.MarshalBinary()
+	if err != nil {
+		t.Fatalf(`Error marshaling: %v`, err)
+	}
+
+	// Rebuild the data in the format without hash algorithm.
+	hashFunc, _ := defaultHashAlgorithm.HashFunc()
+	hashSize := hashFunc().Size()
+	payload := data[len(fileMagic)+2 : len(data)-hashSize]
+
+	var buffer bytes.Buffer
+	w := integritycheckedfile.NewWriterFromWriter(&buffer, hashFunc, integrityKey(), additionalData)
+	_, _ = w.Write(fileMagic)
+	_, _ = w.Write([]byte{versionWithoutHashAlgorithm})
+	_, _ = w.Write(payload)
+	err = w.Close()
+	if err != nil {
+		t.Fatalf(`Error writing old format: %v`, err)
+	}
+
+	var u Substitutor
+	err = u.UnmarshalBinary(buffer.Bytes())
+	if err != nil {
+		t.Fatalf(`Error unmarshaling old format: %v`, err)
+	}
+
+	if u.HashAlgorithm() != defaultHashAlgorithm {
+		t.Fatalf(`Expected hash algorithm '%s', got '%s'`, defaultHashAlgorithm, u.HashAlgorithm())
+	}
+
+	expectEqualSubstitutions(t, s, &u)
+}
+
+func TestUnknownHashAlgorithm(t *testing.T) {
+	s := newTestSubstitutor(t)
+
+	err := s.SetHashAlgorithm(integritycheckedfile.HashAlgorithm(0))
+	if err == nil {
+		t.Fatal(`Setting an unknown hash algorithm did not fail`)
+	}
+
+	data, _ := s.MarshalBinary()
+	data[len(fileMagic)+1] = 0xff
+
+	var u Substitutor
+	err = u.UnmarshalBinary(data)
+	if err == nil {
+		t.Fatal(`Loading an unknown hash algorithm did not fail`)
+	}
+}
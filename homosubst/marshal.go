@@ -0,0 +1,137 @@
+//
+// SPDX-FileCopyrightText: Copyright 2026 Frank Schwab
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileType: SOURCE
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+//
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Author: Frank Schwab
+//
+// Version: 1.2.1
+//
+// Change history:
+//    2026-10-18: V1.0.0: Created.
+//    2026-10-18: V1.1.0: Use hash algorithm from header.
+//    2026-10-18: V1.2.0: Pass file version.
+//    2026-10-18: V1.2.1: Use the integrity key that is generated only once.
+//
+
+package homosubst
+
+import (
+	"bytes"
+	"encoding/base64"
+	"hash"
+	"homophone/integritycheckedfile"
+)
+
+// ******** Public type functions ********
+
+// MarshalBinary returns the substitution data in the same integrity-checked layout
+// that is used for substitution files.
+// It implements the [encoding.BinaryMarshaler] interface.
+func (s *Substitutor) MarshalBinary() ([]byte, error) {
+	hashFunc, err := s.hashAlgorithm.HashFunc()
+	if err != nil {
+		return nil, err
+	}
+
+	var buffer bytes.Buffer
+
+	w := integritycheckedfile.NewWriterFromWriter(
+		&buffer,
+		hashFunc,
+		integrityKey(),
+		additionalData)
+
+	err = s.saveToWriter(w)
+	if err != nil {
+		return nil, err
+	}
+
+	err = w.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	return buffer.Bytes(), nil
+}
+
+// UnmarshalBinary replaces the substitution data with the data that have been
+// created by [Substitutor.MarshalBinary] or read from a substitution file.
+// It implements the [encoding.BinaryUnmarshaler] interface.
+func (s *Substitutor) UnmarshalBinary(data []byte) error {
+	source := bytes.NewReader(data)
+
+	headerLen, version, hashAlgorithm, err := checkHeaderFromReader(source)
+	if err != nil {
+		return err
+	}
+
+	var hashFunc func() hash.Hash
+	hashFunc, err = hashAlgorithm.HashFunc()
+	if err != nil {
+		return err
+	}
+
+	var r *integritycheckedfile.Reader
+	r, err = integritycheckedfile.NewReaderFromReadSeeker(
+		source,
+		hashFunc,
+		integrityKey(),
+		additionalData)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = r.Close() }()
+
+	var loaded *Substitutor
+	loaded, err = loadFromReader(r, headerLen, version, hashAlgorithm)
+	if err != nil {
+		return err
+	}
+
+	*s = *loaded
+
+	return nil
+}
+
+// MarshalText returns the binary substitution data encoded in standard base64.
+// It implements the [encoding.TextMarshaler] interface.
+func (s *Substitutor) MarshalText() ([]byte, error) {
+	data, err := s.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]byte, base64.StdEncoding.EncodedLen(len(data)))
+	base64.StdEncoding.Encode(result, data)
+
+	return result, nil
+}
+
+// UnmarshalText replaces the substitution data with the base64 encoded data that have been
+// created by [Substitutor.MarshalText].
+// It implements the [encoding.TextUnmarshaler] interface.
+func (s *Substitutor) UnmarshalText(text []byte) error {
+	data := make([]byte, base64.StdEncoding.DecodedLen(len(text)))
+	n, err := base64.StdEncoding.Decode(data, text)
+	if err != nil {
+		return err
+	}
+
+	return s.UnmarshalBinary(data[:n])
+}
@@ -0,0 +1,157 @@
+//
+// SPDX-FileCopyrightText: Copyright 2026 Frank Schwab
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileType: SOURCE
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+//
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Author: Frank Schwab
+//
+// Version: 1.0.0
+//
+// Change history:
+//    2026-10-18: V1.0.0: Created.
+//
+
+package homosubst
+
+import (
+	"bytes"
+	"errors"
+	"homophone/integritycheckedfile"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+// ******** Private constants ********
+
+const testClearText = `The quick brown fox jumps over the lazy dog. Pack my box with five dozen liquor jugs.`
+
+// ******** Test functions ********
+
+func TestMarshalBinaryMatchesSave(t *testing.T) {
+	s := newTestSubstitutor(t)
+
+	data, err := s.MarshalBinary()
+	if err != nil {
+		t.Fatalf(`Error marshaling: %v`, err)
+	}
+
+	substFileName := filepath.Join(t.TempDir(), `test.subst`)
+	err = s.Save(substFileName, false)
+	if err != nil {
+		t.Fatalf(`Error saving: %v`, err)
+	}
+
+	var fileData []byte
+	fileData, err = os.ReadFile(substFileName)
+	if err != nil {
+		t.Fatalf(`Error reading substitution file: %v`, err)
+	}
+
+	if !bytes.Equal(data, fileData) {
+		t.Fatalf(`Marshaled data differ from saved file`)
+	}
+}
+
+func TestMarshalBinaryRoundTrip(t *testing.T) {
+	s := newTestSubstitutor(t)
+
+	data, err := s.MarshalBinary()
+	if err != nil {
+		t.Fatalf(`Error marshaling: %v`, err)
+	}
+
+	var u Substitutor
+	err = u.UnmarshalBinary(data)
+	if err != nil {
+		t.Fatalf(`Error unmarshaling: %v`, err)
+	}
+
+	expectEqualSubstitutions(t, s, &u)
+}
+
+func TestMarshalTextRoundTrip(t *testing.T) {
+	s := newTestSubstitutor(t)
+
+	text, err := s.MarshalText()
+	if err != nil {
+		t.Fatalf(`Error marshaling: %v`, err)
+	}
+
+	var u Substitutor
+	err = u.UnmarshalText(text)
+	if err != nil {
+		t.Fatalf(`Error unmarshaling: %v`, err)
+	}
+
+	expectEqualSubstitutions(t, s, &u)
+}
+
+func TestUnmarshalBinaryCorrupt(t *testing.T) {
+	s := newTestSubstitutor(t)
+
+	data, err := s.MarshalBinary()
+	if err != nil {
+		t.Fatalf(`Error marshaling: %v`, err)
+	}
+
+	data[len(fileMagic)+3] ^= 1
+
+	var u Substitutor
+	err = u.UnmarshalBinary(data)
+	if !errors.Is(err, integritycheckedfile.ErrFileCorrupt) {
+		t.Fatalf(`Expected error '%v', got '%v'`, integritycheckedfile.ErrFileCorrupt, err)
+	}
+}
+
+// ******** Private functions ********
+
+// newTestSubstitutor creates a substitutor from the test clear text.
+func newTestSubstitutor(t *testing.T) *Substitutor {
+	t.Helper()
+
+	clearFileName := filepath.Join(t.TempDir(), `clear.txt`)
+	err := os.WriteFile(clearFileName, []byte(testClearText), 0600)
+	if err != nil {
+		t.Fatalf(`Error writing clear text file: %v`, err)
+	}
+
+	var s *Substitutor
+	s, err = NewSubstitutor(clearFileName)
+	if err != nil {
+		t.Fatalf(`Error creating substitutor: %v`, err)
+	}
+
+	return s
+}
+
+// expectEqualSubstitutions checks that two substitutors have the same substitutions.
+func expectEqualSubstitutions(t *testing.T, expected *Substitutor, got *Substitutor) {
+	t.Helper()
+
+	if expected.substitutionAlphabetSize != got.substitutionAlphabetSize {
+		t.Fatalf(`Expected substitution alphabet size %d, got %d`, expected.substitutionAlphabetSize, got.substitutionAlphabetSize)
+	}
+
+	for i, list := range expected.substitutions {
+		if !slices.Equal(list.BaseList(), got.substitutions[i].BaseList()) {
+			t.Fatalf(`Substitutions for '%c' differ: expected '%s', got '%s'`, i+'A', list.BaseList(), got.substitutions[i].BaseList())
+		}
+	}
+}
@@ -0,0 +1,189 @@
+//
+// SPDX-FileCopyrightText: Copyright 2026 Frank Schwab
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileType: SOURCE
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+//
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Author: Frank Schwab
+//
+// Version: 1.0.0
+//
+// Change history:
+//    2026-10-18: V1.0.0: Created.
+//
+
+package homosubst
+
+import (
+	"errors"
+	"fmt"
+	"homophone/nametable"
+	"homophone/randomlist"
+)
+
+// ******** Public types ********
+
+// Mode is the kind of substitution of a [Substitutor].
+type Mode byte
+
+// ******** Public constants ********
+
+// Known modes.
+const (
+	// Homophonic substitutes each letter by one of several substitution characters,
+	// whose number depends on the frequency of the letter.
+	Homophonic Mode = iota
+
+	// Monoalphabetic substitutes each letter by exactly one randomly chosen substitution character.
+	// It is a baseline that shows why homophones help.
+	Monoalphabetic
+
+	// Caesar substitutes each letter by the upper case letter that is a fixed number of letters later in the alphabet.
+	// It is a baseline that shows why homophones help.
+	Caesar
+)
+
+// ErrUnknownMode is returned when a mode is not known.
+var ErrUnknownMode = errors.New(`unknown mode`)
+
+// ErrModeOptions is returned when options that only apply to the [Homophonic] mode are used with another mode.
+var ErrModeOptions = errors.New(`option can only be used with mode 'homophonic'`)
+
+// ******** Private variables ********
+
+// modeNames contains the names of the modes in the order of their values.
+var modeNames = nametable.Table[Mode]{
+	{Value: Homophonic, Name: `homophonic`},
+	{Value: Monoalphabetic, Name: `mono`},
+	{Value: Caesar, Name: `caesar`},
+}
+
+// ******** Public functions ********
+
+// ParseMode returns the mode with the given name.
+func ParseMode(name string) (Mode, error) {
+	return modeNames.Parse(name, ErrUnknownMode)
+}
+
+// ModeNames returns the names of all known modes.
+func ModeNames() []string {
+	return modeNames.Names()
+}
+
+// ******** Public type functions ********
+
+// String returns the name of the mode.
+func (m Mode) String() string {
+	return modeNames.Name(m)
+}
+
+// Mode returns the mode of the substitutor.
+// It is stored in the key file, so it is also known for a substitutor that has been loaded from a file.
+func (s *Substitutor) Mode() Mode {
+	return s.mode
+}
+
+// ******** Private functions ********
+
+// checkModeOptions checks that only options that apply to the mode are set.
+func checkModeOptions(options Options) error {
+	if options.Mode == Homophonic {
+		return nil
+	}
+
+	var name string
+	switch {
+	case options.Apportioner != nil:
+		name = `apportioner`
+	case options.MinLengths != nil:
+		name = `minimum lengths`
+	case options.MaxLengths != nil:
+		name = `maximum lengths`
+	case options.Selection != randomlist.DefaultStrategy:
+		name = `selection strategy`
+	case options.Weighted:
+		name = `weighted selection`
+	case options.ContextClassCount != 0:
+		name = `predecessor classes`
+	default:
+		return nil
+	}
+
+	return fmt.Errorf(`%s: %w`, name, ErrModeOptions)
+}
+
+// checkBaselineSubstitutions checks that the substitution lists fit a baseline mode,
+// i.e. that each letter has exactly one substitution and that a Caesar key is a shifted alphabet.
+func checkBaselineSubstitutions(mode Mode, substitutions []randomlist.Selector[byte]) error {
+	size := int(sourceAlphabetSize)
+	shift := 0
+	for i, list := range substitutions {
+		if list.Len() != 1 {
+			return fmt.Errorf(`'%c' has %d substitutions in mode '%s'`, i+'A', list.Len(), mode)
+		}
+
+		if mode != Caesar {
+			continue
+		}
+
+		b := list.BaseList()[0]
+		letterShift := (int(b) - 'A' - i + size) % size
+		if i == 0 {
+			shift = letterShift
+		}
+
+		if b < 'A' || b > 'Z' || letterShift != shift {
+			return fmt.Errorf(`substitution '%c' of '%c' does not fit mode '%s'`, b, i+'A', mode)
+		}
+	}
+
+	return nil
+}
+
+// ******** Private type functions ********
+
+// withBaselineSubstitutions sets the substitution lists of a baseline mode, where each letter has exactly one substitution.
+func (s *Substitutor) withBaselineSubstitutions(mode Mode, shift int, substitutionBytes []byte) (*Substitutor, error) {
+	var lists [][]byte
+	switch mode {
+	case Monoalphabetic:
+		lengths := make([]uint16, sourceAlphabetSize)
+		for i := range lengths {
+			lengths[i] = 1
+		}
+
+		lists = generateSubstitutions(lengths, substitutionBytes, s.substitutionAlphabetSize)
+
+	case Caesar:
+		size := int(sourceAlphabetSize)
+		lists = make([][]byte, size)
+		for i := range lists {
+			lists[i] = []byte{byte('A' + ((i+shift)%size+size)%size)}
+		}
+
+	default:
+		return nil, fmt.Errorf(`%w: %d`, ErrUnknownMode, mode)
+	}
+
+	s.mode = mode
+	s.substitutions = make([]randomlist.Selector[byte], len(lists))
+	for i, list := range lists {
+		s.substitutions[i] = randomlist.New(list)
+	}
+
+	return s, nil
+}
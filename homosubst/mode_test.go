@@ -0,0 +1,258 @@
+//
+// SPDX-FileCopyrightText: Copyright 2026 Frank Schwab
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileType: SOURCE
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+//
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Author: Frank Schwab
+//
+// Version: 1.0.0
+//
+// Change history:
+//    2026-10-18: V1.0.0: Created.
+//
+
+package homosubst
+
+import (
+	"errors"
+	"homophone/analysis"
+	"homophone/distributor"
+	"homophone/randomlist"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// ******** Test functions ********
+
+func TestMonoalphabetic(t *testing.T) {
+	s := newTestSubstitutorWithOptions(t, testClearText, Options{Mode: Monoalphabetic})
+	if s.Mode() != Monoalphabetic {
+		t.Fatalf(`Mode is '%s' instead of '%s'`, s.Mode(), Monoalphabetic)
+	}
+
+	used := make(map[byte]bool)
+	for i, list := range s.substitutions {
+		if list.Len() != 1 {
+			t.Fatalf(`'%c' has %d substitutions instead of 1`, i+'A', list.Len())
+		}
+
+		used[list.BaseList()[0]] = true
+	}
+
+	if len(used) != int(sourceAlphabetSize) {
+		t.Fatalf(`%d different substitutions instead of %d`, len(used), sourceAlphabetSize)
+	}
+
+	// A monoalphabetic key uses the same key file and decryption as a homophonic one.
+	substFileName := filepath.Join(t.TempDir(), `mono.subst`)
+	err := s.Save(substFileName, false)
+	if err != nil {
+		t.Fatalf(`Error saving: %v`, err)
+	}
+
+	var loaded *Substitutor
+	loaded, err = NewFromFile(substFileName)
+	if err != nil {
+		t.Fatalf(`Error loading: %v`, err)
+	}
+
+	if loaded.Mode() != Monoalphabetic {
+		t.Fatalf(`Mode of loaded key is '%s' instead of '%s'`, loaded.Mode(), Monoalphabetic)
+	}
+
+	got := encryptAndDecrypt(t, s, loaded, testClearText, true)
+	if got != expectedClearText(testClearText, true) {
+		t.Fatalf(`Decrypted text differs: '%s'`, got)
+	}
+}
+
+func TestCaesar(t *testing.T) {
+	for _, tc := range []struct {
+		shift    int
+		expected string
+	}{
+		{3, `WKH TXLFN EURZQ IRA`},
+		{-1, `SGD PTHBJ AQNVM ENW`},
+		{27, `UIF RVJDL CSPXO GPY`},
+	} {
+		s := newTestSubstitutorWithOptions(t, testClearText, Options{Mode: Caesar, Shift: tc.shift})
+		if s.Mode() != Caesar {
+			t.Fatalf(`Shift %d: Mode is '%s' instead of '%s'`, tc.shift, s.Mode(), Caesar)
+		}
+
+		var encrypted strings.Builder
+		err := s.EncryptStream(strings.NewReader(`The quick brown fox`), &encrypted, true)
+		if err != nil {
+			t.Fatalf(`Shift %d: Error encrypting: %v`, tc.shift, err)
+		}
+
+		if encrypted.String() != tc.expected {
+			t.Fatalf(`Shift %d: Expected '%s', got '%s'`, tc.shift, tc.expected, encrypted.String())
+		}
+	}
+}
+
+func TestModeRoundTrip(t *testing.T) {
+	for _, options := range []Options{{Mode: Monoalphabetic}, {Mode: Caesar, Shift: 0}, {Mode: Caesar, Shift: 5}} {
+		s := newTestSubstitutorWithOptions(t, flatnessTestText, options)
+		if s.IsPartial() || s.fileVersion() != attributeVersion {
+			t.Fatalf(`%+v: Partial is %t and version is %d`, options, s.IsPartial(), s.fileVersion())
+		}
+
+		data, err := s.MarshalBinary()
+		if err != nil {
+			t.Fatalf(`%+v: Error marshalling: %v`, options, err)
+		}
+
+		loaded := &Substitutor{}
+		err = loaded.UnmarshalBinary(data)
+		if err != nil {
+			t.Fatalf(`%+v: Error unmarshalling: %v`, options, err)
+		}
+
+		if loaded.Mode() != options.Mode {
+			t.Fatalf(`%+v: Mode of loaded key is '%s'`, options, loaded.Mode())
+		}
+
+		expectEqualSubstitutions(t, s, loaded)
+
+		var expected, got analysis.Flatness
+		expected, err = s.Flatness()
+		if err != nil {
+			t.Fatalf(`%+v: Error calculating flatness: %v`, options, err)
+		}
+
+		got, err = loaded.Flatness()
+		if err != nil {
+			t.Fatalf(`%+v: Error calculating flatness of loaded key: %v`, options, err)
+		}
+
+		if got != expected {
+			t.Fatalf("%+v: Flatness of loaded key differs\nexpected: %+v\ngot:      %+v", options, expected, got)
+		}
+	}
+}
+
+// TestModeRoundTripWithoutLetterCounts checks that baseline keys without letter counts,
+// i.e. with only the mode in the attribute section, can be loaded.
+func TestModeRoundTripWithoutLetterCounts(t *testing.T) {
+	if !isValidDataLength(minAttributeDataLength, attributeVersion) || isValidDataLength(minAttributeDataLength-1, attributeVersion) {
+		t.Fatalf(`Minimum data length %d of an attribute section is not the limit`, minAttributeDataLength)
+	}
+
+	for _, options := range []Options{{Mode: Monoalphabetic}, {Mode: Caesar, Shift: 3}} {
+		s := newTestSubstitutorWithOptions(t, flatnessTestText, options)
+		s.letterCounts = nil
+
+		data, err := s.MarshalBinary()
+		if err != nil {
+			t.Fatalf(`%+v: Error marshalling: %v`, options, err)
+		}
+
+		loaded := &Substitutor{}
+		err = loaded.UnmarshalBinary(data)
+		if err != nil {
+			t.Fatalf(`%+v: Error unmarshalling: %v`, options, err)
+		}
+
+		if loaded.Mode() != options.Mode || loaded.letterCounts != nil {
+			t.Fatalf(`%+v: Loaded key has mode '%s' and letter counts %v`, options, loaded.Mode(), loaded.letterCounts)
+		}
+
+		expectEqualSubstitutions(t, s, loaded)
+
+		got := encryptAndDecrypt(t, s, loaded, flatnessTestText, true)
+		if got != expectedClearText(flatnessTestText, true) {
+			t.Fatalf(`%+v: Decrypted text differs: '%s'`, options, got)
+		}
+	}
+}
+
+func TestModeWrongSubstitutions(t *testing.T) {
+	s := newTestSubstitutorWithOptions(t, flatnessTestText, Options{Mode: Caesar, Shift: 3})
+	s.substitutions[1] = randomlist.New([]byte(`z`))
+
+	data, err := s.MarshalBinary()
+	if err != nil {
+		t.Fatalf(`Error marshalling: %v`, err)
+	}
+
+	err = (&Substitutor{}).UnmarshalBinary(data)
+	if err == nil || !strings.Contains(err.Error(), `does not fit mode 'caesar'`) {
+		t.Fatalf(`Wrong Caesar substitution resulted in error %v`, err)
+	}
+}
+
+func TestModeOptions(t *testing.T) {
+	for _, options := range []Options{
+		{Mode: Monoalphabetic, Apportioner: distributor.DefaultApportioner()},
+		{Mode: Monoalphabetic, MinLengths: []uint{1}},
+		{Mode: Caesar, MaxLengths: []uint{3}},
+		{Mode: Caesar, Selection: randomlist.Window},
+		{Mode: Monoalphabetic, Weighted: true},
+		{Mode: Caesar, ContextClassCount: 2},
+	} {
+		_, err := newSubstitutorFromText(t, flatnessTestText, options)
+		if !errors.Is(err, ErrModeOptions) {
+			t.Fatalf(`%+v: Expected error '%v', got '%v'`, options, ErrModeOptions, err)
+		}
+	}
+}
+
+func TestModeFlatness(t *testing.T) {
+	homophonic, err := newTestSubstitutorWithOptions(t, flatnessTestText, Options{}).Flatness()
+	if err != nil {
+		t.Fatalf(`Error calculating flatness: %v`, err)
+	}
+
+	for _, mode := range []Mode{Monoalphabetic, Caesar} {
+		var flatness analysis.Flatness
+		flatness, err = newTestSubstitutorWithOptions(t, flatnessTestText, Options{Mode: mode}).Flatness()
+		if err != nil {
+			t.Fatalf(`%s: Error calculating flatness: %v`, mode, err)
+		}
+
+		// Half of the substitution characters are not used, so the baseline modes are much less flat.
+		if flatness.Min != 0 || flatness.Deviation <= homophonic.Deviation {
+			t.Fatalf(`%s: Flatness %+v is not worse than %+v`, mode, flatness, homophonic)
+		}
+	}
+
+	if newTestSubstitutor(t).Mode() != Homophonic {
+		t.Fatal(`Homophonic key has another mode`)
+	}
+}
+
+func TestParseMode(t *testing.T) {
+	for _, name := range ModeNames() {
+		mode, err := ParseMode(strings.ToUpper(name))
+		if err != nil {
+			t.Fatalf(`Error parsing '%s': %v`, name, err)
+		}
+
+		if mode.String() != name {
+			t.Fatalf(`'%s' is parsed as '%s'`, name, mode)
+		}
+	}
+
+	_, err := ParseMode(`vigenere`)
+	if !errors.Is(err, ErrUnknownMode) {
+		t.Fatalf(`Expected error '%v', got '%v'`, ErrUnknownMode, err)
+	}
+}
@@ -20,7 +20,7 @@
 //
 // Author: Frank Schwab
 //
-// Version: 2.1.0
+// Version: 2.10.0
 //
 // Change history:
 //    2024-09-17: V1.0.0: Created.
@@ -29,6 +29,15 @@
 //    2025-01-05: V1.2.0: Correct substitution alphabet.
 //    2025-02-08: V2.0.0: Use rune scanner, make substitution length calculation faster.
 //    2025-02-10: V2.1.0: Calculate proportions from frequencies.
+//    2026-10-18: V2.2.0: Set default hash algorithm.
+//    2026-10-18: V2.3.0: Replaceable random source for reproducible substitutions.
+//    2026-10-18: V2.4.0: Add creation options with apportionment method.
+//    2026-10-18: V2.5.0: Add minimum and maximum substitution lengths.
+//    2026-10-18: V2.6.0: Add selection strategy.
+//    2026-10-18: V2.7.0: Add weighted selection with weights from the exact quotas.
+//    2026-10-18: V2.8.0: Build context-dependent substitutions.
+//    2026-10-18: V2.9.0: Create substitutor from a reader. Count characters and bigrams in one pass.
+//    2026-10-18: V2.10.0: Baseline modes.
 //
 
 package homosubst
@@ -58,65 +67,141 @@ var requiredSubstitutionAlphabetSize = uint32(len(substitutionAlphabet))
 // sourceAlphabetSize contains the size of the alphabet to map, i.e. A-Z.
 const sourceAlphabetSize uint16 = 26
 
+// ******** Public variables ********
+
+// ErrNoLetters is returned when a source for a new substitutor contains no letters.
+var ErrNoLetters = errors.New(`no characters in the range A-Z`)
+
+// ErrContextSelection is returned when predecessor classes are combined with weighted selection or with
+// a selection strategy other than the default. The context section of a key file does not store them.
+var ErrContextSelection = errors.New(`predecessor classes can only be used with the default selection`)
+
+// ******** Private variables ********
+
+// randomIntN returns a random number in [0, n). Tests replace it with a seeded generator,
+// so they can create reproducible substitutions.
+var randomIntN = rand.IntN
+
 // ******** Public creation functions ********
 
-// NewSubstitutor creates a new substitutor for the given file.
+// NewSubstitutor creates a new substitutor for the given file with the default options.
 func NewSubstitutor(sourceFileName string) (*Substitutor, error) {
+	return NewSubstitutorWithOptions(sourceFileName, Options{})
+}
+
+// NewSubstitutorWithOptions creates a new substitutor for the given file with the given options.
+func NewSubstitutorWithOptions(sourceFileName string, options Options) (*Substitutor, error) {
+	file, err := os.Open(sourceFileName)
+	if err != nil {
+		return nil, err
+	}
+	defer filehelper.CloseWithName(file)
+
+	var result *Substitutor
+	result, err = NewSubstitutorFromReader(file, options)
+	if errors.Is(err, ErrNoLetters) {
+		return nil, fmt.Errorf(`source file '%s' has %w`, sourceFileName, err)
+	}
+
+	return result, err
+}
+
+// NewSubstitutorFromReader creates a new substitutor for the text that is read from source with the given options.
+func NewSubstitutorFromReader(source io.Reader, options Options) (*Substitutor, error) {
+	err := checkModeOptions(options)
+	if err != nil {
+		return nil, err
+	}
+
+	if options.Apportioner == nil {
+		options.Apportioner = distributor.DefaultApportioner()
+	}
+
+	if options.ContextClassCount > MaxContextClassCount {
+		return nil, fmt.Errorf(`there are %d predecessor classes, but at most %d are possible`, options.ContextClassCount, MaxContextClassCount)
+	}
+
+	if options.ContextClassCount > 0 && (options.Weighted || options.Selection != randomlist.DefaultStrategy) {
+		return nil, ErrContextSelection
+	}
+
 	substitutionBytes := []byte(substitutionAlphabet)
 	substitutionAlphabetSize := uint16(len(substitutionBytes))
 
 	result := &Substitutor{}
 
 	result.substitutionAlphabetSize = substitutionAlphabetSize
+	result.hashAlgorithm = defaultHashAlgorithm
 
-	// 1. Get the character frequencies from the file.
-	sourceFrequencies, totalCount, err := getFrequenciesFromFile(sourceFileName)
+	// 1. Get the character and bigram frequencies from the source.
+	sourceFrequencies, bigramFrequencies, totalCount, err := countCharacters(source)
 	if err != nil {
 		return nil, err
 	}
 
+	if totalCount == 0 {
+		return nil, ErrNoLetters
+	}
+
+	result.letterCounts = sourceFrequencies
+	result.selection = options.Selection
+	result.weighted = options.Weighted
+
 	result.proportions = makeProportions(sourceFrequencies, totalCount)
 
-	if totalCount == 0 {
-		return nil, fmt.Errorf(`source file '%s' has no characters in the range A-Z`, sourceFileName)
+	result.letterShares = makeLetterShares(sourceFrequencies, totalCount)
+
+	// The baseline modes do not depend on the frequencies.
+	if options.Mode != Homophonic {
+		return result.withBaselineSubstitutions(options.Mode, options.Shift, substitutionBytes)
 	}
 
 	// 2. Get the lengths of the substitutions of each character from the frequencies.
 	var substitutionLengths []uint16
-	substitutionLengths, err = getSubstitutionLengths(sourceFrequencies, totalCount, substitutionAlphabetSize)
+	substitutionLengths, err = getSubstitutionLengths(sourceFrequencies, substitutionAlphabetSize, options)
 	if err != nil {
 		return nil, err
 	}
 
 	// 3. Build the substitution lists from the lengths.
-	result.substitutions = generateSubstitutions(substitutionLengths, substitutionBytes, substitutionAlphabetSize)
+	lists := generateSubstitutions(substitutionLengths, substitutionBytes, substitutionAlphabetSize)
+
+	// 4. Build the selectors that choose a substitution from the lists.
+	result.substitutions, err = makeSelectors(lists, sourceFrequencies, totalCount, substitutionAlphabetSize, options)
+	if err != nil {
+		return nil, err
+	}
+
+	// 5. Build the context-dependent substitution lists, if they are requested.
+	if options.ContextClassCount > 0 {
+		err = result.buildContexts(sourceFrequencies, bigramFrequencies, substitutionBytes, options)
+		if err != nil {
+			return nil, err
+		}
+	}
 
 	return result, nil
 }
 
 // ******** Private functions ********
 
-// getFrequenciesFromFile calculates the frequencies of each character in the file.
-func getFrequenciesFromFile(fileName string) ([]uint, uint, error) {
-	file, err := os.Open(fileName)
-	if err != nil {
-		return nil, 0, err
-	}
-	defer filehelper.CloseWithName(file)
-
+// countCharacters calculates the frequencies of each character and of each bigram in the source.
+// Characters that are not in the range A-Z are skipped. The count of the bigram xy is at index 26*x+y.
+func countCharacters(source io.Reader) ([]uint, []uint, uint, error) {
 	frequencies := make([]uint, sourceAlphabetSize)
+	bigramFrequencies := make([]uint, int(sourceAlphabetSize)*int(sourceAlphabetSize))
 	totalCount := uint(0)
+	previous := -1
 
-	reader := bufio.NewReader(file)
+	reader := bufio.NewReader(source)
 	for {
-		var value byte
-		value, err = reader.ReadByte()
+		value, err := reader.ReadByte()
 		if err != nil {
 			if errors.Is(err, io.EOF) {
-				return frequencies, totalCount, nil
+				return frequencies, bigramFrequencies, totalCount, nil
 			}
 
-			return nil, 0, err
+			return nil, nil, 0, err
 		}
 
 		switch {
@@ -125,8 +210,14 @@ func getFrequenciesFromFile(fileName string) ([]uint, uint, error) {
 			fallthrough
 
 		case value >= 'A' && value <= 'Z':
-			frequencies[value-'A']++
+			actual := int(value - 'A')
+			frequencies[actual]++
 			totalCount++
+
+			if previous >= 0 {
+				bigramFrequencies[previous*int(sourceAlphabetSize)+actual]++
+			}
+			previous = actual
 		}
 	}
 }
@@ -145,51 +236,101 @@ func makeProportions(sourceFrequencies []uint, totalCount uint) []uint16 {
 
 // getSubstitutionLengths calculates the number of substitutions for each character
 // from the frequencies.
-func getSubstitutionLengths(sourceFrequencies []uint, totalCount uint, substitutionAlphabetSize uint16) ([]uint16, error) {
+func getSubstitutionLengths(
+	sourceFrequencies []uint,
+	substitutionAlphabetSize uint16,
+	options Options) ([]uint16, error) {
 	result := make([]uint16, sourceAlphabetSize)
 
-	calculateSubstitutionLengths(sourceFrequencies, totalCount, substitutionAlphabetSize, result)
+	err := calculateSubstitutionLengths(sourceFrequencies, substitutionAlphabetSize, options, result)
+	if err != nil {
+		return nil, err
+	}
 
 	return result, nil
 }
 
 // calculateSubstitutionLengths calculates the substitution lengths.
-// The result may not have the correct substitution count. This will be the case if there are
-// a lot of characters with the same count.
 func calculateSubstitutionLengths(
 	sourceFrequencies []uint,
-	totalCount uint,
 	substitutionAlphabetSize uint16,
-	substitutionLengths []uint16) {
-	substitutionCount := initializeSubstitutionLengths(sourceFrequencies, substitutionLengths)
+	options Options,
+	substitutionLengths []uint16) error {
+	minLengths, additionalMaxLengths, err := lengthBounds(sourceFrequencies, options.MinLengths, options.MaxLengths)
+	if err != nil {
+		return err
+	}
+
+	substitutionCount := initializeSubstitutionLengths(minLengths, substitutionLengths)
+	if substitutionCount > uint(substitutionAlphabetSize) {
+		return fmt.Errorf(`the minimum numbers of substitutions need %d characters, but there are only %d`, substitutionCount, substitutionAlphabetSize)
+	}
 
 	// 2. Distribute the remaining substitution alphabet size among the characters.
-	remainingCount := substitutionAlphabetSize - substitutionCount
-	additionalLengths := distributor.SainteLagueDistribution(
-		sourceFrequencies,
-		totalCount,
-		uint(remainingCount))
+	apportioner := options.Apportioner
+	remainingCount := uint(substitutionAlphabetSize) - substitutionCount
+	additionalLengths, err := apportioner.ApportionBounded(sourceFrequencies, remainingCount, nil, additionalMaxLengths)
+	if err != nil {
+		return fmt.Errorf(`error distributing substitutions with method '%s': %w`, apportioner.Name(), err)
+	}
 
-	// 3. Add the distributed lengths to the count of 1 that has already been set.
+	// 3. Add the distributed lengths to the minimum lengths that have already been set.
 	for i := range substitutionLengths {
 		substitutionLengths[i] += uint16(additionalLengths[i])
 	}
 
-	substitutionCount += remainingCount
+	return nil
 }
 
-// initializeSubstitutionLengths initializes the substitution lengths to have the value 1 for each
-// character that appears in the source.
-func initializeSubstitutionLengths(sourceFrequencies []uint, substitutionLengths []uint16) uint16 {
-	substitutionCount := uint16(0)
-	// 1. Each source character gets one substitution character.
+// lengthBounds returns the minimum length of each character and how many substitutions
+// may be added to the minimum length. The latter is nil, if there is no maximum.
+// A character that appears in the source always has a minimum length of at least 1.
+func lengthBounds(sourceFrequencies []uint, minLengths []uint, maxLengths []uint) ([]uint, []uint, error) {
+	if minLengths != nil && len(minLengths) != int(sourceAlphabetSize) {
+		return nil, nil, fmt.Errorf(`there are %d minimum lengths instead of %d`, len(minLengths), sourceAlphabetSize)
+	}
+
+	if maxLengths != nil && len(maxLengths) != int(sourceAlphabetSize) {
+		return nil, nil, fmt.Errorf(`there are %d maximum lengths instead of %d`, len(maxLengths), sourceAlphabetSize)
+	}
+
+	resultMin := make([]uint, sourceAlphabetSize)
+	if minLengths != nil {
+		copy(resultMin, minLengths)
+	}
+
 	for i, f := range sourceFrequencies {
-		// Only calculate a substitution length if the value occurs, at all.
-		if f != 0 {
-			substitutionLengths[i] = 1
-			substitutionCount++
+		if f != 0 && resultMin[i] == 0 {
+			resultMin[i] = 1
+		}
+	}
+
+	if maxLengths == nil {
+		return resultMin, nil, nil
+	}
+
+	resultAdditional := make([]uint, sourceAlphabetSize)
+	for i, maxLength := range maxLengths {
+		if resultMin[i] > maxLength {
+			return nil, nil, fmt.Errorf(`character '%c' needs at least %d substitutions, but the maximum is %d`, i+'A', resultMin[i], maxLength)
 		}
+
+		resultAdditional[i] = maxLength - resultMin[i]
 	}
+
+	return resultMin, resultAdditional, nil
+}
+
+// initializeSubstitutionLengths initializes the substitution lengths with their minimum lengths.
+// It returns the number of substitutions that have been assigned.
+func initializeSubstitutionLengths(minLengths []uint, substitutionLengths []uint16) uint {
+	substitutionCount := uint(0)
+	// 1. Each source character gets its minimum number of substitution characters.
+	for i, minLength := range minLengths {
+		substitutionLengths[i] = uint16(minLength)
+		substitutionCount += minLength
+	}
+
 	return substitutionCount
 }
 
@@ -197,15 +338,81 @@ func initializeSubstitutionLengths(sourceFrequencies []uint, substitutionLengths
 func generateSubstitutions(
 	substitutionLengths []uint16,
 	substitutionAlphabet []byte,
-	substitutionAlphabetSize uint16) []*randomlist.RandomList[byte] {
+	substitutionAlphabetSize uint16) [][]byte {
 	used := make([]bool, substitutionAlphabetSize)
-	result := make([]*randomlist.RandomList[byte], sourceAlphabetSize)
+	result := make([][]byte, sourceAlphabetSize)
 	for i, substitutionLength := range substitutionLengths {
 		list := make([]byte, substitutionLength)
 		for j := range substitutionLength {
 			list[j] = substitutionAlphabet[getSubstitutionAlphabetIndex(used, substitutionAlphabetSize)]
 		}
-		result[i] = randomlist.New(list)
+		result[i] = list
+	}
+
+	return result
+}
+
+// makeLetterShares calculates the share of each character in the source.
+func makeLetterShares(sourceFrequencies []uint, totalCount uint) []float64 {
+	result := make([]float64, len(sourceFrequencies))
+	for i, f := range sourceFrequencies {
+		result[i] = float64(f) / float64(totalCount)
+	}
+
+	return result
+}
+
+// makeSelectors creates the selectors for the substitution lists.
+func makeSelectors(
+	lists [][]byte,
+	sourceFrequencies []uint,
+	totalCount uint,
+	substitutionAlphabetSize uint16,
+	options Options) ([]randomlist.Selector[byte], error) {
+	result := make([]randomlist.Selector[byte], len(lists))
+	for i, list := range lists {
+		if !options.Weighted || len(list) == 0 {
+			result[i] = randomlist.NewWithStrategy(list, options.Selection)
+			continue
+		}
+
+		quota := float64(sourceFrequencies[i]) * float64(substitutionAlphabetSize) / float64(totalCount)
+		weightedList, err := randomlist.NewWeighted(list, quotaWeights(quota, len(list)))
+		if err != nil {
+			return nil, fmt.Errorf(`error creating weighted substitutions for character '%c': %w`, i+'A', err)
+		}
+		result[i] = weightedList
+	}
+
+	return result, nil
+}
+
+// quotaWeights calculates the weights of the substitutions of a character from its exact quota,
+// i.e. the number of substitution characters it would get without rounding.
+// If the quota is smaller than the number of substitutions, as many substitutions as possible
+// get a weight of 1, so they occur exactly with the ideal frequency. The remainder of the
+// quota is distributed evenly among the other substitutions, so no substitution is unused.
+// If the quota is not smaller than the number of substitutions, no substitution can occur with
+// the ideal frequency and all get the same weight. This is also the case for a character that
+// does not occur in the source.
+func quotaWeights(quota float64, count int) []float64 {
+	result := make([]float64, count)
+	if quota <= 0 || quota >= float64(count) {
+		for j := range result {
+			result[j] = 1
+		}
+
+		return result
+	}
+
+	fullCount := int(math.Ceil(quota)) - 1
+	remainderWeight := (quota - float64(fullCount)) / float64(count-fullCount)
+	for j := range result {
+		if j < fullCount {
+			result[j] = 1
+		} else {
+			result[j] = remainderWeight
+		}
 	}
 
 	return result
@@ -214,7 +421,7 @@ func generateSubstitutions(
 // getSubstitutionAlphabetIndex gets the substitution index into the substitution alphabet.
 func getSubstitutionAlphabetIndex(used []bool, usedSize uint16) int {
 	for {
-		i := rand.IntN(int(usedSize))
+		i := randomIntN(int(usedSize))
 
 		if !used[i] {
 			used[i] = true
@@ -0,0 +1,126 @@
+//
+// SPDX-FileCopyrightText: Copyright 2026 Frank Schwab
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileType: SOURCE
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+//
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Author: Frank Schwab
+//
+// Version: 1.0.0
+//
+// Change history:
+//    2026-10-18: V1.0.0: Created.
+//
+
+package homosubst
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+// ******** Test functions ********
+
+func TestLengthBounds(t *testing.T) {
+	minLengths := slices.Repeat([]uint{1}, int(sourceAlphabetSize))
+	maxLengths := slices.Repeat([]uint{3}, int(sourceAlphabetSize))
+	minLengths['Z'-'A'] = 2
+	maxLengths['E'-'A'] = 2
+
+	s := newTestSubstitutorWithOptions(t, `Eeeeee, this text has no letter after Y.`, Options{MinLengths: minLengths, MaxLengths: maxLengths})
+
+	total := 0
+	for i, list := range s.substitutions {
+		l := uint(list.Len())
+		if l < minLengths[i] || l > maxLengths[i] {
+			t.Errorf(`'%c' has %d substitutions, which is not in [%d, %d]`, i+'A', l, minLengths[i], maxLengths[i])
+		}
+
+		total += list.Len()
+	}
+
+	if total != int(s.substitutionAlphabetSize) {
+		t.Errorf(`There are %d substitutions instead of %d`, total, s.substitutionAlphabetSize)
+	}
+}
+
+func TestLengthBoundsOccurringCharacters(t *testing.T) {
+	minLengths := make([]uint, sourceAlphabetSize)
+	maxLengths := slices.Repeat([]uint{52}, int(sourceAlphabetSize))
+	maxLengths['Q'-'A'] = 0
+
+	// A character that occurs always gets a substitution, so a maximum of 0 is not possible.
+	_, err := newSubstitutorFromText(t, `Quick`, Options{MinLengths: minLengths, MaxLengths: maxLengths})
+	if err == nil {
+		t.Fatal(`A maximum of 0 for an occurring character did not fail`)
+	}
+
+	s := newTestSubstitutorWithOptions(t, `Quick`, Options{MinLengths: minLengths})
+	for _, c := range `QUICK` {
+		if s.substitutions[c-'A'].Len() == 0 {
+			t.Errorf(`Occurring character '%c' has no substitution`, c)
+		}
+	}
+}
+
+func TestLengthBoundsInvalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		options Options
+	}{
+		{`minimums too large`, Options{MinLengths: slices.Repeat([]uint{3}, int(sourceAlphabetSize))}},
+		{`maximums too small`, Options{MaxLengths: slices.Repeat([]uint{1}, int(sourceAlphabetSize))}},
+		{`wrong number of minimums`, Options{MinLengths: []uint{1, 2, 3}}},
+		{`wrong number of maximums`, Options{MaxLengths: []uint{1, 2, 3}}},
+	}
+
+	for _, tc := range tests {
+		_, err := newSubstitutorFromText(t, testClearText, tc.options)
+		if err == nil {
+			t.Errorf(`%s: Creating a substitutor did not fail`, tc.name)
+		}
+	}
+}
+
+// ******** Private functions ********
+
+// newTestSubstitutorWithOptions creates a substitutor from a clear text with options and fails on errors.
+func newTestSubstitutorWithOptions(t *testing.T, clearText string, options Options) *Substitutor {
+	t.Helper()
+
+	s, err := newSubstitutorFromText(t, clearText, options)
+	if err != nil {
+		t.Fatalf(`Error creating substitutor: %v`, err)
+	}
+
+	return s
+}
+
+// newSubstitutorFromText creates a substitutor from a clear text with options.
+func newSubstitutorFromText(t *testing.T, clearText string, options Options) (*Substitutor, error) {
+	t.Helper()
+
+	clearFileName := filepath.Join(t.TempDir(), `clear.txt`)
+	err := os.WriteFile(clearFileName, []byte(clearText), 0600)
+	if err != nil {
+		t.Fatalf(`Error writing clear text file: %v`, err)
+	}
+
+	return NewSubstitutorWithOptions(clearFileName, options)
+}
@@ -0,0 +1,97 @@
+//
+// SPDX-FileCopyrightText: Copyright 2026 Frank Schwab
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileType: SOURCE
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+//
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Author: Frank Schwab
+//
+// Version: 1.0.1
+//
+// Change history:
+//    2026-10-18: V1.0.0: Created.
+//    2026-10-18: V1.0.1: Keys of the baseline modes are not partial.
+//
+
+package homosubst
+
+import (
+	"errors"
+	"fmt"
+	"homophone/randomlist"
+	"slices"
+	"strings"
+)
+
+// ******** Public constants ********
+
+// ErrInvalidPartialKey is returned when the substitution lists of a partial key are invalid.
+var ErrInvalidPartialKey = errors.New(`invalid partial key`)
+
+// ******** Public functions ********
+
+// NewPartial creates a new Substitutor from known substitutions, e.g. from a cryptanalysis.
+// homophones contains the substitutions of each character A-Z. The lists may be empty and need not
+// contain all characters of the substitution alphabet, but no substitution character may occur twice.
+// Characters without substitutions can not be encrypted and unknown substitution characters are
+// not changed by the decryption.
+func NewPartial(homophones [][]byte) (*Substitutor, error) {
+	if len(homophones) != int(sourceAlphabetSize) {
+		return nil, fmt.Errorf(`%w: %d substitution lists instead of %d`, ErrInvalidPartialKey, len(homophones), sourceAlphabetSize)
+	}
+
+	substitutions := make([]randomlist.Selector[byte], sourceAlphabetSize)
+	used := make(map[byte]bool)
+	for i, list := range homophones {
+		for _, b := range list {
+			if strings.IndexByte(substitutionAlphabet, b) < 0 {
+				return nil, fmt.Errorf(`%w: invalid substitution character %q of '%c'`, ErrInvalidPartialKey, b, i+'A')
+			}
+
+			if used[b] {
+				return nil, fmt.Errorf(`%w: duplicate substitution character '%c'`, ErrInvalidPartialKey, b)
+			}
+
+			used[b] = true
+		}
+
+		substitutions[i] = randomlist.New(slices.Clone(list))
+	}
+
+	return &Substitutor{
+		substitutions:            substitutions,
+		substitutionAlphabetSize: uint16(requiredSubstitutionAlphabetSize),
+		hashAlgorithm:            defaultHashAlgorithm,
+	}, nil
+}
+
+// ******** Public type functions ********
+
+// IsPartial returns true, if the substitutor is homophonic and its substitution lists do not contain
+// all characters of the substitution alphabet. The keys of the baseline modes are not partial.
+func (s *Substitutor) IsPartial() bool {
+	if s.mode != Homophonic {
+		return false
+	}
+
+	count := 0
+	for _, list := range s.substitutions {
+		count += list.Len()
+	}
+
+	return count < int(s.substitutionAlphabetSize)
+}
@@ -0,0 +1,149 @@
+//
+// SPDX-FileCopyrightText: Copyright 2026 Frank Schwab
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileType: SOURCE
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+//
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Author: Frank Schwab
+//
+// Version: 1.0.0
+//
+// Change history:
+//    2026-10-18: V1.0.0: Created.
+//
+
+package homosubst
+
+import (
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// ******** Test functions ********
+
+func TestPartialRoundTrip(t *testing.T) {
+	homophones := make([][]byte, sourceAlphabetSize)
+	homophones['D'-'A'] = []byte(`Xq`)
+	homophones['E'-'A'] = []byte(`aB`)
+	homophones['R'-'A'] = []byte(`z`)
+
+	s, err := NewPartial(homophones)
+	if err != nil {
+		t.Fatalf(`Error creating partial key: %v`, err)
+	}
+
+	if !s.IsPartial() {
+		t.Fatal(`Partial key is not partial`)
+	}
+
+	substFileName := filepath.Join(t.TempDir(), `partial.subst`)
+	err = s.Save(substFileName, false)
+	if err != nil {
+		t.Fatalf(`Error saving: %v`, err)
+	}
+
+	var u *Substitutor
+	u, err = NewFromFile(substFileName)
+	if err != nil {
+		t.Fatalf(`Error loading: %v`, err)
+	}
+
+	if !u.IsPartial() {
+		t.Fatal(`Loaded partial key is not partial`)
+	}
+
+	expectEqualSubstitutions(t, s, u)
+}
+
+func TestPartialOneSubstitutionPerLetter(t *testing.T) {
+	// A partial key from a crib may have one substitution for each letter, but it is still homophonic.
+	homophones := make([][]byte, sourceAlphabetSize)
+	for i := range homophones {
+		homophones[i] = []byte{substitutionAlphabet[i]}
+	}
+
+	s, err := NewPartial(homophones)
+	if err != nil {
+		t.Fatalf(`Error creating partial key: %v`, err)
+	}
+
+	if s.fileVersion() != partialVersion {
+		t.Fatalf(`Partial key is saved with version %d instead of %d`, s.fileVersion(), partialVersion)
+	}
+
+	var data []byte
+	data, err = s.MarshalBinary()
+	if err != nil {
+		t.Fatalf(`Error marshalling: %v`, err)
+	}
+
+	u := &Substitutor{}
+	err = u.UnmarshalBinary(data)
+	if err != nil {
+		t.Fatalf(`Error unmarshalling: %v`, err)
+	}
+
+	if u.Mode() != Homophonic || !u.IsPartial() {
+		t.Fatalf(`Loaded partial key has mode '%s' and partial %t`, u.Mode(), u.IsPartial())
+	}
+}
+
+func TestPartialDecryptsKnownCharacters(t *testing.T) {
+	homophones := make([][]byte, sourceAlphabetSize)
+	homophones['D'-'A'] = []byte(`Xq`)
+	homophones['E'-'A'] = []byte(`aB`)
+
+	s, err := NewPartial(homophones)
+	if err != nil {
+		t.Fatalf(`Error creating partial key: %v`, err)
+	}
+
+	var decrypted strings.Builder
+	err = s.DecryptStream(strings.NewReader(`XaqBzz`), &decrypted)
+	if err != nil {
+		t.Fatalf(`Error decrypting: %v`, err)
+	}
+
+	// Unknown substitution characters are not changed.
+	if decrypted.String() != `DEDEzz` {
+		t.Fatalf(`Expected 'DEDEzz', got '%s'`, decrypted.String())
+	}
+}
+
+func TestPartialInvalid(t *testing.T) {
+	duplicate := make([][]byte, sourceAlphabetSize)
+	duplicate[0] = []byte(`ab`)
+	duplicate[1] = []byte(`b`)
+
+	invalid := make([][]byte, sourceAlphabetSize)
+	invalid[0] = []byte(`1`)
+
+	for _, homophones := range [][][]byte{nil, duplicate, invalid} {
+		_, err := NewPartial(homophones)
+		if !errors.Is(err, ErrInvalidPartialKey) {
+			t.Fatalf(`Expected error '%v', got '%v'`, ErrInvalidPartialKey, err)
+		}
+	}
+}
+
+func TestCompleteKeyIsNotPartial(t *testing.T) {
+	if newTestSubstitutor(t).IsPartial() {
+		t.Fatal(`Complete key is partial`)
+	}
+}
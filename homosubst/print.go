@@ -20,46 +20,78 @@
 //
 // Author: Frank Schwab
 //
-// Version: 2.0.0
+// Version: 2.2.0
 //
 // Change history:
 //    2024-09-17: V1.0.0: Created.
 //    2025-02-10: V2.0.0: Print proportions, if present.
+//    2026-10-18: V2.1.0: Print to an arbitrary writer.
+//    2026-10-18: V2.2.0: Print context-dependent substitutions.
 //
 
 package homosubst
 
-import "fmt"
+import (
+	"fmt"
+	"homophone/randomlist"
+	"io"
+	"os"
+	"strings"
+)
 
 // ******** Public functions ********
 
-// Print prints all substitutions.
+// Print prints all substitutions to stdout.
 func (s *Substitutor) Print() {
-	substitutions := s.substitutions
-	proportions := s.proportions
+	s.Fprint(os.Stdout)
+}
+
+// Fprint prints all substitutions to w.
+// Context-dependent substitutions are printed after the substitutions without a predecessor.
+func (s *Substitutor) Fprint(w io.Writer) {
+	fprintSubstitutions(w, s.substitutions, s.proportions)
+
+	for class, substitutions := range s.contextSubstitutions {
+		_, _ = fmt.Fprintf(w, "  After %s:\n", s.classLetters(byte(class)))
+		fprintSubstitutions(w, substitutions, nil)
+	}
+}
+
+// ******** Private type functions ********
+
+// classLetters returns the letters of a predecessor class.
+func (s *Substitutor) classLetters(class byte) string {
+	var result strings.Builder
+	for i, c := range s.contextClasses {
+		if c == class {
+			result.WriteByte(byte(i) + 'A')
+		}
+	}
+
+	return result.String()
+}
+
+// ******** Private functions ********
+
+// fprintSubstitutions prints substitution lists with their proportions, if present.
+func fprintSubstitutions(w io.Writer, substitutions []randomlist.Selector[byte], proportions []uint16) {
 	for i, substitution := range substitutions {
-		fmt.Printf(`   %c`, i+'A')
+		_, _ = fmt.Fprintf(w, `   %c`, i+'A')
 		if proportions != nil {
-			printProportion(proportions[i])
+			printProportion(w, proportions[i])
 		}
-		fmt.Print(`: `)
+		_, _ = fmt.Fprint(w, `: `)
 
 		for _, r := range substitution.BaseList() {
-			fmt.Printf(`%c`, r)
+			_, _ = fmt.Fprintf(w, `%c`, r)
 		}
 
-		fmt.Println()
+		_, _ = fmt.Fprintln(w)
 	}
 }
 
-// ******** Private functions ********
-
 // printProportion prints a proportion.
-func printProportion(proportion uint16) {
-	fmt.Print(` (`)
+func printProportion(w io.Writer, proportion uint16) {
 	fixProportion := proportion / 100
-	fmt.Printf(`%3d`, fixProportion)
-	fmt.Print(`.`)
-	fmt.Printf(`%02d`, proportion-(fixProportion*100))
-	fmt.Print(`%)`)
+	_, _ = fmt.Fprintf(w, ` (%3d.%02d%%)`, fixProportion, proportion-(fixProportion*100))
 }
@@ -0,0 +1,160 @@
+//
+// SPDX-FileCopyrightText: Copyright 2026 Frank Schwab
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileType: SOURCE
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+//
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Author: Frank Schwab
+//
+// Version: 1.1.0
+//
+// Change history:
+//    2026-10-18: V1.0.0: Created.
+//    2026-10-18: V1.1.0: Derive the key from the context-dependent substitutions, too.
+//
+
+package homosubst
+
+import (
+	"crypto/hmac"
+	"hash"
+	"homophone/filehelper"
+	"homophone/integritycheckedfile"
+	"homophone/randomlist"
+	"homophone/slicehelper"
+	"io"
+	"os"
+	"slices"
+)
+
+// ******** Private constants ********
+
+// protectedAdditionalData is the additional data for the integrity check of protected encrypted files.
+var protectedAdditionalData = []byte(`HoTzpCiT`)
+
+// protectionLabel separates the key of protected encrypted files from other keys derived from the integrity key.
+var protectionLabel = []byte(`homophone protected file key`)
+
+// ******** Public type functions ********
+
+// EncryptProtected encrypts a file like [Substitutor.Encrypt], but writes the encrypted file in the
+// chunked integrity-checked format of [integritycheckedfile.ChunkedWriter].
+// The tags are calculated with a key that is derived from the substitution data,
+// so they can only be created and verified with the key file.
+func (s *Substitutor) EncryptProtected(clearFileName string, encryptedFileName string, keepOthers bool, overwrite bool) error {
+	return s.encryptToFile(clearFileName, encryptedFileName, keepOthers, overwrite, true)
+}
+
+// DecryptProtected decrypts a file that has been encrypted with [Substitutor.EncryptProtected].
+// The decrypted file is not written, if the encrypted file has been modified or truncated.
+func (s *Substitutor) DecryptProtected(encryptedFileName string, decryptedFileName string, overwrite bool) error {
+	return s.decryptToFile(encryptedFileName, decryptedFileName, overwrite, true)
+}
+
+// ******** Private type functions ********
+
+// encryptProtectedFile encrypts inFile and writes the encrypted text in the protected format to outFile.
+func (s *Substitutor) encryptProtectedFile(inFile *os.File, outFile *filehelper.AtomicFile, keepOthers bool) error {
+	hashFunc, key, err := s.protectionKey()
+	if err != nil {
+		return err
+	}
+	defer slicehelper.ClearNumber(key)
+
+	var w *integritycheckedfile.ChunkedWriter
+	w, err = integritycheckedfile.NewChunkedWriter(
+		outFile,
+		integritycheckedfile.DefaultBlockSize,
+		hashFunc,
+		key,
+		protectedAdditionalData)
+	if err != nil {
+		return makeFileError(`write`, `out`, outFile.Name(), err)
+	}
+
+	err = s.encryptStream(inFile, w, keepOthers, inFile.Name(), outFile.Name())
+	if err != nil {
+		return err
+	}
+
+	err = w.Close()
+	if err != nil {
+		return makeFileError(`write`, `out`, outFile.Name(), err)
+	}
+
+	return nil
+}
+
+// decryptProtectedFile verifies and decrypts inFile in the protected format and writes the decrypted text to outFile.
+func (s *Substitutor) decryptProtectedFile(inFile *os.File, outFile *filehelper.AtomicFile) error {
+	hashFunc, key, err := s.protectionKey()
+	if err != nil {
+		return err
+	}
+	defer slicehelper.ClearNumber(key)
+
+	var r *integritycheckedfile.ChunkedReader
+	r, err = integritycheckedfile.NewChunkedReader(inFile, hashFunc, key, protectedAdditionalData)
+	if err != nil {
+		return makeFileError(`read from`, `in`, inFile.Name(), err)
+	}
+
+	return s.decryptStream(r, outFile, inFile.Name(), outFile.Name())
+}
+
+// protectionKey returns the hash function and the key for the integrity check of protected encrypted files.
+// The key is the HMAC of a fixed label and the sorted substitutions of each letter.
+// Keys with predecessor classes add the classes and the sorted context-dependent substitutions.
+// It does not depend on the format of the key file, so it is the same for a substitutor and
+// the substitutor loaded from its key file, even if the key file has been saved in a newer format.
+func (s *Substitutor) protectionKey() (func() hash.Hash, []byte, error) {
+	hashFunc, err := s.hashAlgorithm.HashFunc()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	mac := hmac.New(hashFunc, integrityKey())
+	_, _ = mac.Write(protectionLabel)
+
+	writeSortedSubstitutions(mac, s.substitutions)
+
+	if len(s.contextSubstitutions) != 0 {
+		_, _ = mac.Write([]byte{byte(len(s.contextSubstitutions))})
+		_, _ = mac.Write(s.contextClasses)
+
+		for _, substitutions := range s.contextSubstitutions {
+			writeSortedSubstitutions(mac, substitutions)
+		}
+	}
+
+	return hashFunc, mac.Sum(nil), nil
+}
+
+// ******** Private functions ********
+
+// writeSortedSubstitutions writes the number and the sorted substitutions of each letter to w.
+func writeSortedSubstitutions(w io.Writer, lists []randomlist.Selector[byte]) {
+	for _, list := range lists {
+		substitutions := slices.Clone(list.BaseList())
+		slices.Sort(substitutions)
+
+		_, _ = w.Write([]byte{byte(len(substitutions))})
+		_, _ = w.Write(substitutions)
+
+		slicehelper.ClearNumber(substitutions)
+	}
+}
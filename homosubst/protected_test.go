@@ -0,0 +1,240 @@
+//
+// SPDX-FileCopyrightText: Copyright 2026 Frank Schwab
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileType: SOURCE
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+//
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Author: Frank Schwab
+//
+// Version: 1.1.0
+//
+// Change history:
+//    2026-10-18: V1.0.0: Created.
+//    2026-10-18: V1.1.0: Check that the key depends on the context-dependent substitutions.
+//
+
+package homosubst
+
+import (
+	"bytes"
+	"errors"
+	"homophone/filehelper"
+	"homophone/integritycheckedfile"
+	"homophone/randomlist"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// ******** Test functions ********
+
+func TestProtectedRoundTrip(t *testing.T) {
+	s := newTestSubstitutor(t)
+
+	// The key that is loaded from the key file must verify the tags.
+	dir := t.TempDir()
+	keyFileName := filepath.Join(dir, `key.subst`)
+	err := s.Save(keyFileName, false)
+	if err != nil {
+		t.Fatalf(`Error saving key file: %v`, err)
+	}
+
+	var loaded *Substitutor
+	loaded, err = NewFromFile(keyFileName)
+	if err != nil {
+		t.Fatalf(`Error loading key file: %v`, err)
+	}
+
+	encryptedFileName, decryptedFileName := encryptProtected(t, s, dir, testClearText)
+
+	err = loaded.DecryptProtected(encryptedFileName, decryptedFileName, false)
+	if err != nil {
+		t.Fatalf(`Error decrypting: %v`, err)
+	}
+
+	expectDecrypted(t, decryptedFileName, strings.ToUpper(testClearText))
+}
+
+func TestProtectedAfterResave(t *testing.T) {
+	s := newTestSubstitutor(t)
+
+	// Rebuild the key in the format without hash algorithm, which has no attribute section.
+	s.letterCounts = nil
+	data, err := s.MarshalBinary()
+	if err != nil {
+		t.Fatalf(`Error marshaling: %v`, err)
+	}
+
+	hashFunc, _ := defaultHashAlgorithm.HashFunc()
+	payload := data[len(fileMagic)+2 : len(data)-hashFunc().Size()]
+
+	var buffer bytes.Buffer
+	w := integritycheckedfile.NewWriterFromWriter(&buffer, hashFunc, integrityKey(), additionalData)
+	_, _ = w.Write(fileMagic)
+	_, _ = w.Write([]byte{versionWithoutHashAlgorithm})
+	_, _ = w.Write(payload)
+	_ = w.Close()
+
+	var old Substitutor
+	err = old.UnmarshalBinary(buffer.Bytes())
+	if err != nil {
+		t.Fatalf(`Error unmarshaling old format: %v`, err)
+	}
+
+	dir := t.TempDir()
+	encryptedFileName, decryptedFileName := encryptProtected(t, &old, dir, testClearText)
+
+	// Saving the key writes the current format. The protected file must still be verified with it.
+	keyFileName := filepath.Join(dir, `key.subst`)
+	err = old.Save(keyFileName, false)
+	if err != nil {
+		t.Fatalf(`Error saving key file: %v`, err)
+	}
+
+	var resaved *Substitutor
+	resaved, err = NewFromFile(keyFileName)
+	if err != nil {
+		t.Fatalf(`Error loading key file: %v`, err)
+	}
+
+	err = resaved.DecryptProtected(encryptedFileName, decryptedFileName, false)
+	if err != nil {
+		t.Fatalf(`Error decrypting with resaved key: %v`, err)
+	}
+
+	expectDecrypted(t, decryptedFileName, strings.ToUpper(testClearText))
+}
+
+func TestProtectedModified(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		modify   func(data []byte) []byte
+		expected error
+	}{
+		{`changed`, func(data []byte) []byte { data[10] ^= 1; return data }, integritycheckedfile.ErrFileCorrupt},
+		{`shortened`, func(data []byte) []byte { return data[:len(data)-1] }, integritycheckedfile.ErrFileCorrupt},
+		{`truncated`, func(data []byte) []byte { return data[:3] }, integritycheckedfile.ErrFileTruncated},
+	} {
+		s := newTestSubstitutor(t)
+		dir := t.TempDir()
+		encryptedFileName, decryptedFileName := encryptProtected(t, s, dir, testClearText)
+
+		data, err := os.ReadFile(encryptedFileName)
+		if err != nil {
+			t.Fatalf(`Error reading encrypted file: %v`, err)
+		}
+
+		err = os.WriteFile(encryptedFileName, tc.modify(data), 0600)
+		if err != nil {
+			t.Fatalf(`Error writing encrypted file: %v`, err)
+		}
+
+		err = s.DecryptProtected(encryptedFileName, decryptedFileName, false)
+		if !errors.Is(err, tc.expected) {
+			t.Fatalf(`%s: Expected error '%v', got '%v'`, tc.name, tc.expected, err)
+		}
+
+		if exists, _ := filehelper.FileExists(decryptedFileName); exists {
+			t.Fatalf(`%s: Decrypted file has been written`, tc.name)
+		}
+	}
+}
+
+func TestProtectedWrongKey(t *testing.T) {
+	dir := t.TempDir()
+	encryptedFileName, decryptedFileName := encryptProtected(t, newTestSubstitutor(t), dir, testClearText)
+
+	err := newTestSubstitutor(t).DecryptProtected(encryptedFileName, decryptedFileName, false)
+	if !errors.Is(err, integritycheckedfile.ErrFileCorrupt) {
+		t.Fatalf(`Expected error '%v', got '%v'`, integritycheckedfile.ErrFileCorrupt, err)
+	}
+}
+
+// TestProtectionKeyContexts checks that the key changes, if only the context-dependent substitutions change.
+func TestProtectionKeyContexts(t *testing.T) {
+	s := newTestSubstitutorWithOptions(t, flatnessTestText, Options{ContextClassCount: 2})
+	original := protectionKeyOf(t, s)
+
+	// Swap the first substitutions of the first two letters of a predecessor class that have substitutions.
+	substitutions := s.contextSubstitutions[1]
+	var letters []int
+	for i, list := range substitutions {
+		if list.Len() != 0 {
+			letters = append(letters, i)
+		}
+	}
+
+	first := bytes.Clone(substitutions[letters[0]].BaseList())
+	second := bytes.Clone(substitutions[letters[1]].BaseList())
+	first[0], second[0] = second[0], first[0]
+	substitutions[letters[0]] = randomlist.New(first)
+	substitutions[letters[1]] = randomlist.New(second)
+
+	if bytes.Equal(original, protectionKeyOf(t, s)) {
+		t.Fatal(`Changing the context-dependent substitutions did not change the key`)
+	}
+}
+
+// ******** Private functions ********
+
+// protectionKeyOf returns the key for protected encrypted files of a substitutor.
+func protectionKeyOf(t *testing.T, s *Substitutor) []byte {
+	t.Helper()
+
+	_, key, err := s.protectionKey()
+	if err != nil {
+		t.Fatalf(`Error getting protection key: %v`, err)
+	}
+
+	return key
+}
+
+// encryptProtected encrypts a clear text into a protected file in dir.
+// It returns the name of the encrypted file and the name for the decrypted file.
+func encryptProtected(t *testing.T, s *Substitutor, dir string, clearText string) (string, string) {
+	t.Helper()
+
+	clearFileName := filepath.Join(dir, `clear.txt`)
+	encryptedFileName := filepath.Join(dir, `encrypted.txt`)
+
+	err := os.WriteFile(clearFileName, []byte(clearText), 0600)
+	if err != nil {
+		t.Fatalf(`Error writing clear text file: %v`, err)
+	}
+
+	err = s.EncryptProtected(clearFileName, encryptedFileName, true, false)
+	if err != nil {
+		t.Fatalf(`Error encrypting: %v`, err)
+	}
+
+	return encryptedFileName, filepath.Join(dir, `decrypted.txt`)
+}
+
+// expectDecrypted checks that the decrypted file contains the expected text.
+func expectDecrypted(t *testing.T, decryptedFileName string, expected string) {
+	t.Helper()
+
+	decrypted, err := os.ReadFile(decryptedFileName)
+	if err != nil {
+		t.Fatalf(`Error reading decrypted file: %v`, err)
+	}
+
+	if string(decrypted) != expected {
+		t.Fatalf("Decrypted text differs\nexpected: %q\ngot:      %q", expected, decrypted)
+	}
+}
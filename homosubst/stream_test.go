@@ -0,0 +1,93 @@
+//
+// SPDX-FileCopyrightText: Copyright 2026 Frank Schwab
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileType: SOURCE
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+//
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Author: Frank Schwab
+//
+// Version: 1.1.0
+//
+// Change history:
+//    2026-10-18: V1.0.0: Created.
+//    2026-10-18: V1.1.0: Test letters without substitutions.
+//
+
+package homosubst
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+)
+
+// ******** Test functions ********
+
+func TestStreamRoundTrip(t *testing.T) {
+	for _, options := range []Options{{}, {ContextClassCount: 4}} {
+		for _, keepOthers := range []bool{false, true} {
+			s, err := NewSubstitutorFromReader(strings.NewReader(flatnessTestText), options)
+			if err != nil {
+				t.Fatalf(`Error creating substitutor: %v`, err)
+			}
+
+			var encrypted, decrypted bytes.Buffer
+			err = s.EncryptStream(strings.NewReader(flatnessTestText), &encrypted, keepOthers)
+			if err != nil {
+				t.Fatalf(`Error encrypting: %v`, err)
+			}
+
+			err = s.DecryptStream(&encrypted, &decrypted)
+			if err != nil {
+				t.Fatalf(`Error decrypting: %v`, err)
+			}
+
+			expected := expectedClearText(flatnessTestText, keepOthers)
+			if decrypted.String() != expected {
+				t.Fatalf("%d classes, keep=%t: Decrypted text differs\nexpected: %q\ngot:      %q", options.ContextClassCount, keepOthers, expected, decrypted.String())
+			}
+
+			// The file based decryption must give the same result.
+			if got := encryptAndDecrypt(t, s, s, flatnessTestText, keepOthers); got != expected {
+				t.Fatalf("%d classes, keep=%t: File decryption differs\nexpected: %q\ngot:      %q", options.ContextClassCount, keepOthers, expected, got)
+			}
+		}
+	}
+}
+
+func TestStreamNoLetters(t *testing.T) {
+	_, err := NewSubstitutorFromReader(strings.NewReader(`0123456789`), Options{})
+	if !errors.Is(err, ErrNoLetters) {
+		t.Fatalf(`Expected error '%v', got '%v'`, ErrNoLetters, err)
+	}
+}
+
+func TestStreamNoSubstitution(t *testing.T) {
+	for _, options := range []Options{{}, {ContextClassCount: 2}} {
+		s, err := NewSubstitutorFromReader(strings.NewReader(`abc`), options)
+		if err != nil {
+			t.Fatalf(`Error creating substitutor: %v`, err)
+		}
+
+		err = s.EncryptStream(strings.NewReader(`abz`), io.Discard, false)
+		if !errors.Is(err, ErrNoSubstitution) {
+			t.Fatalf(`%d classes: Expected error '%v', got '%v'`, options.ContextClassCount, ErrNoSubstitution, err)
+		}
+	}
+}
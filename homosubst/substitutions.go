@@ -20,23 +20,41 @@
 //
 // Author: Frank Schwab
 //
-// Version: 2.0.0
+// Version: 2.2.0
 //
 // Change history:
 //    2024-09-17: V1.0.0: Created.
 //    2025-01-03: V1.1.0: Use randomlist. Correct rune substitution handling.
 //    2025-02-17: V2.0.0: Handle only bytes.
+//    2026-10-18: V2.1.0: Substitution may depend on the predecessor.
+//    2026-10-18: V2.1.1: Document panic for letters without substitutions.
+//    2026-10-18: V2.2.0: Add access to the homophones of a letter.
 //
 
 package homosubst
 
+import "slices"
+
 // ******** Public functions ********
 
 // SubstituteByte substitutes a byte.
+// If the substitutor has contexts, the substitution depends on the letter substituted before.
+// It panics if there is no substitution for b. The encryption functions return [ErrNoSubstitution] instead.
 func (s *Substitutor) SubstituteByte(b byte) byte {
 	if b >= 'A' && b <= 'Z' {
-		return s.substitutions[b-'A'].RandomElement()
+		return s.selectorFor(b).RandomElement()
 	} else {
 		return b
 	}
 }
+
+// Homophones returns a copy of the substitutions of a letter in the range A-Z.
+// If the substitutor has contexts, these are the substitutions of a letter without a predecessor.
+// It returns nil for other characters.
+func (s *Substitutor) Homophones(letter byte) []byte {
+	if letter < 'A' || letter > 'Z' {
+		return nil
+	}
+
+	return slices.Clone(s.substitutions[letter-'A'].BaseList())
+}
@@ -20,23 +20,91 @@
 //
 // Author: Frank Schwab
 //
-// Version: 1.1.0
+// Version: 1.9.0
 //
 // Change history:
 //    2024-09-17: V1.0.0: Created.
 //    2025-01-03: V1.1.0: Remove unnecessary fields.
+//    2026-10-18: V1.2.0: Add decryption table.
+//    2026-10-18: V1.3.0: Add hash algorithm.
+//    2026-10-18: V1.4.0: Add creation options.
+//    2026-10-18: V1.5.0: Add minimum and maximum substitution lengths to the options.
+//    2026-10-18: V1.6.0: Add selection strategy to the options.
+//    2026-10-18: V1.7.0: Use selector interface for substitution lists. Add weighted selection.
+//    2026-10-18: V1.8.0: Add context-dependent substitutions.
+//    2026-10-18: V1.9.0: Added cipher mode and shift.
 //
 
 // Package homosubst contains the functions the implement a homophonic substitution.
 package homosubst
 
-import "homophone/randomlist"
+import (
+	"homophone/distributor"
+	"homophone/integritycheckedfile"
+	"homophone/randomlist"
+)
 
 // ******** Public types ********
 
 // Substitutor contains the data needed for a homophonic substitution cipher.
 type Substitutor struct {
-	substitutions            []*randomlist.RandomList[byte]
+	substitutions            []randomlist.Selector[byte]
 	proportions              []uint16
+	letterShares             []float64
+	decryptionTable          []byte
 	substitutionAlphabetSize uint16
+	hashAlgorithm            integritycheckedfile.HashAlgorithm
+
+	// Selection of the substitutions. The letter counts are nil, if the source is not known.
+	letterCounts []uint
+	selection    randomlist.Strategy
+	weighted     bool
+
+	// Kind of substitution. Each letter has exactly one substitution, if it is not [Homophonic].
+	mode Mode
+
+	// Context-dependent substitutions. They are nil, if the substitution does not depend on the predecessor.
+	contextClasses          []byte
+	contextSubstitutions    [][]randomlist.Selector[byte]
+	contextShares           [][]float64
+	contextDecryptionTables [][]byte
+	previousLetter          byte
+}
+
+// Options contains the options for the creation of a [Substitutor].
+// The zero value contains the default options.
+type Options struct {
+	// Apportioner distributes the substitution characters among the source characters.
+	// If it is nil, [distributor.DefaultApportioner] is used.
+	Apportioner distributor.Apportioner
+
+	// MinLengths contains the minimum number of substitutions for each character A-Z.
+	// If it is nil, the minimum is 0. Characters that appear in the source always have
+	// at least one substitution.
+	MinLengths []uint
+
+	// MaxLengths contains the maximum number of substitutions for each character A-Z.
+	// If it is nil, there is no maximum.
+	MaxLengths []uint
+
+	// Selection is the strategy for selecting a substitution among the substitutions of a character.
+	// The zero value is [randomlist.ShuffledCycle].
+	Selection randomlist.Strategy
+
+	// Weighted selects the substitutions of a character randomly with weights that are
+	// calculated from the exact quota of the character. Selection is ignored, if this is true.
+	Weighted bool
+
+	// ContextClassCount is the number of predecessor classes for the experimental context-dependent substitution.
+	// If it is greater than 0, each character is substituted with a separate set of substitution lists
+	// for each class of the preceding character. If it is 0, the substitution does not depend on the predecessor.
+	// It can not be combined with Weighted or a Selection other than the default.
+	ContextClassCount int
+
+	// Mode is the kind of substitution. The zero value is [Homophonic].
+	// The other modes can not be combined with an apportioner, lengths, a selection strategy, weighting or contexts.
+	Mode Mode
+
+	// Shift is the number of letters each letter is shifted in the [Caesar] mode. It is used modulo 26.
+	Shift int
 }
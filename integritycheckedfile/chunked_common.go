@@ -0,0 +1,198 @@
+//
+// SPDX-FileCopyrightText: Copyright 2026 Frank Schwab
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileType: SOURCE
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+//
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Author: Frank Schwab
+//
+// Version: 1.0.0
+//
+// Change history:
+//    2026-10-18: V1.0.0: Created.
+//
+
+package integritycheckedfile
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"encoding/binary"
+	"errors"
+	"hash"
+)
+
+// The chunked format has the following layout:
+//
+//	header:  magic "HCIF" || version (byte) || block size (uint32, big endian)
+//	blocks:  data of block i || tag of block i
+//	trailer: final tag
+//
+// All blocks have the block size, except the last one, which may be shorter or even empty.
+// There is always at least one block.
+//
+// The tag of block i is the HMAC of the block index (uint64, big endian),
+// a flag that is 1 for the last block and 0 otherwise, and the block data.
+// The final tag is the HMAC of the header, all block tags, the number of blocks
+// (uint64, big endian) and the additional data.
+//
+// The block tags allow to verify each block on its own. The flag in the tag of the last block
+// and the final tag make it possible to detect truncated and reordered blocks.
+
+// ******** Public constants ********
+
+// DefaultBlockSize is the default size of the data blocks in a chunked file.
+const DefaultBlockSize = 64 * 1_024
+
+// MaxBlockSize is the maximum size of the data blocks in a chunked file.
+const MaxBlockSize = 16 * 1_024 * 1_024
+
+// ErrFileTruncated is returned when a chunked file is shorter than its structure requires.
+var ErrFileTruncated = errors.New(`file is truncated`)
+
+// ErrInvalidBlockSize is returned when a block size is not in the allowed range.
+var ErrInvalidBlockSize = errors.New(`invalid block size`)
+
+// ErrNotChunkedFile is returned when data do not start with the header of a chunked file.
+var ErrNotChunkedFile = errors.New(`not a chunked file`)
+
+// ErrUnknownChunkedVersion is returned when the version of a chunked file is not known.
+var ErrUnknownChunkedVersion = errors.New(`unknown chunked file version`)
+
+// ******** Private constants ********
+
+// chunkedMagic is the magic bytes at the start of a chunked file.
+var chunkedMagic = []byte(`HCIF`)
+
+// chunkedVersion is the version of the chunked format.
+const chunkedVersion byte = 1
+
+// chunkedHeaderSize is the size of the header of a chunked file.
+// It consists of the magic bytes, the version and the block size.
+const chunkedHeaderSize = 4 + 1 + 4
+
+// lastBlockFlag is the flag for the last block.
+const lastBlockFlag = 1
+
+// notLastBlockFlag is the flag for all blocks except the last one.
+const notLastBlockFlag = 0
+
+// ******** Private types ********
+
+// chunkMACs contains the MAC calculators for the chunked format.
+type chunkMACs struct {
+	blockHasher    hash.Hash
+	finalHasher    hash.Hash
+	additionalData []byte
+	blockCount     uint64
+}
+
+// ******** Private creation functions ********
+
+// newChunkMACs creates the MAC calculators for a chunked file with the given header.
+func newChunkMACs(hashFunc func() hash.Hash, key []byte, additionalData []byte, header []byte) *chunkMACs {
+	result := &chunkMACs{
+		blockHasher:    hmac.New(hashFunc, key),
+		finalHasher:    hmac.New(hashFunc, key),
+		additionalData: additionalData,
+	}
+
+	_, _ = result.finalHasher.Write(header)
+
+	return result
+}
+
+// ******** Private type functions ********
+
+// tagSize returns the size of a tag.
+func (c *chunkMACs) tagSize() int {
+	return c.blockHasher.Size()
+}
+
+// blockTag calculates the tag of a block with the given index.
+func (c *chunkMACs) blockTag(index uint64, isLast bool, data []byte) []byte {
+	h := c.blockHasher
+	h.Reset()
+
+	var prefix [9]byte
+	binary.BigEndian.PutUint64(prefix[:8], index)
+	if isLast {
+		prefix[8] = lastBlockFlag
+	} else {
+		prefix[8] = notLastBlockFlag
+	}
+
+	_, _ = h.Write(prefix[:])
+	_, _ = h.Write(data)
+
+	return h.Sum(nil)
+}
+
+// addBlockTag adds a block tag to the final tag.
+func (c *chunkMACs) addBlockTag(tag []byte) {
+	_, _ = c.finalHasher.Write(tag)
+	c.blockCount++
+}
+
+// finalTag calculates the final tag.
+// It must only be called once, after all block tags have been added.
+func (c *chunkMACs) finalTag() []byte {
+	h := c.finalHasher
+	_, _ = h.Write(binary.BigEndian.AppendUint64(nil, c.blockCount))
+	_, _ = h.Write(c.additionalData)
+
+	return h.Sum(nil)
+}
+
+// ******** Private functions ********
+
+// makeChunkedHeader builds the header of a chunked file with the given block size.
+func makeChunkedHeader(blockSize uint32) []byte {
+	header := make([]byte, 0, chunkedHeaderSize)
+	header = append(header, chunkedMagic...)
+	header = append(header, chunkedVersion)
+
+	return binary.BigEndian.AppendUint32(header, blockSize)
+}
+
+// parseChunkedHeader checks the header of a chunked file and returns the block size.
+func parseChunkedHeader(header []byte) (uint32, error) {
+	if !bytes.Equal(header[:len(chunkedMagic)], chunkedMagic) {
+		return 0, ErrNotChunkedFile
+	}
+
+	if header[len(chunkedMagic)] != chunkedVersion {
+		return 0, ErrUnknownChunkedVersion
+	}
+
+	blockSize := binary.BigEndian.Uint32(header[len(chunkedMagic)+1:])
+	err := checkBlockSize(blockSize)
+	if err != nil {
+		return 0, err
+	}
+
+	return blockSize, nil
+}
+
+// checkBlockSize checks if a block size is in the allowed range.
+func checkBlockSize(blockSize uint32) error {
+	if blockSize == 0 || blockSize > MaxBlockSize {
+		return ErrInvalidBlockSize
+	}
+
+	return nil
+}
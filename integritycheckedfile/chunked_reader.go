@@ -0,0 +1,185 @@
+//
+// SPDX-FileCopyrightText: Copyright 2026 Frank Schwab
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileType: SOURCE
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+//
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Author: Frank Schwab
+//
+// Version: 1.0.0
+//
+// Change history:
+//    2026-10-18: V1.0.0: Created.
+//
+
+package integritycheckedfile
+
+import (
+	"bufio"
+	"crypto/subtle"
+	"errors"
+	"hash"
+	"io"
+)
+
+// ******** Public types ********
+
+// ChunkedReader implements a streaming reader for the chunked integrity-checked format.
+// Data are only returned after the block they belong to has been verified.
+// The source is read only once.
+type ChunkedReader struct {
+	source    *bufio.Reader
+	macs      *chunkMACs
+	blockSize int
+	buffer    []byte
+	block     []byte
+	isDone    bool
+	err       error
+}
+
+// ******** Public creation functions ********
+
+// NewChunkedReader creates a new streaming reader for data in the chunked integrity-checked format.
+func NewChunkedReader(source io.Reader, hashFunc func() hash.Hash, key []byte, additionalData []byte) (*ChunkedReader, error) {
+	header := make([]byte, chunkedHeaderSize)
+	_, err := io.ReadFull(source, header)
+	if err != nil {
+		return nil, truncatedOnEOF(err)
+	}
+
+	var blockSize uint32
+	blockSize, err = parseChunkedHeader(header)
+	if err != nil {
+		return nil, err
+	}
+
+	macs := newChunkMACs(hashFunc, key, additionalData, header)
+
+	return &ChunkedReader{
+		source:    bufio.NewReaderSize(source, lookAheadSize(int(blockSize), macs.tagSize())),
+		macs:      macs,
+		blockSize: int(blockSize),
+		buffer:    make([]byte, blockSize),
+	}, nil
+}
+
+// ******** Public functions ********
+
+// Read reads verified data into the provided buffer.
+// It returns [ErrFileCorrupt] or [ErrFileTruncated] as soon as a block fails verification.
+func (r *ChunkedReader) Read(p []byte) (n int, err error) {
+	for len(r.block) == 0 {
+		if r.err != nil {
+			return 0, r.err
+		}
+
+		if r.isDone {
+			return 0, io.EOF
+		}
+
+		r.err = r.readBlock()
+	}
+
+	n = copy(p, r.block)
+	r.block = r.block[n:]
+
+	return n, nil
+}
+
+// ******** Private type functions ********
+
+// readBlock reads and verifies the next block.
+func (r *ChunkedReader) readBlock() error {
+	blockSize := r.blockSize
+	tagSize := r.macs.tagSize()
+	limit := lookAheadSize(blockSize, tagSize)
+
+	peeked, err := r.source.Peek(limit)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return err
+	}
+
+	// If there are more bytes than the largest possible last block and
+	// the final tag, this is a full block that is not the last one.
+	isLast := len(peeked) < limit
+	dataLen := blockSize
+	if isLast {
+		dataLen = len(peeked) - tagSize - tagSize
+		if dataLen < 0 {
+			return ErrFileTruncated
+		}
+	}
+
+	data := peeked[:dataLen]
+	tag := peeked[dataLen : dataLen+tagSize]
+
+	expectedTag := r.macs.blockTag(r.macs.blockCount, isLast, data)
+	if subtle.ConstantTimeCompare(expectedTag, tag) == 0 {
+		if isLast && r.isFullBlock(peeked) {
+			// A valid block that is not the last one is followed by too few bytes.
+			return ErrFileTruncated
+		}
+
+		return ErrFileCorrupt
+	}
+
+	r.macs.addBlockTag(tag)
+
+	if isLast {
+		if subtle.ConstantTimeCompare(r.macs.finalTag(), peeked[dataLen+tagSize:]) == 0 {
+			return ErrFileCorrupt
+		}
+
+		r.isDone = true
+	}
+
+	r.block = r.buffer[:copy(r.buffer, data)]
+
+	_, err = r.source.Discard(dataLen + tagSize)
+
+	return err
+}
+
+// isFullBlock checks if the data start with a valid block that is not the last block.
+func (r *ChunkedReader) isFullBlock(data []byte) bool {
+	blockSize := r.blockSize
+	tagSize := r.macs.tagSize()
+	if len(data) < blockSize+tagSize {
+		return false
+	}
+
+	expectedTag := r.macs.blockTag(r.macs.blockCount, false, data[:blockSize])
+
+	return subtle.ConstantTimeCompare(expectedTag, data[blockSize:blockSize+tagSize]) == 1
+}
+
+// ******** Private functions ********
+
+// lookAheadSize returns the number of bytes that have to be looked at to decide
+// whether a block is the last one.
+func lookAheadSize(blockSize int, tagSize int) int {
+	return blockSize + tagSize + tagSize + 1
+}
+
+// truncatedOnEOF converts an unexpected end of file into [ErrFileTruncated].
+func truncatedOnEOF(err error) error {
+	if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return ErrFileTruncated
+	}
+
+	return err
+}
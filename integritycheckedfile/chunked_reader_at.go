@@ -0,0 +1,326 @@
+//
+// SPDX-FileCopyrightText: Copyright 2026 Frank Schwab
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileType: SOURCE
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+//
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Author: Frank Schwab
+//
+// Version: 1.0.0
+//
+// Change history:
+//    2026-10-18: V1.0.0: Created.
+//
+
+package integritycheckedfile
+
+import (
+	"crypto/subtle"
+	"errors"
+	"hash"
+	"io"
+	"os"
+)
+
+// ******** Public types ********
+
+// ChunkedReaderAt implements a random access reader for the chunked integrity-checked format.
+// The structure of the data is verified on creation by reading only the tags.
+// Each block is verified when it is read.
+type ChunkedReaderAt struct {
+	source      io.ReaderAt
+	closer      io.Closer
+	name        string
+	macs        *chunkMACs
+	tags        []byte
+	blockSize   int64
+	recordSize  int64
+	blockCount  int64
+	dataLen     int64
+	position    int64
+	cachedIndex int64
+	cachedBlock []byte
+}
+
+// ******** Public creation functions ********
+
+// OpenChunkedFile opens a file in the chunked integrity-checked format for random access.
+func OpenChunkedFile(fileName string, hashFunc func() hash.Hash, key []byte, additionalData []byte) (*ChunkedReaderAt, error) {
+	file, err := os.Open(fileName)
+	if err != nil {
+		return nil, err
+	}
+	// Do not defer a close!
+
+	var fi os.FileInfo
+	fi, err = file.Stat()
+	if err != nil {
+		_ = file.Close()
+		return nil, err
+	}
+
+	var result *ChunkedReaderAt
+	result, err = NewChunkedReaderAt(file, fi.Size(), hashFunc, key, additionalData)
+	if err != nil {
+		_ = file.Close()
+		return nil, err
+	}
+
+	// The reader owns the file.
+	result.closer = file
+
+	return result, nil
+}
+
+// NewChunkedReaderAt creates a new random access reader for size bytes of data in the chunked integrity-checked format.
+// Closing the returned reader does not close source.
+func NewChunkedReaderAt(
+	source io.ReaderAt,
+	size int64,
+	hashFunc func() hash.Hash,
+	key []byte,
+	additionalData []byte,
+) (*ChunkedReaderAt, error) {
+	// 1. Read the header.
+	header := make([]byte, chunkedHeaderSize)
+	_, err := source.ReadAt(header, 0)
+	if err != nil {
+		return nil, truncatedOnEOF(err)
+	}
+
+	var blockSize uint32
+	blockSize, err = parseChunkedHeader(header)
+	if err != nil {
+		return nil, err
+	}
+
+	macs := newChunkMACs(hashFunc, key, additionalData, header)
+
+	result := &ChunkedReaderAt{
+		source:      source,
+		name:        nameOf(source),
+		macs:        macs,
+		blockSize:   int64(blockSize),
+		recordSize:  int64(blockSize) + int64(macs.tagSize()),
+		cachedIndex: -1,
+	}
+
+	// 2. Calculate the structure from the size.
+	err = result.calculateLayout(size)
+	if err != nil {
+		return nil, err
+	}
+
+	// 3. Read the tags and verify them with the final tag.
+	err = result.verifyTags(size)
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// ******** Public functions ********
+
+// Size returns the length of the data.
+func (r *ChunkedReaderAt) Size() int64 {
+	return r.dataLen
+}
+
+// ReadAt reads verified data starting at offset off into the provided buffer.
+// It implements the [io.ReaderAt] interface.
+func (r *ChunkedReaderAt) ReadAt(p []byte, off int64) (n int, err error) {
+	if off < 0 {
+		return 0, errors.New(`negative offset`)
+	}
+
+	for len(p) > 0 {
+		if off >= r.dataLen {
+			return n, io.EOF
+		}
+
+		var block []byte
+		block, err = r.readBlock(off / r.blockSize)
+		if err != nil {
+			return
+		}
+
+		copied := copy(p, block[off%r.blockSize:])
+		p = p[copied:]
+		off += int64(copied)
+		n += copied
+	}
+
+	return
+}
+
+// Read reads verified data from the current position into the provided buffer.
+func (r *ChunkedReaderAt) Read(p []byte) (n int, err error) {
+	if r.position >= r.dataLen {
+		return 0, io.EOF
+	}
+
+	n, err = r.ReadAt(p, r.position)
+	r.position += int64(n)
+	if n > 0 && errors.Is(err, io.EOF) {
+		err = nil
+	}
+
+	return
+}
+
+// Seek sets the position for the next Read to offset,
+// interpreted according to whence.
+func (r *ChunkedReaderAt) Seek(offset int64, whence int) (int64, error) {
+	switch whence {
+	case io.SeekStart:
+	case io.SeekCurrent:
+		offset += r.position
+	case io.SeekEnd:
+		offset += r.dataLen
+	default:
+		return 0, errors.New(`invalid whence`)
+	}
+
+	if offset < 0 {
+		return 0, errors.New(`negative position`)
+	}
+
+	r.position = offset
+
+	return offset, nil
+}
+
+// Close closes the file.
+// If the reader has been created from an [io.ReaderAt] the source is not closed.
+func (r *ChunkedReaderAt) Close() error {
+	if r.closer != nil {
+		err := r.closer.Close()
+		if err != nil {
+			return err
+		}
+	}
+
+	r.source = nil
+	r.closer = nil
+	r.tags = nil
+	r.cachedBlock = nil
+	r.cachedIndex = -1
+
+	return nil
+}
+
+// Name returns the name of the underlying file.
+func (r *ChunkedReaderAt) Name() string {
+	return r.name
+}
+
+// ******** Private type functions ********
+
+// calculateLayout calculates the number of blocks and the data length from the size.
+func (r *ChunkedReaderAt) calculateLayout(size int64) error {
+	tagSize := int64(r.macs.tagSize())
+
+	bodySize := size - chunkedHeaderSize - tagSize
+	if bodySize < tagSize {
+		return ErrFileTruncated
+	}
+
+	fullRecords := bodySize / r.recordSize
+	rest := bodySize % r.recordSize
+	if rest == 0 {
+		r.blockCount = fullRecords
+		r.dataLen = fullRecords * r.blockSize
+		return nil
+	}
+
+	if rest < tagSize {
+		return ErrFileTruncated
+	}
+
+	r.blockCount = fullRecords + 1
+	r.dataLen = fullRecords*r.blockSize + rest - tagSize
+
+	return nil
+}
+
+// verifyTags reads all block tags and checks them against the final tag.
+func (r *ChunkedReaderAt) verifyTags(size int64) error {
+	tagSize := r.macs.tagSize()
+	r.tags = make([]byte, int(r.blockCount)*tagSize)
+
+	for i := range r.blockCount {
+		tag := r.tags[int(i)*tagSize : int(i+1)*tagSize]
+		_, err := r.source.ReadAt(tag, chunkedHeaderSize+i*r.recordSize+r.blockLen(i))
+		if err != nil {
+			return truncatedOnEOF(err)
+		}
+
+		r.macs.addBlockTag(tag)
+	}
+
+	finalTag := make([]byte, tagSize)
+	_, err := r.source.ReadAt(finalTag, size-int64(tagSize))
+	if err != nil {
+		return truncatedOnEOF(err)
+	}
+
+	if subtle.ConstantTimeCompare(r.macs.finalTag(), finalTag) == 0 {
+		return ErrFileCorrupt
+	}
+
+	return nil
+}
+
+// blockLen returns the data length of the block with the given index.
+func (r *ChunkedReaderAt) blockLen(index int64) int64 {
+	if index == r.blockCount-1 {
+		return r.dataLen - index*r.blockSize
+	}
+
+	return r.blockSize
+}
+
+// readBlock reads and verifies the block with the given index.
+// The last block read is cached.
+func (r *ChunkedReaderAt) readBlock(index int64) ([]byte, error) {
+	if index == r.cachedIndex {
+		return r.cachedBlock, nil
+	}
+
+	if r.cachedBlock == nil {
+		r.cachedBlock = make([]byte, r.blockSize)
+	}
+
+	r.cachedIndex = -1
+	block := r.cachedBlock[:r.blockLen(index)]
+	_, err := r.source.ReadAt(block, chunkedHeaderSize+index*r.recordSize)
+	if err != nil {
+		return nil, truncatedOnEOF(err)
+	}
+
+	tagSize := int64(r.macs.tagSize())
+	expectedTag := r.macs.blockTag(uint64(index), index == r.blockCount-1, block)
+	if subtle.ConstantTimeCompare(expectedTag, r.tags[index*tagSize:(index+1)*tagSize]) == 0 {
+		return nil, ErrFileCorrupt
+	}
+
+	r.cachedIndex = index
+	r.cachedBlock = block
+
+	return block, nil
+}
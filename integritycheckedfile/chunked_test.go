@@ -0,0 +1,289 @@
+//
+// SPDX-FileCopyrightText: Copyright 2026 Frank Schwab
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileType: SOURCE
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+//
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Author: Frank Schwab
+//
+// Version: 1.0.0
+//
+// Change history:
+//    2026-10-18: V1.0.0: Created.
+//
+
+package integritycheckedfile
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"errors"
+	"io"
+	"math/rand/v2"
+	"testing"
+)
+
+// ******** Private constants ********
+
+const testBlockSize = 16
+
+var testKey = []byte(`0123456789abcdef0123456789abcdef`)
+
+var testAdditionalData = []byte(`additional`)
+
+var testDataLengths = []int{0, 1, testBlockSize - 1, testBlockSize, testBlockSize + 1, 3 * testBlockSize, 3*testBlockSize + 7}
+
+// ******** Test functions ********
+
+func TestChunkedStreamRoundTrip(t *testing.T) {
+	for _, dataLen := range testDataLengths {
+		data := randomBytes(dataLen)
+		chunked := writeChunked(t, data)
+
+		r, err := NewChunkedReader(bytes.NewReader(chunked), sha256.New, testKey, testAdditionalData)
+		if err != nil {
+			t.Fatalf(`Length %d: Error creating reader: %v`, dataLen, err)
+		}
+
+		var result []byte
+		result, err = io.ReadAll(r)
+		if err != nil {
+			t.Fatalf(`Length %d: Error reading: %v`, dataLen, err)
+		}
+
+		if !bytes.Equal(data, result) {
+			t.Fatalf(`Length %d: Read data differ from written data`, dataLen)
+		}
+	}
+}
+
+func TestChunkedReaderAtRoundTrip(t *testing.T) {
+	for _, dataLen := range testDataLengths {
+		data := randomBytes(dataLen)
+		chunked := writeChunked(t, data)
+
+		r, err := NewChunkedReaderAt(bytes.NewReader(chunked), int64(len(chunked)), sha256.New, testKey, testAdditionalData)
+		if err != nil {
+			t.Fatalf(`Length %d: Error creating reader: %v`, dataLen, err)
+		}
+
+		if r.Size() != int64(dataLen) {
+			t.Fatalf(`Length %d: Expected size %d, got %d`, dataLen, dataLen, r.Size())
+		}
+
+		var result []byte
+		result, err = io.ReadAll(r)
+		if err != nil {
+			t.Fatalf(`Length %d: Error reading: %v`, dataLen, err)
+		}
+
+		if !bytes.Equal(data, result) {
+			t.Fatalf(`Length %d: Read data differ from written data`, dataLen)
+		}
+
+		// Random access.
+		for range 20 {
+			if dataLen == 0 {
+				break
+			}
+
+			off := rand.IntN(dataLen)
+			part := make([]byte, rand.IntN(dataLen-off)+1)
+			_, err = r.ReadAt(part, int64(off))
+			if err != nil && !errors.Is(err, io.EOF) {
+				t.Fatalf(`Length %d: Error reading at %d: %v`, dataLen, off, err)
+			}
+
+			if !bytes.Equal(data[off:off+len(part)], part) {
+				t.Fatalf(`Length %d: Data read at %d differ from written data`, dataLen, off)
+			}
+		}
+	}
+}
+
+func TestChunkedCorrupt(t *testing.T) {
+	data := randomBytes(3 * testBlockSize)
+	chunked := writeChunked(t, data)
+	chunked[chunkedHeaderSize+testBlockSize+sha256.Size+1] ^= 1
+
+	r, err := NewChunkedReader(bytes.NewReader(chunked), sha256.New, testKey, testAdditionalData)
+	if err != nil {
+		t.Fatalf(`Error creating reader: %v`, err)
+	}
+
+	var result []byte
+	result, err = io.ReadAll(r)
+	if !errors.Is(err, ErrFileCorrupt) {
+		t.Fatalf(`Expected error '%v', got '%v'`, ErrFileCorrupt, err)
+	}
+
+	if !bytes.Equal(data[:testBlockSize], result) {
+		t.Fatal(`Verified block before corrupt block has not been returned`)
+	}
+
+	var ra *ChunkedReaderAt
+	ra, err = NewChunkedReaderAt(bytes.NewReader(chunked), int64(len(chunked)), sha256.New, testKey, testAdditionalData)
+	if err != nil {
+		t.Fatalf(`Error creating reader: %v`, err)
+	}
+
+	_, err = ra.ReadAt(make([]byte, 1), testBlockSize)
+	if !errors.Is(err, ErrFileCorrupt) {
+		t.Fatalf(`Expected error '%v', got '%v'`, ErrFileCorrupt, err)
+	}
+}
+
+func TestChunkedTruncated(t *testing.T) {
+	data := randomBytes(3 * testBlockSize)
+	chunked := writeChunked(t, data)
+
+	// Cut off after the first block.
+	truncated := chunked[:chunkedHeaderSize+testBlockSize+sha256.Size]
+
+	r, err := NewChunkedReader(bytes.NewReader(truncated), sha256.New, testKey, testAdditionalData)
+	if err != nil {
+		t.Fatalf(`Error creating reader: %v`, err)
+	}
+
+	_, err = io.ReadAll(r)
+	if !errors.Is(err, ErrFileTruncated) {
+		t.Fatalf(`Expected error '%v', got '%v'`, ErrFileTruncated, err)
+	}
+
+	_, err = NewChunkedReaderAt(bytes.NewReader(truncated), int64(len(truncated)), sha256.New, testKey, testAdditionalData)
+	if !errors.Is(err, ErrFileTruncated) {
+		t.Fatalf(`Expected error '%v', got '%v'`, ErrFileTruncated, err)
+	}
+
+	// Cut off the last block, but keep the final tag.
+	recordSize := testBlockSize + sha256.Size
+	withoutLastBlock := append(bytes.Clone(chunked[:chunkedHeaderSize+2*recordSize]), chunked[len(chunked)-sha256.Size:]...)
+	_, err = NewChunkedReaderAt(bytes.NewReader(withoutLastBlock), int64(len(withoutLastBlock)), sha256.New, testKey, testAdditionalData)
+	if !errors.Is(err, ErrFileCorrupt) {
+		t.Fatalf(`Expected error '%v', got '%v'`, ErrFileCorrupt, err)
+	}
+
+	_, err = NewChunkedReaderAt(bytes.NewReader(chunked[:3]), 3, sha256.New, testKey, testAdditionalData)
+	if !errors.Is(err, ErrFileTruncated) {
+		t.Fatalf(`Expected error '%v', got '%v'`, ErrFileTruncated, err)
+	}
+}
+
+func TestChunkedHeader(t *testing.T) {
+	chunked := writeChunked(t, randomBytes(testBlockSize))
+
+	if !bytes.HasPrefix(chunked, chunkedMagic) {
+		t.Fatalf(`Chunked data do not start with the magic bytes: %x`, chunked[:chunkedHeaderSize])
+	}
+
+	otherMagic := bytes.Clone(chunked)
+	otherMagic[0] ^= 1
+	checkHeaderError(t, otherMagic, ErrNotChunkedFile)
+
+	otherVersion := bytes.Clone(chunked)
+	otherVersion[len(chunkedMagic)]++
+	checkHeaderError(t, otherVersion, ErrUnknownChunkedVersion)
+
+	otherBlockSize := bytes.Clone(chunked)
+	otherBlockSize[chunkedHeaderSize-1] ^= 1
+	r, err := NewChunkedReader(bytes.NewReader(otherBlockSize), sha256.New, testKey, testAdditionalData)
+	if err != nil {
+		t.Fatalf(`Error creating reader: %v`, err)
+	}
+
+	_, err = io.ReadAll(r)
+	if err == nil {
+		t.Fatal(`Modified block size has not been detected`)
+	}
+}
+
+func TestChunkedReordered(t *testing.T) {
+	data := randomBytes(3 * testBlockSize)
+	chunked := writeChunked(t, data)
+
+	// Swap the first two blocks.
+	recordSize := testBlockSize + sha256.Size
+	first := chunked[chunkedHeaderSize : chunkedHeaderSize+recordSize]
+	second := chunked[chunkedHeaderSize+recordSize : chunkedHeaderSize+2*recordSize]
+	reordered := bytes.Clone(chunked)
+	copy(reordered[chunkedHeaderSize:], second)
+	copy(reordered[chunkedHeaderSize+recordSize:], first)
+
+	r, err := NewChunkedReader(bytes.NewReader(reordered), sha256.New, testKey, testAdditionalData)
+	if err != nil {
+		t.Fatalf(`Error creating reader: %v`, err)
+	}
+
+	_, err = io.ReadAll(r)
+	if !errors.Is(err, ErrFileCorrupt) {
+		t.Fatalf(`Expected error '%v', got '%v'`, ErrFileCorrupt, err)
+	}
+}
+
+// ******** Private functions ********
+
+// writeChunked writes data in the chunked format.
+func writeChunked(t *testing.T, data []byte) []byte {
+	t.Helper()
+
+	var buffer bytes.Buffer
+	w, err := NewChunkedWriter(&buffer, testBlockSize, sha256.New, testKey, testAdditionalData)
+	if err != nil {
+		t.Fatalf(`Error creating writer: %v`, err)
+	}
+
+	// Write in pieces of random size.
+	rest := data
+	for len(rest) > 0 {
+		n := rand.IntN(len(rest)) + 1
+		_, err = w.Write(rest[:n])
+		if err != nil {
+			t.Fatalf(`Error writing: %v`, err)
+		}
+
+		rest = rest[n:]
+	}
+
+	err = w.Close()
+	if err != nil {
+		t.Fatalf(`Error closing writer: %v`, err)
+	}
+
+	return buffer.Bytes()
+}
+
+// randomBytes returns a slice of random bytes.
+func checkHeaderError(t *testing.T, chunked []byte, expected error) {
+	_, err := NewChunkedReader(bytes.NewReader(chunked), sha256.New, testKey, testAdditionalData)
+	if !errors.Is(err, expected) {
+		t.Fatalf(`Expected error '%v', got '%v'`, expected, err)
+	}
+
+	_, err = NewChunkedReaderAt(bytes.NewReader(chunked), int64(len(chunked)), sha256.New, testKey, testAdditionalData)
+	if !errors.Is(err, expected) {
+		t.Fatalf(`Expected error '%v', got '%v'`, expected, err)
+	}
+}
+
+func randomBytes(n int) []byte {
+	result := make([]byte, n)
+	for i := range result {
+		result[i] = byte(rand.UintN(256))
+	}
+
+	return result
+}
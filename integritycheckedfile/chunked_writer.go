@@ -0,0 +1,151 @@
+//
+// SPDX-FileCopyrightText: Copyright 2026 Frank Schwab
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileType: SOURCE
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+//
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Author: Frank Schwab
+//
+// Version: 1.0.0
+//
+// Change history:
+//    2026-10-18: V1.0.0: Created.
+//
+
+package integritycheckedfile
+
+import (
+	"hash"
+	"homophone/slicehelper"
+	"io"
+	"os"
+	"slices"
+)
+
+// ******** Public types ********
+
+// ChunkedWriter implements a writer for the chunked integrity-checked format.
+// Each block of data gets its own tag, so the data can be verified while they are read.
+type ChunkedWriter struct {
+	destination io.Writer
+	macs        *chunkMACs
+	block       []byte
+	blockSize   int
+}
+
+// ******** Public creation functions ********
+
+// NewChunkedWriter creates a new writer for the chunked integrity-checked format with the given block size.
+// The header is written immediately.
+// Closing the returned writer writes the last block and the final tag, but does not close destination.
+func NewChunkedWriter(
+	destination io.Writer,
+	blockSize int,
+	hashFunc func() hash.Hash,
+	key []byte,
+	additionalData []byte,
+) (*ChunkedWriter, error) {
+	if blockSize <= 0 || blockSize > MaxBlockSize {
+		return nil, ErrInvalidBlockSize
+	}
+
+	header := makeChunkedHeader(uint32(blockSize))
+	_, err := destination.Write(header)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ChunkedWriter{
+		destination: destination,
+		macs:        newChunkMACs(hashFunc, key, slices.Clone(additionalData), header),
+		block:       make([]byte, 0, blockSize),
+		blockSize:   blockSize,
+	}, nil
+}
+
+// ******** Public functions ********
+
+// Write writes the supplied data.
+// Data are written to the destination in whole blocks.
+func (w *ChunkedWriter) Write(p []byte) (n int, err error) {
+	if w.destination == nil {
+		return 0, os.ErrClosed
+	}
+
+	for len(p) > 0 {
+		// A full block is only written when more data follow,
+		// as it may be the last block, otherwise.
+		if len(w.block) == w.blockSize {
+			err = w.writeBlock(false)
+			if err != nil {
+				return
+			}
+		}
+
+		copied := copy(w.block[len(w.block):w.blockSize], p)
+		w.block = w.block[:len(w.block)+copied]
+		p = p[copied:]
+		n += copied
+	}
+
+	return
+}
+
+// Close writes the last block and the final tag.
+func (w *ChunkedWriter) Close() error {
+	if w.destination == nil {
+		return os.ErrClosed
+	}
+
+	err := w.writeBlock(true)
+	if err != nil {
+		return err
+	}
+
+	_, err = w.destination.Write(w.macs.finalTag())
+	if err != nil {
+		return err
+	}
+
+	w.destination = nil
+	slicehelper.ClearNumber(w.block)
+	w.block = nil
+
+	return nil
+}
+
+// ******** Private type functions ********
+
+// writeBlock writes the current block and its tag.
+func (w *ChunkedWriter) writeBlock(isLast bool) error {
+	tag := w.macs.blockTag(w.macs.blockCount, isLast, w.block)
+
+	_, err := w.destination.Write(w.block)
+	if err != nil {
+		return err
+	}
+
+	_, err = w.destination.Write(tag)
+	if err != nil {
+		return err
+	}
+
+	w.macs.addBlockTag(tag)
+	w.block = w.block[:0]
+
+	return nil
+}
@@ -0,0 +1,117 @@
+//
+// SPDX-FileCopyrightText: Copyright 2026 Frank Schwab
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileType: SOURCE
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+//
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Author: Frank Schwab
+//
+// Version: 1.1.0
+//
+// Change history:
+//    2026-10-18: V1.0.0: Created.
+//    2026-10-18: V1.1.0: Use name table.
+//
+
+package integritycheckedfile
+
+import (
+	"crypto/sha256"
+	"crypto/sha512"
+	"errors"
+	"fmt"
+	"golang.org/x/crypto/blake2b"
+	"golang.org/x/crypto/sha3"
+	"hash"
+	"homophone/nametable"
+)
+
+// ******** Public types ********
+
+// HashAlgorithm identifies a hash algorithm that can be used for the integrity check.
+// Its value is stored in file headers, so the values must never change.
+type HashAlgorithm byte
+
+// ******** Public constants ********
+
+// Known hash algorithms.
+const (
+	HashSHA3_256 HashAlgorithm = 1
+	HashSHA256   HashAlgorithm = 2
+	HashSHA512   HashAlgorithm = 3
+	HashBLAKE2b  HashAlgorithm = 4
+)
+
+// ErrUnknownHashAlgorithm is returned when a hash algorithm is not known.
+var ErrUnknownHashAlgorithm = errors.New(`unknown hash algorithm`)
+
+// ******** Private constants ********
+
+// hashAlgorithmNames contains the names of the hash algorithms in the order of their identifiers.
+var hashAlgorithmNames = nametable.Table[HashAlgorithm]{
+	{Value: HashSHA3_256, Name: `sha3-256`},
+	{Value: HashSHA256, Name: `sha-256`},
+	{Value: HashSHA512, Name: `sha-512`},
+	{Value: HashBLAKE2b, Name: `blake2b`},
+}
+
+// hashAlgorithmFuncs maps the hash algorithms to their hash factories.
+var hashAlgorithmFuncs = map[HashAlgorithm]func() hash.Hash{
+	HashSHA3_256: sha3.New256,
+	HashSHA256:   sha256.New,
+	HashSHA512:   sha512.New,
+	HashBLAKE2b:  newBLAKE2b,
+}
+
+// ******** Public functions ********
+
+// ParseHashAlgorithm returns the hash algorithm with the given name.
+func ParseHashAlgorithm(name string) (HashAlgorithm, error) {
+	return hashAlgorithmNames.Parse(name, ErrUnknownHashAlgorithm)
+}
+
+// HashAlgorithmNames returns the names of all known hash algorithms in the order of their identifiers.
+func HashAlgorithmNames() []string {
+	return hashAlgorithmNames.Names()
+}
+
+// ******** Public type functions ********
+
+// HashFunc returns the hash factory of the hash algorithm.
+func (a HashAlgorithm) HashFunc() (func() hash.Hash, error) {
+	result, found := hashAlgorithmFuncs[a]
+	if !found {
+		return nil, fmt.Errorf(`%d: %w`, a, ErrUnknownHashAlgorithm)
+	}
+
+	return result, nil
+}
+
+// String returns the name of the hash algorithm.
+func (a HashAlgorithm) String() string {
+	return hashAlgorithmNames.Name(a)
+}
+
+// ******** Private functions ********
+
+// newBLAKE2b returns a new unkeyed BLAKE2b-512 hash.
+// Keying is done by HMAC, so no key is needed here.
+func newBLAKE2b() hash.Hash {
+	// An error can only occur with a key that is too long.
+	h, _ := blake2b.New512(nil)
+	return h
+}
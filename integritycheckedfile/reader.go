@@ -20,11 +20,12 @@
 //
 // Author: Frank Schwab
 //
-// Version: 1.1.0
+// Version: 1.2.0
 //
 // Change history:
 //    2024-09-17: V1.0.0: Created.
 //    2025-01-03: V1.1.0: Add Name function.
+//    2026-10-18: V1.2.0: Add creation from an io.ReadSeeker.
 //
 
 package integritycheckedfile
@@ -42,7 +43,9 @@ import (
 
 // Reader implements a read for an integrity-checked file.
 type Reader struct {
-	file     *os.File
+	source   io.ReadSeeker
+	closer   io.Closer
+	name     string
 	dataLen  int64
 	position int64
 }
@@ -57,6 +60,9 @@ var ErrFileCorrupt = errors.New(`file is corrupt`)
 // readBufferSize is the buffer size used for the check of the file.
 const readBufferSize = 1_024 << 2
 
+// streamName is the name returned by [Reader.Name] and [Writer.Name] for sources and destinations without a name.
+const streamName = `<stream>`
+
 // ******** Public creation functions ********
 
 // NewReader creates a new integrity-checked file reader.
@@ -68,34 +74,58 @@ func NewReader(fileName string, hashFunc func() hash.Hash, key []byte, additiona
 	}
 	// Do not defer a close!
 
-	// 2. Get file size.
-	var fi os.FileInfo
-	fi, err = file.Stat()
+	// 2. Check the file.
+	var result *Reader
+	result, err = NewReaderFromReadSeeker(file, hashFunc, key, additionalData)
+	if err != nil {
+		_ = file.Close()
+		return nil, err
+	}
+
+	// 3. The reader owns the file.
+	result.closer = file
+
+	return result, nil
+}
+
+// NewReaderFromReadSeeker creates a new integrity-checked reader for the data in source.
+// The data are read from the start of source.
+// Closing the returned reader does not close source.
+func NewReaderFromReadSeeker(source io.ReadSeeker, hashFunc func() hash.Hash, key []byte, additionalData []byte) (*Reader, error) {
+	// 1. Get source size.
+	size, err := source.Seek(0, io.SeekEnd)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = source.Seek(0, io.SeekStart)
 	if err != nil {
 		return nil, err
 	}
 
-	// 3. Calculate length of data without the checksum.
+	// 2. Calculate length of data without the checksum.
 	hasher := hmac.New(hashFunc, key)
-	dataLen := fi.Size() - int64(hasher.Size())
+	dataLen := size - int64(hasher.Size())
+	if dataLen < 0 {
+		return nil, ErrFileCorrupt
+	}
 
-	// 4. Check, if the checksum matches the file data.
-	err = checkFileIntegrity(file, dataLen, hasher, additionalData)
+	// 3. Check, if the checksum matches the data.
+	err = checkFileIntegrity(source, dataLen, hasher, additionalData)
 	if err != nil {
-		_ = file.Close()
 		return nil, err
 	}
 
-	// 5. Reset file position.
-	_, err = file.Seek(0, 0)
+	// 4. Reset source position.
+	_, err = source.Seek(0, io.SeekStart)
 	if err != nil {
-		_ = file.Close()
 		return nil, err
 	}
 
 	// All done. Return reader.
 	return &Reader{
-		file:     file,
+		source:   source,
+		name:     nameOf(source),
 		dataLen:  dataLen,
 		position: 0,
 	}, nil
@@ -113,7 +143,7 @@ func (r *Reader) DataLen() int64 {
 // 1 means relative to the current offset, and 2 means relative to the end.
 // It returns the new offset and an error, if any.
 func (r *Reader) Seek(offset int64, whence int) (ret int64, err error) {
-	ret, err = r.file.Seek(offset, whence)
+	ret, err = r.source.Seek(offset, whence)
 	r.position = ret
 
 	return
@@ -133,7 +163,7 @@ func (r *Reader) Read(p []byte) (n int, err error) {
 		pLen = int(r.dataLen - r.position)
 	}
 
-	n, err = r.file.Read(p[:pLen])
+	n, err = r.source.Read(p[:pLen])
 	if err != nil {
 		return
 	}
@@ -144,15 +174,19 @@ func (r *Reader) Read(p []byte) (n int, err error) {
 }
 
 // Close closes the file.
+// If the reader has been created from an [io.ReadSeeker] the source is not closed.
 func (r *Reader) Close() error {
 	// 1. Close the file.
-	err := r.file.Close()
-	if err != nil {
-		return err
+	if r.closer != nil {
+		err := r.closer.Close()
+		if err != nil {
+			return err
+		}
 	}
 
 	// 2. Destroy all data in the reader struct.
-	r.file = nil
+	r.source = nil
+	r.closer = nil
 	r.position = 0
 	r.dataLen = 0
 
@@ -161,13 +195,13 @@ func (r *Reader) Close() error {
 
 // Name returns the name of the underlying file.
 func (r *Reader) Name() string {
-	return r.file.Name()
+	return r.name
 }
 
 // ******** Private functions ********
 
 // checkFileIntegrity checks if the file has the correct checksum.
-func checkFileIntegrity(file *os.File, dataLength int64, hasher hash.Hash, additionalData []byte) error {
+func checkFileIntegrity(file io.Reader, dataLength int64, hasher hash.Hash, additionalData []byte) error {
 	buffer := make([]byte, readBufferSize)
 
 	var n int
@@ -198,7 +232,7 @@ func checkFileIntegrity(file *os.File, dataLength int64, hasher hash.Hash, addit
 	}
 
 	hashSize := hasher.Size()
-	n, err = file.Read(buffer[:hashSize])
+	_, err = io.ReadFull(file, buffer[:hashSize])
 	if err != nil {
 		return err
 	}
@@ -209,3 +243,12 @@ func checkFileIntegrity(file *os.File, dataLength int64, hasher hash.Hash, addit
 
 	return nil
 }
+
+// nameOf returns the name of v, if it has one, or [streamName], if it has none.
+func nameOf(v any) string {
+	if named, ok := v.(interface{ Name() string }); ok {
+		return named.Name()
+	}
+
+	return streamName
+}
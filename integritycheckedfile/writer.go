@@ -20,11 +20,14 @@
 //
 // Author: Frank Schwab
 //
-// Version: 1.1.0
+// Version: 1.4.0
 //
 // Change history:
 //    2024-09-17: V1.0.0: Created.
 //    2025-01-03: V1.1.0: Add Name function.
+//    2026-10-18: V1.2.0: Add creation from an io.Writer.
+//    2026-10-18: V1.3.0: Write files atomically and add Abort function.
+//    2026-10-18: V1.4.0: File permissions are specified by the caller.
 //
 
 package integritycheckedfile
@@ -32,7 +35,9 @@ package integritycheckedfile
 import (
 	"crypto/hmac"
 	"hash"
+	"homophone/filehelper"
 	"homophone/slicehelper"
+	"io"
 	"os"
 	"slices"
 )
@@ -41,25 +46,48 @@ import (
 
 // Writer implements a writer for an integrity-checked file.
 type Writer struct {
-	file           *os.File
+	destination    io.Writer
+	file           *filehelper.AtomicFile
+	name           string
 	hasher         hash.Hash
 	additionalData []byte
 }
 
 // ******** Public creation functions ********
 
-// NewWriter creates a new writer for an integrity-checked file.
-func NewWriter(fileName string, hashFunc func() hash.Hash, key []byte, additionalData []byte) (*Writer, error) {
-	file, err := os.Create(fileName)
+// NewWriter creates a new writer for an integrity-checked file with the permissions perm.
+// The file is only written when the writer is closed. If overwrite is false
+// and the file already exists, an [filehelper.ErrFileExists] error is returned.
+func NewWriter(
+	fileName string,
+	perm os.FileMode,
+	overwrite bool,
+	hashFunc func() hash.Hash,
+	key []byte,
+	additionalData []byte,
+) (*Writer, error) {
+	file, err := filehelper.CreateAtomic(fileName, perm, overwrite)
 	if err != nil {
 		return nil, err
 	}
 
+	result := NewWriterFromWriter(file, hashFunc, key, additionalData)
+
+	// The writer owns the file.
+	result.file = file
+
+	return result, nil
+}
+
+// NewWriterFromWriter creates a new integrity-checked writer that writes to destination.
+// Closing the returned writer writes the checksum, but does not close destination.
+func NewWriterFromWriter(destination io.Writer, hashFunc func() hash.Hash, key []byte, additionalData []byte) *Writer {
 	return &Writer{
-		file:           file,
+		destination:    destination,
+		name:           nameOf(destination),
 		hasher:         hmac.New(hashFunc, key),
 		additionalData: slices.Clone(additionalData),
-	}, nil
+	}
 }
 
 // ******** Public functions ********
@@ -71,50 +99,72 @@ func (w *Writer) Write(p []byte) (n int, err error) {
 		return
 	}
 
-	return w.file.Write(p)
+	return w.destination.Write(p)
 }
 
 // WriteString writes a string to the file.
 func (w *Writer) WriteString(s string) (n int, err error) {
-	n, err = w.hasher.Write([]byte(s))
-	if err != nil {
-		return
-	}
-
-	return w.file.WriteString(s)
+	return w.Write([]byte(s))
 }
 
-// Close closes the file.
+// Close writes the checksum and closes the file.
+// A file created by [NewWriter] is moved to its final name.
+// If the writer has been created from an [io.Writer] the destination is not closed.
 func (w *Writer) Close() error {
 	// 1. Hash additional data.
 	hasher := w.hasher
 	_, err := hasher.Write(w.additionalData)
 	if err != nil {
+		_ = w.Abort()
 		return err
 	}
 
 	// 2. Write checksum after data.
-	file := w.file
-	_, err = file.Write(hasher.Sum(nil))
+	_, err = w.destination.Write(hasher.Sum(nil))
 	if err != nil {
+		_ = w.Abort()
 		return err
 	}
 
-	// 3. Close the file.
-	err = w.file.Close()
-	if err != nil {
-		return err
+	// 3. Move the file to its final name.
+	if w.file != nil {
+		err = w.file.Commit()
+		if err != nil {
+			w.destroy()
+			return err
+		}
 	}
 
 	// 4. Destroy all data in the [Writer] struct.
-	w.file = nil
-	w.hasher = nil
-	slicehelper.ClearNumber(w.additionalData)
+	w.destroy()
 
 	return nil
 }
 
+// Abort discards all data written so far without writing a checksum.
+// A file created by [NewWriter] is removed and an existing file is left untouched.
+func (w *Writer) Abort() error {
+	var err error
+	if w.file != nil {
+		err = w.file.Close()
+	}
+
+	w.destroy()
+
+	return err
+}
+
 // Name returns the name of the underlying file.
 func (w *Writer) Name() string {
-	return w.file.Name()
+	return w.name
+}
+
+// ******** Private type functions ********
+
+// destroy destroys all data in the [Writer] struct.
+func (w *Writer) destroy() {
+	w.destination = nil
+	w.file = nil
+	w.hasher = nil
+	slicehelper.ClearNumber(w.additionalData)
 }
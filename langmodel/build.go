@@ -0,0 +1,187 @@
+//
+// SPDX-FileCopyrightText: Copyright 2026 Frank Schwab
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileType: SOURCE
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+//
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Author: Frank Schwab
+//
+// Version: 1.0.0
+//
+// Change history:
+//    2026-10-18: V1.0.0: Created.
+//
+
+package langmodel
+
+import (
+	"bufio"
+	"errors"
+	"homophone/filehelper"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// ******** Public types ********
+
+// Builder counts the trigrams and quadgrams of a text corpus and builds a [Model] from them.
+type Builder struct {
+	trigramCounts  []uint64
+	quadgramCounts []uint64
+	trigramTotal   uint64
+	quadgramTotal  uint64
+	letterTotal    uint64
+
+	// The index of the last letters of the current document and their number.
+	index       int
+	letterCount int
+}
+
+// ******** Public constants ********
+
+// ErrEmptyCorpus is returned when a model is built from a corpus without quadgrams.
+var ErrEmptyCorpus = errors.New(`corpus contains no quadgrams`)
+
+// ******** Public creation functions ********
+
+// NewBuilder creates a builder for an empty corpus.
+func NewBuilder() *Builder {
+	return &Builder{
+		trigramCounts:  make([]uint64, TrigramCount),
+		quadgramCounts: make([]uint64, QuadgramCount),
+	}
+}
+
+// ******** Public type functions ********
+
+// Write adds the letters of p to the current document. It implements the [io.Writer] interface and never returns an error.
+// Characters that are not letters are skipped, so they do not separate n-grams.
+func (b *Builder) Write(p []byte) (int, error) {
+	for _, c := range p {
+		letter, ok := letterIndex(c)
+		if !ok {
+			continue
+		}
+
+		b.index = (b.index%TrigramCount)*LetterCount + letter
+		b.letterCount++
+		b.letterTotal++
+
+		if b.letterCount >= 3 {
+			b.trigramCounts[b.index%TrigramCount]++
+			b.trigramTotal++
+		}
+
+		if b.letterCount >= 4 {
+			b.quadgramCounts[b.index]++
+			b.quadgramTotal++
+		}
+	}
+
+	return len(p), nil
+}
+
+// Break ends the current document, so no n-gram spans the end of this document and the start of the next one.
+func (b *Builder) Break() {
+	b.index = 0
+	b.letterCount = 0
+}
+
+// AddReader adds the text that is read from r as a new document.
+func (b *Builder) AddReader(r io.Reader) error {
+	b.Break()
+
+	_, err := io.Copy(b, bufio.NewReader(r))
+
+	return err
+}
+
+// AddPath adds the file with the given path as a new document.
+// If the path is a directory, all regular files in it and its subdirectories are added.
+// It returns the number of added files.
+func (b *Builder) AddPath(path string) (int, error) {
+	fileCount := 0
+	err := filepath.WalkDir(path, func(filePath string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if !entry.Type().IsRegular() {
+			return nil
+		}
+
+		err = b.addFile(filePath)
+		if err != nil {
+			return err
+		}
+
+		fileCount++
+
+		return nil
+	})
+
+	return fileCount, err
+}
+
+// Letters returns the number of letters of the corpus.
+func (b *Builder) Letters() uint64 {
+	return b.letterTotal
+}
+
+// Model builds the model of the corpus. N-grams that occur less than minCount times are
+// treated as if they did not occur, which makes the model file smaller.
+func (b *Builder) Model(minCount uint64) (*Model, error) {
+	if b.quadgramTotal == 0 {
+		return nil, ErrEmptyCorpus
+	}
+
+	return newModel(
+		dropRare(b.trigramCounts, minCount),
+		b.trigramTotal,
+		dropRare(b.quadgramCounts, minCount),
+		b.quadgramTotal,
+	), nil
+}
+
+// ******** Private type functions ********
+
+// addFile adds a file as a new document.
+func (b *Builder) addFile(filePath string) error {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return err
+	}
+	defer filehelper.CloseWithName(file)
+
+	return b.AddReader(file)
+}
+
+// ******** Private functions ********
+
+// dropRare returns a copy of counts, where the counts that are smaller than minCount are 0.
+func dropRare(counts []uint64, minCount uint64) []uint64 {
+	result := make([]uint64, len(counts))
+	for i, count := range counts {
+		if count >= minCount {
+			result[i] = count
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,94 @@
+//
+// SPDX-FileCopyrightText: Copyright 2026 Frank Schwab
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileType: SOURCE
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+//
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Author: Frank Schwab
+//
+// Version: 1.0.0
+//
+// Change history:
+//    2026-10-18: V1.0.0: Created.
+//
+
+package langmodel
+
+// The embedded models are built from the public domain texts in the directory "corpus".
+// "go generate" rebuilds them with the "lm build" command, which always writes the same
+// model file for the same corpus.
+//go:generate go run homophone lm build -in corpus/de -out models/de.lm -force
+//go:generate go run homophone lm build -in corpus/en -out models/en.lm -force
+
+import (
+	"bytes"
+	"embed"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"sync"
+)
+
+// ******** Public constants ********
+
+// ErrUnknownLanguage is returned when there is no embedded model for a language.
+var ErrUnknownLanguage = errors.New(`unknown language`)
+
+// ******** Private variables ********
+
+// modelFiles contains the embedded model files. They are built from the English and German
+// texts in the directories "corpus/en" and "corpus/de", which are listed in "corpus/README.md".
+//
+//go:embed models/*.lm
+var modelFiles embed.FS
+
+// embeddedModels contains the functions that load the embedded model of a language only once.
+var embeddedModels = map[string]func() (*Model, error){
+	`de`: sync.OnceValues(func() (*Model, error) { return loadEmbedded(`de`) }),
+	`en`: sync.OnceValues(func() (*Model, error) { return loadEmbedded(`en`) }),
+}
+
+// ******** Public functions ********
+
+// Embedded returns the embedded model of a language, e.g. "en" for English or "de" for German.
+// The model is loaded when it is requested for the first time.
+func Embedded(language string) (*Model, error) {
+	load, ok := embeddedModels[language]
+	if !ok {
+		return nil, fmt.Errorf(`%w: '%s'`, ErrUnknownLanguage, language)
+	}
+
+	return load()
+}
+
+// Languages returns the languages of the embedded models.
+func Languages() []string {
+	return slices.Sorted(maps.Keys(embeddedModels))
+}
+
+// ******** Private functions ********
+
+// loadEmbedded loads the embedded model of a language.
+func loadEmbedded(language string) (*Model, error) {
+	data, err := modelFiles.ReadFile(`models/` + language + `.lm`)
+	if err != nil {
+		return nil, err
+	}
+
+	return Load(bytes.NewReader(data))
+}
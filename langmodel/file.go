@@ -0,0 +1,229 @@
+//
+// SPDX-FileCopyrightText: Copyright 2026 Frank Schwab
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileType: SOURCE
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+//
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Author: Frank Schwab
+//
+// Version: 1.0.0
+//
+// Change history:
+//    2026-10-18: V1.0.0: Created.
+//
+
+package langmodel
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"homophone/compressedinteger"
+	"homophone/filehelper"
+	"io"
+	"os"
+)
+
+// ******** Public constants ********
+
+// ErrInvalidModel is returned when the data of a model file are invalid.
+var ErrInvalidModel = errors.New(`invalid language model`)
+
+// ******** Private constants ********
+
+// fileMagic is the magic bytes of a model file.
+var fileMagic = []byte(`HFLM`)
+
+// actVersion is the current version number of model files.
+//
+// A model file consists of the magic bytes, the version number and a stream of compressed integers
+// with the trigram table followed by the quadgram table. Each table consists of the total number
+// of n-grams, the number of n-grams that occur and, for each of them in the order of their index,
+// the number of the skipped indices before it and its count.
+const actVersion byte = 1
+
+// ******** Public creation functions ********
+
+// NewFromFile loads a model from a model file.
+func NewFromFile(filePath string) (*Model, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, err
+	}
+	defer filehelper.CloseWithName(file)
+
+	return Load(file)
+}
+
+// Load loads a model from the data of a model file that are read from r.
+func Load(r io.Reader) (*Model, error) {
+	reader := bufio.NewReader(r)
+
+	header := make([]byte, len(fileMagic)+1)
+	_, err := io.ReadFull(reader, header)
+	if err != nil {
+		return nil, fmt.Errorf(`%w: %w`, ErrInvalidModel, unexpectedEOF(err))
+	}
+
+	if !bytes.Equal(header[:len(fileMagic)], fileMagic) {
+		return nil, fmt.Errorf(`%w: wrong magic bytes`, ErrInvalidModel)
+	}
+
+	if header[len(fileMagic)] != actVersion {
+		return nil, fmt.Errorf(`%w: unknown version %d`, ErrInvalidModel, header[len(fileMagic)])
+	}
+
+	source := compressedinteger.NewReader(reader)
+
+	var trigramCounts, quadgramCounts []uint64
+	var trigramTotal, quadgramTotal uint64
+	trigramCounts, trigramTotal, err = readTable(source, TrigramCount)
+	if err != nil {
+		return nil, fmt.Errorf(`%w: trigrams: %w`, ErrInvalidModel, err)
+	}
+
+	quadgramCounts, quadgramTotal, err = readTable(source, QuadgramCount)
+	if err != nil {
+		return nil, fmt.Errorf(`%w: quadgrams: %w`, ErrInvalidModel, err)
+	}
+
+	_, err = source.ReadUInt32()
+	if !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf(`%w: data after the quadgrams`, ErrInvalidModel)
+	}
+
+	return newModel(trigramCounts, trigramTotal, quadgramCounts, quadgramTotal), nil
+}
+
+// ******** Public type functions ********
+
+// WriteTo writes the model in the model file format to w.
+// It implements the [io.WriterTo] interface.
+func (m *Model) WriteTo(w io.Writer) (int64, error) {
+	var buffer bytes.Buffer
+	buffer.Write(fileMagic)
+	buffer.WriteByte(actVersion)
+
+	cw := compressedinteger.NewWriter(&buffer)
+	err := writeTable(cw, &m.trigrams)
+	if err != nil {
+		return 0, err
+	}
+
+	err = writeTable(cw, &m.quadgrams)
+	if err != nil {
+		return 0, err
+	}
+
+	return buffer.WriteTo(w)
+}
+
+// ******** Private functions ********
+
+// writeTable writes the total and the counts of the n-grams that occur.
+func writeTable(w *compressedinteger.Writer, t *table) error {
+	err := w.WriteUInt64(t.total)
+	if err != nil {
+		return err
+	}
+
+	err = w.WriteUInt32(uint32(t.distinct()))
+	if err != nil {
+		return err
+	}
+
+	next := 0
+	for index, count := range t.counts {
+		if count == 0 {
+			continue
+		}
+
+		err = w.WriteUInt32(uint32(index - next))
+		if err != nil {
+			return err
+		}
+
+		err = w.WriteUInt64(count)
+		if err != nil {
+			return err
+		}
+
+		next = index + 1
+	}
+
+	return nil
+}
+
+// readTable reads the total and the counts of the n-grams of a table with size entries.
+func readTable(source *compressedinteger.Reader, size int) ([]uint64, uint64, error) {
+	total, err := source.ReadUInt64()
+	if err != nil {
+		return nil, 0, unexpectedEOF(err)
+	}
+
+	var entryCount uint32
+	entryCount, err = source.ReadUInt32()
+	if err != nil {
+		return nil, 0, unexpectedEOF(err)
+	}
+
+	if entryCount > uint32(size) {
+		return nil, 0, fmt.Errorf(`too many entries: %d`, entryCount)
+	}
+
+	counts := make([]uint64, size)
+	next := uint64(0)
+	sum := uint64(0)
+	for range entryCount {
+		var skipped uint32
+		skipped, err = source.ReadUInt32()
+		if err != nil {
+			return nil, 0, unexpectedEOF(err)
+		}
+
+		index := next + uint64(skipped)
+		if index >= uint64(size) {
+			return nil, 0, fmt.Errorf(`invalid index: %d`, index)
+		}
+
+		var count uint64
+		count, err = source.ReadUInt64()
+		if err != nil {
+			return nil, 0, unexpectedEOF(err)
+		}
+
+		if count == 0 || count > total-sum {
+			return nil, 0, fmt.Errorf(`invalid count of index %d: %d`, index, count)
+		}
+
+		counts[index] = count
+		sum += count
+		next = index + 1
+	}
+
+	return counts, total, nil
+}
+
+// unexpectedEOF converts an [io.EOF] into an [io.ErrUnexpectedEOF], as the data end too early.
+func unexpectedEOF(err error) error {
+	if errors.Is(err, io.EOF) {
+		return io.ErrUnexpectedEOF
+	}
+
+	return err
+}
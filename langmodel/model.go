@@ -0,0 +1,246 @@
+//
+// SPDX-FileCopyrightText: Copyright 2026 Frank Schwab
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileType: SOURCE
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+//
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Author: Frank Schwab
+//
+// Version: 1.0.0
+//
+// Change history:
+//    2026-10-18: V1.0.0: Created.
+//
+
+// Package langmodel implements n-gram language models for scoring texts.
+// A model contains the log10 probabilities of all trigrams and quadgrams of the letters A-Z.
+// It is built from the letters of a text corpus, where lower case letters are converted
+// to upper case and all other characters are skipped, just as the encryption processes a text.
+package langmodel
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// ******** Public types ********
+
+// Model is an n-gram language model. It is safe for concurrent use.
+type Model struct {
+	trigrams  table
+	quadgrams table
+}
+
+// ******** Public constants ********
+
+// LetterCount is the number of letters of the models.
+const LetterCount = 26
+
+// TrigramCount is the number of different trigrams.
+const TrigramCount = LetterCount * LetterCount * LetterCount
+
+// QuadgramCount is the number of different quadgrams.
+const QuadgramCount = TrigramCount * LetterCount
+
+// ErrInvalidNGram is returned when an n-gram has the wrong length or contains characters that are not letters.
+var ErrInvalidNGram = errors.New(`invalid n-gram`)
+
+// ******** Private types ********
+
+// table contains the counts and log10 probabilities of the n-grams of one length.
+type table struct {
+	// counts contains the number of occurrences of each n-gram.
+	counts []uint64
+
+	// total is the number of all n-grams of the corpus.
+	// It may be larger than the sum of the counts, if rare n-grams have been dropped.
+	total uint64
+
+	// logProbabilities contains the log10 probability of each n-gram.
+	// N-grams that do not occur get the floor probability.
+	logProbabilities []float32
+}
+
+// ******** Private constants ********
+
+// floorCount is the count that is assumed for n-grams that do not occur in the corpus.
+const floorCount = 0.01
+
+// ******** Public type functions ********
+
+// Score returns the sum of the log10 probabilities of the quadgrams of the letters of text.
+// The higher the score, the more the text resembles the language of the model.
+// Characters that are not letters are skipped.
+func (m *Model) Score(text []byte) float64 {
+	return m.quadgrams.score(text, 4)
+}
+
+// ScoreTrigrams returns the sum of the log10 probabilities of the trigrams of the letters of text.
+// Characters that are not letters are skipped.
+func (m *Model) ScoreTrigrams(text []byte) float64 {
+	return m.trigrams.score(text, 3)
+}
+
+// ScoreIndices returns the sum of the log10 probabilities of the quadgrams of letters
+// that are given as indices in the range 0 to 25.
+// It is the fastest way to score many candidate decryptions, e.g. in a hill climbing.
+// It panics, if an index is not smaller than [LetterCount].
+func (m *Model) ScoreIndices(indices []byte) float64 {
+	if len(indices) < 4 {
+		return 0
+	}
+
+	logProbabilities := m.quadgrams.logProbabilities
+	index := (int(indices[0])*LetterCount+int(indices[1]))*LetterCount + int(indices[2])
+	result := 0.0
+	for _, letter := range indices[3:] {
+		index = (index%TrigramCount)*LetterCount + int(letter)
+		result += float64(logProbabilities[index])
+	}
+
+	return result
+}
+
+// LogProbability returns the log10 probability of a trigram or quadgram.
+func (m *Model) LogProbability(ngram string) (float64, error) {
+	var t *table
+	switch len(ngram) {
+	case 3:
+		t = &m.trigrams
+
+	case 4:
+		t = &m.quadgrams
+
+	default:
+		return 0, fmt.Errorf(`%w: '%s' does not have 3 or 4 letters`, ErrInvalidNGram, ngram)
+	}
+
+	index := 0
+	for i := range len(ngram) {
+		letter, ok := letterIndex(ngram[i])
+		if !ok {
+			return 0, fmt.Errorf(`%w: '%s' contains a character that is not a letter`, ErrInvalidNGram, ngram)
+		}
+
+		index = index*LetterCount + letter
+	}
+
+	return float64(t.logProbabilities[index]), nil
+}
+
+// TrigramTotal returns the number of trigrams of the corpus the model was built from.
+func (m *Model) TrigramTotal() uint64 {
+	return m.trigrams.total
+}
+
+// QuadgramTotal returns the number of quadgrams of the corpus the model was built from.
+func (m *Model) QuadgramTotal() uint64 {
+	return m.quadgrams.total
+}
+
+// DistinctTrigrams returns the number of different trigrams that the model knows.
+func (m *Model) DistinctTrigrams() int {
+	return m.trigrams.distinct()
+}
+
+// DistinctQuadgrams returns the number of different quadgrams that the model knows.
+func (m *Model) DistinctQuadgrams() int {
+	return m.quadgrams.distinct()
+}
+
+// ******** Private type functions ********
+
+// score returns the sum of the log10 probabilities of the n-grams of length n of the letters of text.
+func (t *table) score(text []byte, n int) float64 {
+	size := len(t.logProbabilities)
+	prefixSize := size / LetterCount
+	index := 0
+	letterCount := 0
+	result := 0.0
+	for _, b := range text {
+		letter, ok := letterIndex(b)
+		if !ok {
+			continue
+		}
+
+		index = (index%prefixSize)*LetterCount + letter
+		letterCount++
+		if letterCount >= n {
+			result += float64(t.logProbabilities[index])
+		}
+	}
+
+	return result
+}
+
+// distinct returns the number of n-grams that occur.
+func (t *table) distinct() int {
+	result := 0
+	for _, count := range t.counts {
+		if count != 0 {
+			result++
+		}
+	}
+
+	return result
+}
+
+// calculateLogProbabilities calculates the log10 probabilities from the counts.
+func (t *table) calculateLogProbabilities() {
+	total := float64(max(t.total, 1))
+	floor := float32(math.Log10(floorCount / total))
+
+	t.logProbabilities = make([]float32, len(t.counts))
+	for i, count := range t.counts {
+		if count == 0 {
+			t.logProbabilities[i] = floor
+		} else {
+			t.logProbabilities[i] = float32(math.Log10(float64(count) / total))
+		}
+	}
+}
+
+// ******** Private functions ********
+
+// newModel creates a model from the n-gram counts and totals.
+func newModel(trigramCounts []uint64, trigramTotal uint64, quadgramCounts []uint64, quadgramTotal uint64) *Model {
+	result := &Model{
+		trigrams:  table{counts: trigramCounts, total: trigramTotal},
+		quadgrams: table{counts: quadgramCounts, total: quadgramTotal},
+	}
+
+	result.trigrams.calculateLogProbabilities()
+	result.quadgrams.calculateLogProbabilities()
+
+	return result
+}
+
+// letterIndex returns the index of a letter in the range 0 to 25, where lower case letters are treated as upper case ones.
+// It returns false, if b is not a letter.
+func letterIndex(b byte) (int, bool) {
+	switch {
+	case b >= 'A' && b <= 'Z':
+		return int(b - 'A'), true
+
+	case b >= 'a' && b <= 'z':
+		return int(b - 'a'), true
+
+	default:
+		return 0, false
+	}
+}
@@ -0,0 +1,276 @@
+//
+// SPDX-FileCopyrightText: Copyright 2026 Frank Schwab
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileType: SOURCE
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+//
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Author: Frank Schwab
+//
+// Version: 1.0.0
+//
+// Change history:
+//    2026-10-18: V1.0.0: Created.
+//
+
+package langmodel
+
+import (
+	"bytes"
+	"errors"
+	"math"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// ******** Private constants ********
+
+const tolerance = 1e-6
+
+const englishText = `It was the best of times, it was the worst of times, it was the age of wisdom, it was the age of foolishness.`
+
+const germanText = `Es war einmal ein kleines Mädchen, das wohnte mit seiner Mutter in einem Haus am Rande des Waldes.`
+
+// ******** Test functions ********
+
+func TestLogProbability(t *testing.T) {
+	m := buildModel(t, 1, `abcdab`, `cd`)
+
+	// The quadgrams are ABCD, BCDA and CDAB, as documents are not joined. ABCD occurs 1 of 3 times.
+	expectLogProbability(t, m, `ABCD`, math.Log10(1.0/3))
+	expectLogProbability(t, m, `abcd`, math.Log10(1.0/3))
+	expectLogProbability(t, m, `DABC`, math.Log10(floorCount/3))
+
+	// The trigrams are ABC, BCD, CDA and DAB.
+	expectLogProbability(t, m, `ABC`, math.Log10(1.0/4))
+
+	for _, ngram := range []string{`AB`, `ABCDE`, `AB-D`, `ÄBC`} {
+		_, err := m.LogProbability(ngram)
+		if !errors.Is(err, ErrInvalidNGram) {
+			t.Fatalf(`'%s': Expected error '%v', got '%v'`, ngram, ErrInvalidNGram, err)
+		}
+	}
+}
+
+func TestSkipsOtherCharacters(t *testing.T) {
+	m := buildModel(t, 1, `a-B, c!d`)
+	expectLogProbability(t, m, `ABCD`, 0)
+}
+
+func TestScore(t *testing.T) {
+	m := buildModel(t, 1, englishText)
+
+	text := []byte(`It was the age of wisdom!`)
+	letters := lettersOnly(string(text))
+	indices := make([]byte, len(letters))
+	expected := 0.0
+	for i, letter := range letters {
+		indices[i] = letter - 'A'
+		if i >= 3 {
+			p, err := m.LogProbability(string(letters[i-3 : i+1]))
+			if err != nil {
+				t.Fatalf(`Error getting log probability: %v`, err)
+			}
+
+			expected += p
+		}
+	}
+
+	if math.Abs(m.Score(text)-expected) > tolerance {
+		t.Fatalf(`Score is %f instead of %f`, m.Score(text), expected)
+	}
+
+	if math.Abs(m.ScoreIndices(indices)-expected) > tolerance {
+		t.Fatalf(`Score of indices is %f instead of %f`, m.ScoreIndices(indices), expected)
+	}
+
+	if m.Score([]byte(`abc`)) != 0 || m.ScoreIndices([]byte{0, 1, 2}) != 0 {
+		t.Fatal(`Text without quadgrams has a score`)
+	}
+
+	if m.ScoreTrigrams(text) >= 0 {
+		t.Fatalf(`Trigram score is %f`, m.ScoreTrigrams(text))
+	}
+}
+
+func TestFileRoundTrip(t *testing.T) {
+	m := buildModel(t, 2, englishText, germanText)
+
+	var data bytes.Buffer
+	_, err := m.WriteTo(&data)
+	if err != nil {
+		t.Fatalf(`Error writing model: %v`, err)
+	}
+
+	var loaded *Model
+	loaded, err = Load(bytes.NewReader(data.Bytes()))
+	if err != nil {
+		t.Fatalf(`Error loading model: %v`, err)
+	}
+
+	for _, pair := range [][2]*table{{&m.trigrams, &loaded.trigrams}, {&m.quadgrams, &loaded.quadgrams}} {
+		if pair[0].total != pair[1].total || !slices.Equal(pair[0].counts, pair[1].counts) || !slices.Equal(pair[0].logProbabilities, pair[1].logProbabilities) {
+			t.Fatal(`Loaded model differs`)
+		}
+	}
+
+	// Rare n-grams are dropped, but the total remains.
+	if m.DistinctQuadgrams() == 0 || m.DistinctQuadgrams() >= buildModel(t, 1, englishText, germanText).DistinctQuadgrams() {
+		t.Fatalf(`Rare quadgrams are not dropped: %d remain`, m.DistinctQuadgrams())
+	}
+
+	if m.QuadgramTotal() != uint64(len(lettersOnly(englishText))+len(lettersOnly(germanText))-6) {
+		t.Fatalf(`Wrong number of quadgrams: %d`, m.QuadgramTotal())
+	}
+}
+
+func TestLoadInvalid(t *testing.T) {
+	var data bytes.Buffer
+	_, err := buildModel(t, 1, englishText).WriteTo(&data)
+	if err != nil {
+		t.Fatalf(`Error writing model: %v`, err)
+	}
+
+	valid := data.Bytes()
+	tests := map[string][]byte{
+		`empty`:         {},
+		`wrong magic`:   append([]byte(`HFDF`), valid[4:]...),
+		`wrong version`: append(append([]byte(`HFLM`), 2), valid[5:]...),
+		`truncated`:     valid[:len(valid)-1],
+		`too long`:      append(slices.Clone(valid), 0),
+	}
+
+	for name, invalid := range tests {
+		_, err = Load(bytes.NewReader(invalid))
+		if !errors.Is(err, ErrInvalidModel) {
+			t.Fatalf(`%s: Expected error '%v', got '%v'`, name, ErrInvalidModel, err)
+		}
+	}
+}
+
+func TestEmptyCorpus(t *testing.T) {
+	b := NewBuilder()
+	_, _ = b.Write([]byte(`abc, 123`))
+
+	_, err := b.Model(1)
+	if !errors.Is(err, ErrEmptyCorpus) {
+		t.Fatalf(`Expected error '%v', got '%v'`, ErrEmptyCorpus, err)
+	}
+}
+
+func TestEmbedded(t *testing.T) {
+	if !slices.Equal(Languages(), []string{`de`, `en`}) {
+		t.Fatalf(`Unexpected languages: %v`, Languages())
+	}
+
+	english, err := Embedded(`en`)
+	if err != nil {
+		t.Fatalf(`Error loading English model: %v`, err)
+	}
+
+	var german *Model
+	german, err = Embedded(`de`)
+	if err != nil {
+		t.Fatalf(`Error loading German model: %v`, err)
+	}
+
+	again, _ := Embedded(`en`)
+	if again != english {
+		t.Fatal(`English model is loaded twice`)
+	}
+
+	// Each model prefers texts of its language.
+	if english.Score([]byte(englishText)) <= german.Score([]byte(englishText)) {
+		t.Fatal(`German model prefers English text`)
+	}
+
+	if german.Score([]byte(germanText)) <= english.Score([]byte(germanText)) {
+		t.Fatal(`English model prefers German text`)
+	}
+
+	_, err = Embedded(`fr`)
+	if !errors.Is(err, ErrUnknownLanguage) {
+		t.Fatalf(`Expected error '%v', got '%v'`, ErrUnknownLanguage, err)
+	}
+}
+
+// ******** Benchmark functions ********
+
+// BenchmarkScoreIndices benchmarks the scoring of a text whose letters are given as indices
+func BenchmarkScoreIndices(b *testing.B) {
+	m, err := Embedded(`en`)
+	if err != nil {
+		b.Fatalf(`Error loading English model: %v`, err)
+	}
+
+	indices := make([]byte, 0, len(englishText))
+	for _, c := range lettersOnly(englishText) {
+		indices = append(indices, c-'A')
+	}
+
+	for n := 0; n < b.N; n++ {
+		m.ScoreIndices(indices)
+	}
+}
+
+// ******** Private functions ********
+
+// buildModel builds a model from documents.
+func buildModel(t *testing.T, minCount uint64, documents ...string) *Model {
+	t.Helper()
+
+	b := NewBuilder()
+	for _, document := range documents {
+		err := b.AddReader(strings.NewReader(document))
+		if err != nil {
+			t.Fatalf(`Error adding document: %v`, err)
+		}
+	}
+
+	m, err := b.Model(minCount)
+	if err != nil {
+		t.Fatalf(`Error building model: %v`, err)
+	}
+
+	return m
+}
+
+// expectLogProbability checks the log10 probability of an n-gram.
+func expectLogProbability(t *testing.T, m *Model, ngram string, expected float64) {
+	t.Helper()
+
+	got, err := m.LogProbability(ngram)
+	if err != nil {
+		t.Fatalf(`Error getting log probability of '%s': %v`, ngram, err)
+	}
+
+	if math.Abs(got-expected) > tolerance {
+		t.Fatalf(`Log probability of '%s' is %f instead of %f`, ngram, got, expected)
+	}
+}
+
+// lettersOnly returns the letters of a text in upper case.
+func lettersOnly(text string) []byte {
+	var result []byte
+	for _, b := range bytes.ToUpper([]byte(text)) {
+		if b >= 'A' && b <= 'Z' {
+			result = append(result, b)
+		}
+	}
+
+	return result
+}
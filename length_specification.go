@@ -0,0 +1,114 @@
+//
+// SPDX-FileCopyrightText: Copyright 2026 Frank Schwab
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileType: SOURCE
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+//
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Author: Frank Schwab
+//
+// Version: 1.0.0
+//
+// Change history:
+//    2026-10-18: V1.0.0: Created.
+//
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ******** Private constants ********
+
+// letterCount is the number of letters A-Z.
+const letterCount = 26
+
+// ******** Private functions ********
+
+// parseLengthSpecification parses a specification of substitution lengths for the letters A-Z.
+// The specification is a comma-separated list of items. An item is either a number that applies
+// to all letters, or a letter followed by '=' and a number that applies only to this letter.
+// E.g. "1,E=4,Q=0" sets the value 1 for all letters, but 4 for 'E' and 0 for 'Q'.
+// A letter that is not mentioned gets defaultValue. An empty specification results in nil.
+func parseLengthSpecification(spec string, defaultValue uint) ([]uint, error) {
+	spec = strings.TrimSpace(spec)
+	if len(spec) == 0 {
+		return nil, nil
+	}
+
+	items := strings.Split(spec, `,`)
+
+	result := make([]uint, letterCount)
+	for i := range result {
+		result[i] = defaultValue
+	}
+
+	// First apply the value for all letters, so the order of the items does not matter.
+	for _, item := range items {
+		item = strings.TrimSpace(item)
+		if strings.Contains(item, `=`) {
+			continue
+		}
+
+		value, err := parseLength(item)
+		if err != nil {
+			return nil, err
+		}
+
+		for i := range result {
+			result[i] = value
+		}
+	}
+
+	for _, item := range items {
+		letterText, valueText, found := strings.Cut(strings.TrimSpace(item), `=`)
+		if !found {
+			continue
+		}
+
+		letterText = strings.ToUpper(strings.TrimSpace(letterText))
+		if len(letterText) != 1 || letterText[0] < 'A' || letterText[0] > 'Z' {
+			return nil, fmt.Errorf(`'%s' is not a letter A-Z`, letterText)
+		}
+
+		value, err := parseLength(strings.TrimSpace(valueText))
+		if err != nil {
+			return nil, err
+		}
+
+		result[letterText[0]-'A'] = value
+	}
+
+	return result, nil
+}
+
+// parseLength parses one length value.
+func parseLength(text string) (uint, error) {
+	if len(text) == 0 {
+		return 0, errors.New(`length is missing`)
+	}
+
+	value, err := strconv.ParseUint(text, 10, 16)
+	if err != nil {
+		return 0, fmt.Errorf(`'%s' is not a valid length`, text)
+	}
+
+	return uint(value), nil
+}
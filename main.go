@@ -20,7 +20,7 @@
 //
 // Author: Frank Schwab
 //
-// Version: 3.0.1
+// Version: 3.15.0
 //
 // Change history:
 //    2024-09-17: V1.0.0: Created.
@@ -39,18 +39,43 @@
 //    2025-02-17: V2.8.0: Simplify decryption.
 //    2025-02-17: V3.0.0: Work only with bytes, instead of runes.
 //    2025-02-24: V3.0.1: Slightly improved efficiency.
+//    2026-10-18: V3.1.0: Write output files atomically, do not overwrite them without "force".
+//    2026-10-18: V3.2.0: Command line and output streams are passed to realMain.
+//    2026-10-18: V3.3.0: Selectable apportionment method.
+//    2026-10-18: V3.4.0: Minimum and maximum number of substitutions per letter.
+//    2026-10-18: V3.5.0: Selectable selection strategy for substitutions.
+//    2026-10-18: V3.6.0: Weighted selection of substitutions.
+//    2026-10-18: V3.7.0: Experimental context-dependent substitution. Analysis of encrypted files.
+//    2026-10-18: V3.8.0: HTTP server.
+//    2026-10-18: V3.9.0: Interactive session.
+//    2026-10-18: V3.10.0: Exercise sheets.
+//    2026-10-18: V3.11.0: Grading of solutions.
+//    2026-10-18: V3.12.0: Crib attacks.
+//    2026-10-18: V3.13.0: Groups of probable homophones.
+//    2026-10-18: V3.14.0: Language models.
+//    2026-10-18: V3.15.0: Added cipher modes.
 //
 
 package main
 
 import (
+	"homophone/exercise"
+	"homophone/homosubst"
+	"io"
 	"os"
+	"strings"
 	"unicode"
 	"unicode/utf8"
 )
 
 // myVersion contains the current version of this program.
-const myVersion = `3.0.1`
+const myVersion = `3.15.0`
+
+// exerciseCommandName is the name of the "exercise" command. It starts with the same letter as "encrypt".
+const exerciseCommandName = `exercise`
+
+// lmBuildCommandName is the name of the "build" subcommand of the "lm" command.
+const lmBuildCommandName = `build`
 
 // myCopyright contains the copyright of this program.
 const myCopyright = `Copyright (c) 2024-2025 Frank Schwab`
@@ -59,11 +84,16 @@ const myCopyright = `Copyright (c) 2024-2025 Frank Schwab`
 
 // main is the entry point of the program.
 func main() {
-	os.Exit(realMain(os.Args[1:]))
+	os.Exit(realMain(os.Args[1:], os.Stdout, os.Stderr))
 }
 
 // realMain is the real main function with a return code.
-func realMain(args []string) int {
+// args are the command line arguments without the program name.
+// Normal output is written to stdout, error messages and usage information to stderr.
+func realMain(args []string, stdout io.Writer, stderr io.Writer) int {
+	outWriter = stdout
+	errWriter = stderr
+
 	defineCommandLineFlags()
 
 	numArgs := len(args)
@@ -71,23 +101,70 @@ func realMain(args []string) int {
 		return printUsageError(`Not enough arguments`)
 	}
 
+	var doIt bool
 	var rc int
 
 	r, _ := utf8.DecodeRuneInString(args[0])
 	cmd := unicode.ToUpper(r)
 	switch cmd {
+	case 'A':
+		doIt, rc = parseAnalysis(args[1:])
+		if doIt {
+			return doAnalysis(inFileName, topCount, groupCount, substFileName, plainFileName)
+		} else {
+			return rc
+		}
+
+	case 'C':
+		doIt, rc = parseCrib(args[1:])
+		if doIt {
+			return doCrib(inFileName, cribText, maxAlignments, cribPosition, substFileName, forceOverwrite)
+		} else {
+			return rc
+		}
+
 	case 'D':
-		rc = parseDecryption()
-		if rc == rcOK {
-			return doDecryption(inFileName, outFileName, substFileName)
+		doIt, rc = parseDecryption(args[1:])
+		if doIt {
+			return doDecryption(inFileName, outFileName, substFileName, forceOverwrite, protectOutput)
 		} else {
 			return rc
 		}
 
 	case 'E':
-		rc = parseEncryption()
-		if rc == rcOK {
-			return doEncryption(inFileName, outFileName, substFileName, keepOthers)
+		if isExerciseCommand(args[0]) {
+			doIt, rc = parseExercise(args[1:])
+			if doIt {
+				return doExercise(inFileName, outFileName, answerFileName, substFileName, sheetFormat, exercise.Options{
+					Reveal:      revealCount,
+					KeepOthers:  keepOthers,
+					Frequencies: withFrequencies,
+				}, forceOverwrite)
+			} else {
+				return rc
+			}
+		}
+
+		doIt, rc = parseEncryption(args[1:])
+		if doIt {
+			return doEncryption(inFileName, outFileName, substFileName, keepOthers, forceOverwrite, protectOutput, hashAlgorithm, homosubst.Options{
+				Apportioner:       apportioner,
+				MinLengths:        minLengths,
+				MaxLengths:        maxLengths,
+				Selection:         selection,
+				Weighted:          weighted,
+				ContextClassCount: contextClassCount,
+				Mode:              cipherMode,
+				Shift:             caesarShift,
+			})
+		} else {
+			return rc
+		}
+
+	case 'G':
+		doIt, rc = parseGrade(args[1:])
+		if doIt {
+			return doGrade(substFileName, cipherFileName, answerFileName, answerKind)
 		} else {
 			return rc
 		}
@@ -95,6 +172,30 @@ func realMain(args []string) int {
 	case 'H':
 		return printUsageOnly()
 
+	case 'L':
+		doIt, rc = parseLanguageModel(args[1:])
+		if doIt {
+			return doBuildLanguageModel(inFileName, outFileName, minNGramCount, forceOverwrite)
+		} else {
+			return rc
+		}
+
+	case 'R':
+		doIt, rc = parseRepl(args[1:])
+		if doIt {
+			return doRepl(substFileName, keepOthers)
+		} else {
+			return rc
+		}
+
+	case 'S':
+		doIt, rc = parseServe(args[1:])
+		if doIt {
+			return doServe(listenAddress, maxRequestSize)
+		} else {
+			return rc
+		}
+
 	case 'V':
 		return printVersion()
 
@@ -102,3 +203,11 @@ func realMain(args []string) int {
 		return printUsageErrorf(`Unknown command: '%s'`, args[0])
 	}
 }
+
+// ******** Private functions ********
+
+// isExerciseCommand returns true, if command is the "exercise" command or an abbreviation of it
+// with at least two letters. All other commands that start with 'E' are the "encrypt" command.
+func isExerciseCommand(command string) bool {
+	return len(command) >= 2 && strings.HasPrefix(exerciseCommandName, strings.ToLower(command))
+}
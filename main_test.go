@@ -0,0 +1,778 @@
+//
+// SPDX-FileCopyrightText: Copyright 2026 Frank Schwab
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileType: SOURCE
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+//
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Author: Frank Schwab
+//
+// Version: 1.0.0
+//
+// Change history:
+//    2026-10-18: V1.0.0: Created.
+//
+
+package main
+
+import (
+	"bytes"
+	"homophone/langmodel"
+	"homophone/oshelper"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// ******** Private constants ********
+
+const testClearText = "The quick brown fox jumps over the lazy dog.\nPack my box with five dozen liquor jugs!\n"
+
+// ******** Test functions ********
+
+func TestRoundTrip(t *testing.T) {
+	tests := []struct {
+		name          string
+		keep          bool
+		explicitNames bool
+		hash          string
+		method        string
+		bounds        []string
+		selection     string
+		weighted      bool
+		bigram        string
+	}{
+		{name: `default names`},
+		{name: `default names with keep`, keep: true},
+		{name: `explicit names`, explicitNames: true},
+		{name: `explicit names with keep and hash`, keep: true, explicitNames: true, hash: `blake2b`},
+		{name: `method dhondt`, method: `dhondt`},
+		{name: `method hare-niemeyer`, method: `hare-niemeyer`},
+		{name: `method huntington-hill`, method: `huntington-hill`},
+		{name: `method adams`, method: `adams`},
+		{name: `min and max`, bounds: []string{`-min`, `1`, `-max`, `3,E=4`}},
+		{name: `min and max with method`, method: `hare-niemeyer`, bounds: []string{`-min`, `2,Q=0`, `-max`, `3`}},
+		{name: `select uniform`, selection: `uniform`},
+		{name: `select sequential`, selection: `sequential`},
+		{name: `select window`, selection: `window`},
+		{name: `select window with keep`, keep: true, selection: `Window`},
+		{name: `weighted`, weighted: true},
+		{name: `weighted with method`, method: `dhondt`, weighted: true},
+		{name: `bigram`, bigram: `3`},
+		{name: `bigram with keep and method`, keep: true, method: `dhondt`, bigram: `26`},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			dir := t.TempDir()
+			clearFileName := writeTestFile(t, dir, `clear.txt`, testClearText)
+
+			encryptedFileName := filepath.Join(dir, `clear_homophone.txt`)
+			decryptedFileName := filepath.Join(dir, `clear_decrypted.txt`)
+			substFileName := filepath.Join(dir, `clear_txt.subst`)
+
+			encryptArgs := []string{`encrypt`, `-in`, clearFileName}
+			decryptArgs := []string{`decrypt`, `-in`, encryptedFileName}
+			if tc.explicitNames {
+				encryptedFileName = filepath.Join(dir, `secret.txt`)
+				decryptedFileName = filepath.Join(dir, `plain.txt`)
+				substFileName = filepath.Join(dir, `my.key`)
+				encryptArgs = append(encryptArgs, `-out`, encryptedFileName, `-key`, substFileName)
+				decryptArgs = []string{`decrypt`, `-in`, encryptedFileName, `-out`, decryptedFileName, `-key`, substFileName}
+			}
+			if tc.keep {
+				encryptArgs = append(encryptArgs, `-keep`)
+			}
+			if len(tc.hash) != 0 {
+				encryptArgs = append(encryptArgs, `-hash`, tc.hash)
+			}
+			if len(tc.method) != 0 {
+				encryptArgs = append(encryptArgs, `-method`, tc.method)
+			}
+			encryptArgs = append(encryptArgs, tc.bounds...)
+			if len(tc.selection) != 0 {
+				encryptArgs = append(encryptArgs, `-select`, tc.selection)
+			}
+			if tc.weighted {
+				encryptArgs = append(encryptArgs, `-weighted`)
+			}
+			if len(tc.bigram) != 0 {
+				encryptArgs = append(encryptArgs, `-bigram`, tc.bigram)
+			}
+
+			stdout := expectReturnCode(t, encryptArgs, rcOK)
+			if !strings.Contains(stdout, `Expected frequencies of the substitution characters`) {
+				t.Errorf(`Output does not contain the flatness`)
+			}
+			for _, fileName := range []string{clearFileName, encryptedFileName, substFileName} {
+				if !strings.Contains(stdout, fileName) {
+					t.Errorf(`Output does not mention file '%s'`, fileName)
+				}
+			}
+
+			expectReturnCode(t, decryptArgs, rcOK)
+
+			encrypted := readTestFile(t, encryptedFileName)
+			if strings.Contains(encrypted, `quick`) {
+				t.Errorf(`Encrypted file contains clear text`)
+			}
+
+			expected := expectedDecryption(testClearText, tc.keep)
+			decrypted := readTestFile(t, decryptedFileName)
+			if decrypted != expected {
+				t.Errorf(`Decrypted text is '%s' instead of '%s'`, decrypted, expected)
+			}
+		})
+	}
+}
+
+func TestReturnCodes(t *testing.T) {
+	dir := t.TempDir()
+	clearFileName := writeTestFile(t, dir, `clear.txt`, testClearText)
+	existingFileName := writeTestFile(t, dir, `existing.txt`, testClearText)
+	noLettersFileName := writeTestFile(t, dir, `digits.txt`, `0123456789`)
+	corruptKeyFileName := writeTestFile(t, dir, `corrupt.subst`, `HFDF corrupt key file`)
+	missingFileName := filepath.Join(dir, `missing.txt`)
+
+	tests := []struct {
+		name       string
+		args       []string
+		expectedRc int
+		inStderr   string
+	}{
+		{`no arguments`, []string{}, rcParameterError, `Not enough arguments`},
+		{`unknown command`, []string{`xyz`}, rcParameterError, `Unknown command: 'xyz'`},
+		{`unknown flag`, []string{`encrypt`, `-unknown`}, rcParameterError, `flag provided but not defined`},
+		{`missing input file name`, []string{`encrypt`}, rcParameterError, `Name of clear text file is missing`},
+		{`missing encrypted file name`, []string{`decrypt`}, rcParameterError, `Name of encrypted file is missing`},
+		{`arguments without flags`, []string{`encrypt`, `-in`, clearFileName, `extra`}, rcParameterError, `Arguments without flags present`},
+		{`invalid hash algorithm`, []string{`encrypt`, `-in`, clearFileName, `-hash`, `md5`}, rcParameterError, `Invalid hash algorithm`},
+		{`invalid method`, []string{`encrypt`, `-in`, clearFileName, `-method`, `lottery`}, rcParameterError, `Invalid apportionment method`},
+		{`invalid min`, []string{`encrypt`, `-in`, clearFileName, `-min`, `1,EE=2`}, rcParameterError, `Invalid minimum lengths`},
+		{`invalid max`, []string{`encrypt`, `-in`, clearFileName, `-max`, `-1`}, rcParameterError, `Invalid maximum lengths`},
+		{`invalid selection strategy`, []string{`encrypt`, `-in`, clearFileName, `-select`, `dice`}, rcParameterError, `Invalid selection strategy`},
+		{`invalid bigram classes`, []string{`encrypt`, `-in`, clearFileName, `-bigram`, `27`}, rcParameterError, `Invalid number of predecessor classes`},
+		{`invalid mode`, []string{`encrypt`, `-in`, clearFileName, `-mode`, `vigenere`}, rcParameterError, `Invalid mode`},
+		{`bigram with baseline mode`, []string{`encrypt`, `-in`, clearFileName, `-mode`, `mono`, `-bigram`, `2`}, rcParameterError, `Predecessor classes can only be used with mode 'homophonic'`},
+		{`bigram with weighted`, []string{`encrypt`, `-in`, clearFileName, `-bigram`, `2`, `-weighted`}, rcParameterError, `Predecessor classes can only be used with the default selection strategy`},
+		{`bigram with selection`, []string{`encrypt`, `-in`, clearFileName, `-bigram`, `2`, `-select`, `window`}, rcParameterError, `Predecessor classes can only be used with the default selection strategy`},
+		{`method with baseline mode`, []string{`encrypt`, `-in`, clearFileName, `-mode`, `mono`, `-method`, `dhondt`}, rcParameterError, `Option 'method' can only be used with mode 'homophonic'`},
+		{`min with baseline mode`, []string{`encrypt`, `-in`, clearFileName, `-mode`, `caesar`, `-min`, `1`}, rcParameterError, `Option 'min' can only be used with mode 'homophonic'`},
+		{`max with baseline mode`, []string{`encrypt`, `-in`, clearFileName, `-mode`, `mono`, `-max`, `3`}, rcParameterError, `Option 'max' can only be used with mode 'homophonic'`},
+		{`select with baseline mode`, []string{`encrypt`, `-in`, clearFileName, `-mode`, `caesar`, `-select`, `window`}, rcParameterError, `Option 'select' can only be used with mode 'homophonic'`},
+		{`weighted with baseline mode`, []string{`encrypt`, `-in`, clearFileName, `-mode`, `mono`, `-weighted`}, rcParameterError, `Option 'weighted' can only be used with mode 'homophonic'`},
+		{`shift without caesar mode`, []string{`encrypt`, `-in`, clearFileName, `-shift`, `2`}, rcParameterError, `A shift can only be used with mode 'caesar'`},
+		{`missing plain file`, []string{`analyze`, `-in`, clearFileName, `-plain`, missingFileName}, rcProcessingError, `Error creating substitutor`},
+		{`max too small`, []string{`encrypt`, `-in`, clearFileName, `-max`, `1`}, rcProcessingError, `Error creating substitutor`},
+		{`existing output file`, []string{`encrypt`, `-in`, clearFileName, `-out`, existingFileName}, rcParameterError, `already exists`},
+		{`same files`, []string{`encrypt`, `-in`, clearFileName, `-out`, clearFileName, `-force`}, rcParameterError, `are the same file`},
+		{`missing input file`, []string{`encrypt`, `-in`, missingFileName}, rcProcessingError, `Error creating substitutor`},
+		{`no letters`, []string{`encrypt`, `-in`, noLettersFileName}, rcProcessingError, `has no characters in the range A-Z`},
+		{`missing key file`, []string{`decrypt`, `-in`, clearFileName, `-key`, missingFileName}, rcProcessingError, `Error loading substitution file`},
+		{`corrupt key file`, []string{`decrypt`, `-in`, clearFileName, `-key`, corruptKeyFileName}, rcProcessingError, `Error loading substitution file`},
+		{`flag help`, []string{`encrypt`, `-h`}, rcOK, `-keep`},
+		{`missing analysis file name`, []string{`analyze`}, rcParameterError, `Name of encrypted file is missing`},
+		{`missing analysis file`, []string{`analyze`, `-in`, missingFileName}, rcProcessingError, `Error opening encrypted file`},
+		{`invalid top count`, []string{`analyze`, `-in`, clearFileName, `-top`, `-1`}, rcParameterError, `Invalid number of bigrams`},
+		{`invalid group count`, []string{`analyze`, `-in`, clearFileName, `-groups`, `53`}, rcParameterError, `Invalid number of groups`},
+		{`key without groups`, []string{`analyze`, `-in`, clearFileName, `-key`, clearFileName}, rcParameterError, `Specify 'groups'`},
+		{`analysis corrupt key file`, []string{`analyze`, `-in`, clearFileName, `-groups`, `26`, `-key`, corruptKeyFileName}, rcProcessingError, `Error loading substitution file`},
+		{`missing language model command`, []string{`lm`}, rcParameterError, `Language model command is missing`},
+		{`unknown language model command`, []string{`lm`, `score`}, rcParameterError, `Unknown language model command: 'score'`},
+		{`missing corpus name`, []string{`lm`, `build`, `-out`, missingFileName}, rcParameterError, `Name of corpus is missing`},
+		{`missing model file name`, []string{`lm`, `build`, `-in`, clearFileName}, rcParameterError, `Name of language model file is missing`},
+		{`invalid minimum count`, []string{`lm`, `build`, `-in`, clearFileName, `-out`, missingFileName, `-min`, `0`}, rcParameterError, `Invalid minimum number of occurrences`},
+		{`missing corpus`, []string{`lm`, `build`, `-in`, missingFileName, `-out`, existingFileName, `-force`}, rcProcessingError, `Error reading corpus`},
+		{`empty corpus`, []string{`lm`, `b`, `-in`, noLettersFileName, `-out`, missingFileName}, rcProcessingError, `corpus contains no quadgrams`},
+		{`serve arguments without flags`, []string{`serve`, `extra`}, rcParameterError, `Arguments without flags present`},
+		{`invalid request size`, []string{`serve`, `-limit`, `0`}, rcParameterError, `Invalid maximum request size`},
+		{`missing exercise file name`, []string{`ex`}, rcParameterError, `Name of clear text file is missing`},
+		{`invalid reveal count`, []string{`exercise`, `-in`, clearFileName, `-reveal`, `-1`}, rcParameterError, `Invalid number of revealed letters`},
+		{`invalid output format`, []string{`exercise`, `-in`, clearFileName, `-format`, `pdf`}, rcParameterError, `Invalid output format`},
+		{`missing grade key file name`, []string{`grade`, `-cipher`, clearFileName, `-answer`, clearFileName}, rcParameterError, `Name of key file is missing`},
+		{`missing answer file name`, []string{`grade`, `-key`, clearFileName, `-cipher`, clearFileName}, rcParameterError, `Name of answer file is missing`},
+		{`invalid answer kind`, []string{`grade`, `-key`, clearFileName, `-cipher`, clearFileName, `-answer`, clearFileName, `-kind`, `essay`}, rcParameterError, `Invalid kind of answer`},
+		{`grade corrupt key file`, []string{`grade`, `-key`, corruptKeyFileName, `-cipher`, clearFileName, `-answer`, clearFileName}, rcProcessingError, `Error loading substitution file`},
+		{`missing crib file name`, []string{`crib`, `-crib`, `dear sir`}, rcParameterError, `Name of encrypted file is missing`},
+		{`crib without letters`, []string{`crib`, `-in`, clearFileName, `-crib`, `1 2 3`}, rcParameterError, `Crib is missing or contains no letters`},
+		{`invalid crib position`, []string{`crib`, `-in`, clearFileName, `-crib`, `dear sir`, `-pos`, `-1`}, rcParameterError, `Invalid position`},
+		{`impossible crib position`, []string{`crib`, `-in`, noLettersFileName, `-crib`, `dear sir`, `-pos`, `1`}, rcProcessingError, `Crib is not possible at position 1`},
+		{`existing partial key file`, []string{`crib`, `-in`, clearFileName, `-crib`, `dear sir`, `-key`, existingFileName}, rcParameterError, `already exists`},
+		{`repl arguments without flags`, []string{`repl`, `extra`}, rcParameterError, `Arguments without flags present`},
+		{`repl missing key file`, []string{`repl`, `-key`, missingFileName}, rcProcessingError, `Error loading substitution file`},
+		{`invalid listen address`, []string{`serve`, `-addr`, `127.0.0.1:no-port`}, rcProcessingError, `Error listening on`},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			rc := realMain(tc.args, &stdout, &stderr)
+			if rc != tc.expectedRc {
+				t.Fatalf("Return code is %d instead of %d. Error output:\n%s", rc, tc.expectedRc, stderr.String())
+			}
+
+			if !strings.Contains(stderr.String(), tc.inStderr) {
+				t.Errorf("Error output does not contain '%s':\n%s", tc.inStderr, stderr.String())
+			}
+		})
+	}
+}
+
+func TestAnalyze(t *testing.T) {
+	dir := t.TempDir()
+	encryptedFileName := writeTestFile(t, dir, `encrypted.txt`, "abAB ab\nxy")
+
+	stdout := expectReturnCode(t, []string{`analyze`, `-in`, encryptedFileName, `-top`, `2`}, rcOK)
+	for _, expected := range []string{
+		`Characters: 8, different: 6 of 52`,
+		`Bigrams: 7, different: 6 of 2704`,
+		`Bigram index of coincidence:`,
+		"   ab: 2 (28.571%)\n",
+		"   AB: 1 (14.286%)\n",
+	} {
+		if !strings.Contains(stdout, expected) {
+			t.Errorf("Output does not contain '%s':\n%s", expected, stdout)
+		}
+	}
+
+	if strings.Contains(stdout, `bA:`) {
+		t.Errorf("Output contains more than 2 bigrams:\n%s", stdout)
+	}
+}
+
+func TestAnalyzeGroups(t *testing.T) {
+	dir := t.TempDir()
+	clearFileName := writeTestFile(t, dir, `clear.txt`, strings.Repeat(testClearText, 10))
+	encryptedFileName := filepath.Join(dir, `clear_homophone.txt`)
+	keyFileName := filepath.Join(dir, `clear_txt.subst`)
+	expectReturnCode(t, []string{`encrypt`, `-in`, clearFileName}, rcOK)
+
+	stdout := expectReturnCode(t, []string{`analyze`, `-in`, encryptedFileName, `-groups`, `26`, `-key`, keyFileName}, rcOK)
+	for _, expected := range []string{
+		`Groups of probable homophones:`,
+		"   26: ",
+		`, key: `,
+		`Pairs in a group that are homophones: `,
+	} {
+		if !strings.Contains(stdout, expected) {
+			t.Errorf("Output does not contain '%s':\n%s", expected, stdout)
+		}
+	}
+
+	stdout = expectReturnCode(t, []string{`analyze`, `-in`, encryptedFileName, `-groups`, `3`}, rcOK)
+	if !strings.Contains(stdout, "    3: ") || strings.Contains(stdout, `key:`) || strings.Contains(stdout, `Pairs`) {
+		t.Errorf("Wrong groups without key:\n%s", stdout)
+	}
+}
+
+func TestProtectedOutput(t *testing.T) {
+	dir := t.TempDir()
+	clearFileName := writeTestFile(t, dir, `clear.txt`, testClearText)
+	encryptedFileName := filepath.Join(dir, `encrypted.txt`)
+	decryptedFileName := filepath.Join(dir, `decrypted.txt`)
+	keyFileName := filepath.Join(dir, `key.subst`)
+
+	expectReturnCode(t, []string{`encrypt`, `-in`, clearFileName, `-out`, encryptedFileName, `-key`, keyFileName, `-keep`, `-protect`}, rcOK)
+	expectReturnCode(t, []string{`decrypt`, `-in`, encryptedFileName, `-out`, decryptedFileName, `-key`, keyFileName, `-protect`}, rcOK)
+
+	decrypted := readTestFile(t, decryptedFileName)
+	if decrypted != expectedDecryption(testClearText, true) {
+		t.Fatalf("Decrypted text differs:\n%s", decrypted)
+	}
+
+	// A modified encrypted file must not be decrypted.
+	encrypted := []byte(readTestFile(t, encryptedFileName))
+	encrypted[10] ^= 1
+	err := os.WriteFile(encryptedFileName, encrypted, 0600)
+	if err != nil {
+		t.Fatalf(`Error writing encrypted file: %v`, err)
+	}
+
+	expectReturnCode(t, []string{`decrypt`, `-in`, encryptedFileName, `-out`, decryptedFileName, `-key`, keyFileName, `-protect`, `-force`}, rcProcessingError)
+	if readTestFile(t, decryptedFileName) != decrypted {
+		t.Fatal(`Decrypted file has been replaced`)
+	}
+}
+
+func TestEncryptModes(t *testing.T) {
+	dir := t.TempDir()
+	clearFileName := writeTestFile(t, dir, `clear.txt`, testClearText)
+
+	for _, tc := range []struct {
+		args      []string
+		encrypted string
+	}{
+		{[]string{`-mode`, `caesar`, `-shift`, `1`}, `UIF RVJDL CSPXO GPY`},
+		{[]string{`-mode`, `Caesar`}, `WKH TXLFN EURZQ IRA`},
+		{[]string{`-mode`, `mono`}, ``},
+	} {
+		encryptedFileName := filepath.Join(dir, `encrypted.txt`)
+		decryptedFileName := filepath.Join(dir, `decrypted.txt`)
+		keyFileName := filepath.Join(dir, `key.subst`)
+
+		args := append([]string{`encrypt`, `-in`, clearFileName, `-out`, encryptedFileName, `-key`, keyFileName, `-keep`, `-force`}, tc.args...)
+		stdout := expectReturnCode(t, args, rcOK)
+		if !strings.Contains(stdout, `of 52 ideal`) {
+			t.Errorf("%v: Output does not contain the flatness:\n%s", tc.args, stdout)
+		}
+
+		encrypted := readTestFile(t, encryptedFileName)
+		if !strings.HasPrefix(encrypted, tc.encrypted) {
+			t.Errorf(`%v: Encrypted text '%s' does not start with '%s'`, tc.args, encrypted, tc.encrypted)
+		}
+
+		expectReturnCode(t, []string{`decrypt`, `-in`, encryptedFileName, `-out`, decryptedFileName, `-key`, keyFileName, `-force`}, rcOK)
+		decrypted := readTestFile(t, decryptedFileName)
+		if decrypted != expectedDecryption(testClearText, true) {
+			t.Errorf("%v: Decrypted text differs:\n%s", tc.args, decrypted)
+		}
+	}
+
+	stdout := expectReturnCode(t, []string{`analyze`, `-in`, clearFileName, `-plain`, clearFileName}, rcOK)
+	for _, expected := range []string{`homophonic:`, `mono:`, `caesar:`, `min 0.000%`} {
+		if !strings.Contains(stdout, expected) {
+			t.Errorf("Output does not contain '%s':\n%s", expected, stdout)
+		}
+	}
+}
+
+func TestExercise(t *testing.T) {
+	dir := t.TempDir()
+	clearFileName := writeTestFile(t, dir, `clear.txt`, testClearText)
+	sheetFileName := filepath.Join(dir, `sheet.md`)
+	answerFileName := filepath.Join(dir, `answer.md`)
+	keyFileName := filepath.Join(dir, `sheet.subst`)
+
+	stdout := expectReturnCode(t, []string{`exercise`, `-in`, clearFileName, `-out`, sheetFileName, `-answer`, answerFileName, `-key`, keyFileName, `-reveal`, `3`, `-freq`}, rcOK)
+	if !strings.Contains(stdout, `(markdown, 3 of 26 letters revealed)`) {
+		t.Errorf("Output does not contain the number of revealed letters:\n%s", stdout)
+	}
+
+	sheet := readTestFile(t, sheetFileName)
+	if !strings.Contains(sheet, `## Partial key`) || !strings.Contains(sheet, `## Frequencies`) || strings.Contains(sheet, `Answer key`) {
+		t.Errorf("Wrong exercise sheet:\n%s", sheet)
+	}
+
+	answer := readTestFile(t, answerFileName)
+	if !strings.Contains(answer, `## Answer key`) || !strings.Contains(answer, `THEQU ICKBR`) {
+		t.Errorf("Wrong answer key:\n%s", answer)
+	}
+
+	// The saved key decrypts the ciphertext of the sheet.
+	ciphertext := strings.Split(sheet, "```\n")[1]
+	encryptedFileName := writeTestFile(t, dir, `encrypted.txt`, ciphertext)
+	expectReturnCode(t, []string{`decrypt`, `-in`, encryptedFileName, `-key`, keyFileName}, rcOK)
+	decrypted := readTestFile(t, filepath.Join(dir, `encrypted_decrypted.txt`))
+	if strings.ReplaceAll(strings.ReplaceAll(decrypted, ` `, ``), "\n", ``) != expectedDecryption(testClearText, false) {
+		t.Errorf("Decrypted sheet differs:\n%s", decrypted)
+	}
+
+	// The default sheet in HTML contains the answer key.
+	expectReturnCode(t, []string{`ex`, `-in`, clearFileName, `-format`, `HTML`}, rcOK)
+	html := readTestFile(t, filepath.Join(dir, `clear_exercise.html`))
+	if !strings.Contains(html, `<h2>Partial key</h2>`) || !strings.Contains(html, `<h2>Answer key</h2>`) {
+		t.Errorf("Wrong HTML sheet:\n%s", html)
+	}
+}
+
+func TestGrade(t *testing.T) {
+	dir := t.TempDir()
+	clearFileName := writeTestFile(t, dir, `clear.txt`, testClearText)
+	encryptedFileName := filepath.Join(dir, `clear_homophone.txt`)
+	keyFileName := filepath.Join(dir, `clear_txt.subst`)
+	expectReturnCode(t, []string{`encrypt`, `-in`, clearFileName, `-keep`}, rcOK)
+
+	stdout := expectReturnCode(t, []string{`grade`, `-key`, keyFileName, `-cipher`, encryptedFileName, `-answer`, clearFileName}, rcOK)
+	if !strings.Contains(stdout, `(plaintext)`) || !strings.Contains(stdout, `Score: 100.00% (67 of 67 characters correct)`) || !strings.Contains(stdout, `No differences`) {
+		t.Errorf("Wrong grading of correct plaintext:\n%s", stdout)
+	}
+
+	wrongFileName := writeTestFile(t, dir, `wrong.txt`, strings.Replace(testClearText, `dog`, `cat`, 1))
+	stdout = expectReturnCode(t, []string{`grade`, `-key`, keyFileName, `-cipher`, encryptedFileName, `-answer`, wrongFileName}, rcOK)
+	if !strings.Contains(stdout, `(64 of 67 characters correct)`) || !strings.Contains(stdout, `THELAZYCATPACK`) {
+		t.Errorf("Wrong grading of wrong plaintext:\n%s", stdout)
+	}
+
+	keyAnswerFileName := writeTestFile(t, dir, `key.json`, `{"A": "zz"}`)
+	stdout = expectReturnCode(t, []string{`grade`, `-key`, keyFileName, `-cipher`, encryptedFileName, `-answer`, keyAnswerFileName}, rcOK)
+	if !strings.Contains(stdout, `(key)`) || !strings.Contains(stdout, `Correct homophones per letter:`) {
+		t.Errorf("Wrong grading of key:\n%s", stdout)
+	}
+}
+
+func TestCrib(t *testing.T) {
+	dir := t.TempDir()
+	clearFileName := writeTestFile(t, dir, `clear.txt`, testClearText)
+	encryptedFileName := filepath.Join(dir, `clear_homophone.txt`)
+	partialKeyFileName := filepath.Join(dir, `partial.subst`)
+	expectReturnCode(t, []string{`encrypt`, `-in`, clearFileName, `-keep`}, rcOK)
+
+	stdout := expectReturnCode(t, []string{`crib`, `-in`, encryptedFileName, `-crib`, `lazy dog`, `-max`, `1`}, rcOK)
+	if !strings.Contains(stdout, `Crib: LAZYDOG`) || !strings.Contains(stdout, ` of 61`) || !strings.Contains(stdout, `more`) {
+		t.Errorf("Wrong crib output:\n%s", stdout)
+	}
+
+	// There is more than one possible position, so the partial key can only be exported with a position.
+	expectReturnCode(t, []string{`crib`, `-in`, encryptedFileName, `-crib`, `lazy dog`, `-key`, partialKeyFileName}, rcProcessingError)
+
+	stdout = expectReturnCode(t, []string{`crib`, `-in`, encryptedFileName, `-crib`, `lazy dog`, `-pos`, `29`, `-key`, partialKeyFileName}, rcOK)
+	if !strings.Contains(stdout, `Position 29: `) || !strings.Contains(stdout, `Partial key file:`) {
+		t.Errorf("Wrong crib output:\n%s", stdout)
+	}
+
+	decryptedFileName := filepath.Join(dir, `partial.txt`)
+	expectReturnCode(t, []string{`decrypt`, `-in`, encryptedFileName, `-out`, decryptedFileName, `-key`, partialKeyFileName}, rcOK)
+	decrypted := readTestFile(t, decryptedFileName)
+	if !strings.Contains(decrypted, ` LAZY DOG.`) {
+		t.Errorf("Partial key does not decrypt the crib:\n%s", decrypted)
+	}
+}
+
+func TestBuildLanguageModel(t *testing.T) {
+	dir := t.TempDir()
+	corpusDir := filepath.Join(dir, `corpus`)
+	err := os.MkdirAll(filepath.Join(corpusDir, `sub`), 0700)
+	if err != nil {
+		t.Fatalf(`Error creating corpus directory: %v`, err)
+	}
+
+	writeTestFile(t, dir, filepath.Join(`corpus`, `a.txt`), testClearText)
+	writeTestFile(t, dir, filepath.Join(`corpus`, `sub`, `b.txt`), `Sphinx of black quartz, judge my vow.`)
+	modelFileName := filepath.Join(dir, `test.lm`)
+
+	stdout := expectReturnCode(t, []string{`lm`, `build`, `-in`, corpusDir, `-out`, modelFileName}, rcOK)
+	for _, expected := range []string{
+		`(2 files, 96 letters)`,
+		`Quadgrams: 90, different: `,
+		`Language model file: `,
+	} {
+		if !strings.Contains(stdout, expected) {
+			t.Errorf("Output does not contain '%s':\n%s", expected, stdout)
+		}
+	}
+
+	var model *langmodel.Model
+	model, err = langmodel.NewFromFile(modelFileName)
+	if err != nil {
+		t.Fatalf(`Error loading language model: %v`, err)
+	}
+
+	if model.Score([]byte(`the lazy dog`)) <= model.Score([]byte(`xqz jvw kfp`)) {
+		t.Error(`Language model does not prefer text from its corpus`)
+	}
+}
+
+func TestRepl(t *testing.T) {
+	setInput(t, strings.Join([]string{
+		`Attack at dawn`,
+		`:keep`,
+		`Attack at dawn`,
+		`:table`,
+		`:freq`,
+		`Zebra`,
+		`:new`,
+		`Zebra`,
+		`:unknown`,
+		`:quit`,
+		`Not encrypted after quit`,
+	}, "\n"))
+
+	var stdout, stderr bytes.Buffer
+	rc := realMain([]string{`repl`}, &stdout, &stderr)
+	if rc != rcOK {
+		t.Fatalf("Return code is %d instead of %d. Error output:\n%s", rc, rcOK, stderr.String())
+	}
+
+	for _, expected := range []string{
+		"Decrypted:  ATTACKATDAWN\n",
+		"Keep mode is on\n",
+		"Decrypted:  ATTACK AT DAWN\n",
+		`Expected frequencies of the substitution characters`,
+		`Ciphertext characters: 24, different:`,
+		`Created a new key from`,
+		"Decrypted:  ZEBRA\n",
+	} {
+		if !strings.Contains(stdout.String(), expected) {
+			t.Errorf("Output does not contain '%s':\n%s", expected, stdout.String())
+		}
+	}
+
+	for _, expected := range []string{`no substitution for letter`, `Unknown command ':unknown'`} {
+		if !strings.Contains(stderr.String(), expected) {
+			t.Errorf("Error output does not contain '%s':\n%s", expected, stderr.String())
+		}
+	}
+
+	if strings.Contains(stdout.String(), `NOT ENCRYPTED`) {
+		t.Errorf("Line after quit has been encrypted:\n%s", stdout.String())
+	}
+}
+
+func TestReplWithKey(t *testing.T) {
+	dir := t.TempDir()
+	clearFileName := writeTestFile(t, dir, `clear.txt`, testClearText)
+	keyFileName := filepath.Join(dir, `clear_txt.subst`)
+	expectReturnCode(t, []string{`encrypt`, `-in`, clearFileName}, rcOK)
+
+	setInput(t, "Hello, World!\n")
+	stdout := expectReturnCode(t, []string{`repl`, `-key`, keyFileName, `-keep`}, rcOK)
+	if !strings.Contains(stdout, "Decrypted:  HELLO, WORLD!\n") {
+		t.Errorf("Output does not contain the decrypted line:\n%s", stdout)
+	}
+}
+
+func TestReplWipesClearText(t *testing.T) {
+	var stdout bytes.Buffer
+	outWriter = &stdout
+	t.Cleanup(func() { outWriter = os.Stdout })
+
+	session := &replSession{}
+	session.addLine(`Attack at dawn`)
+	beforeGrowth := session.clearText
+	session.addLine(`Pack my box with five dozen liquor jugs`)
+	afterGrowth := session.clearText
+
+	session.newKey()
+	if session.substitutor == nil {
+		t.Fatal(`No key has been created`)
+	}
+	defer session.close()
+
+	for _, data := range [][]byte{beforeGrowth, afterGrowth[:cap(afterGrowth)]} {
+		if slices.ContainsFunc(data, func(b byte) bool { return b != 0 }) {
+			t.Fatalf(`Clear text has not been wiped: %q`, data)
+		}
+	}
+
+	if len(session.clearText) != 0 {
+		t.Fatalf(`Clear text is not empty: %q`, session.clearText)
+	}
+}
+
+func TestForceOverwrite(t *testing.T) {
+	dir := t.TempDir()
+	clearFileName := writeTestFile(t, dir, `clear.txt`, testClearText)
+
+	expectReturnCode(t, []string{`encrypt`, `-in`, clearFileName}, rcOK)
+	expectReturnCode(t, []string{`encrypt`, `-in`, clearFileName}, rcParameterError)
+	expectReturnCode(t, []string{`encrypt`, `-in`, clearFileName, `-force`}, rcOK)
+}
+
+func TestReadableKeyFileWarning(t *testing.T) {
+	if !oshelper.HasFilePermissions {
+		t.Skip(`File permissions are not supported`)
+	}
+
+	dir := t.TempDir()
+	clearFileName := writeTestFile(t, dir, `clear.txt`, testClearText)
+	encryptedFileName := filepath.Join(dir, `encrypted.txt`)
+	decryptedFileName := filepath.Join(dir, `decrypted.txt`)
+	keyFileName := filepath.Join(dir, `key.subst`)
+
+	expectReturnCode(t, []string{`encrypt`, `-in`, clearFileName, `-out`, encryptedFileName, `-key`, keyFileName}, rcOK)
+
+	decryptArgs := []string{`decrypt`, `-in`, encryptedFileName, `-out`, decryptedFileName, `-key`, keyFileName, `-force`}
+	for _, perm := range []os.FileMode{0600, 0640, 0604} {
+		err := os.Chmod(keyFileName, perm)
+		if err != nil {
+			t.Fatalf(`Error changing permissions of key file: %v`, err)
+		}
+
+		var stdout, stderr bytes.Buffer
+		rc := realMain(decryptArgs, &stdout, &stderr)
+		if rc != rcOK {
+			t.Fatalf("Return code is %d instead of %d. Error output:\n%s", rc, rcOK, stderr.String())
+		}
+
+		expectWarning := perm != 0600
+		if strings.Contains(stderr.String(), `readable by group or others`) != expectWarning {
+			t.Errorf("Key file with permissions %04o: expected warning %t, error output:\n%s", perm, expectWarning, stderr.String())
+		}
+	}
+}
+
+func TestHelpAndVersion(t *testing.T) {
+	tests := []struct {
+		command  string
+		inStdout string
+		inStderr string
+	}{
+		{`help`, ``, `The usage is:`},
+		{`H`, ``, `The following commands are available:`},
+		{`version`, myVersion, ``},
+		{`v`, myCopyright, ``},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.command, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			rc := realMain([]string{tc.command}, &stdout, &stderr)
+			if rc != rcOK {
+				t.Fatalf(`Return code is %d instead of %d`, rc, rcOK)
+			}
+
+			if !strings.Contains(stdout.String(), tc.inStdout) {
+				t.Errorf("Output does not contain '%s':\n%s", tc.inStdout, stdout.String())
+			}
+
+			if !strings.Contains(stderr.String(), tc.inStderr) {
+				t.Errorf("Error output does not contain '%s':\n%s", tc.inStderr, stderr.String())
+			}
+		})
+	}
+}
+
+func TestDefaultFileNames(t *testing.T) {
+	tests := []struct {
+		in           string
+		encryptedOut string
+		decryptedOut string
+		subst        string
+	}{
+		{`text.txt`, `text_homophone.txt`, `text_decrypted.txt`, `text_txt.subst`},
+		{`text_homophone.txt`, `text_homophone.txt`, `text_decrypted.txt`, `text_txt.subst`},
+		{`text_decrypted.txt`, `text_homophone.txt`, `text_decrypted.txt`, `text_txt.subst`},
+		{`text`, `text_homophone`, `text_decrypted`, `text.subst`},
+		{filepath.Join(`dir`, `a.b.c`), filepath.Join(`dir`, `a.b_homophone.c`), filepath.Join(`dir`, `a.b_decrypted.c`), filepath.Join(`dir`, `a.b_c.subst`)},
+	}
+
+	for _, tc := range tests {
+		expectFileName(t, `encrypted`, tc.in, buildEncryptOutFilePath(tc.in), tc.encryptedOut)
+		expectFileName(t, `decrypted`, tc.in, buildDecryptOutFilePath(tc.in), tc.decryptedOut)
+		expectFileName(t, `substitution`, tc.in, buildSubstFilePath(tc.in), tc.subst)
+	}
+}
+
+func TestParseLengthSpecification(t *testing.T) {
+	all := func(v uint, changes map[byte]uint) []uint {
+		result := make([]uint, letterCount)
+		for i := range result {
+			result[i] = v
+		}
+		for c, cv := range changes {
+			result[c-'A'] = cv
+		}
+		return result
+	}
+
+	tests := []struct {
+		spec     string
+		expected []uint
+		isError  bool
+	}{
+		{``, nil, false},
+		{`2`, all(2, nil), false},
+		{`1,E=4,q=0`, all(1, map[byte]uint{'E': 4, 'Q': 0}), false},
+		{`E=4, 1`, all(1, map[byte]uint{'E': 4}), false},
+		{`Z=3`, all(7, map[byte]uint{'Z': 3}), false},
+		{`x`, nil, true},
+		{`EE=1`, nil, true},
+		{`1=1`, nil, true},
+		{`E=`, nil, true},
+		{`1,,2`, nil, true},
+		{`70000`, nil, true},
+	}
+
+	for _, tc := range tests {
+		got, err := parseLengthSpecification(tc.spec, 7)
+		if tc.isError {
+			if err == nil {
+				t.Errorf(`'%s': Expected an error, got %v`, tc.spec, got)
+			}
+			continue
+		}
+
+		if err != nil {
+			t.Errorf(`'%s': Unexpected error: %v`, tc.spec, err)
+			continue
+		}
+
+		if !slices.Equal(got, tc.expected) {
+			t.Errorf(`'%s': Expected %v, got %v`, tc.spec, tc.expected, got)
+		}
+	}
+}
+
+// ******** Private functions ********
+
+// expectReturnCode runs realMain with args, checks the return code and returns the normal output.
+func expectReturnCode(t *testing.T, args []string, expectedRc int) string {
+	t.Helper()
+
+	var stdout, stderr bytes.Buffer
+	rc := realMain(args, &stdout, &stderr)
+	if rc != expectedRc {
+		t.Fatalf("%s: Return code is %d instead of %d. Error output:\n%s", args[0], rc, expectedRc, stderr.String())
+	}
+
+	return stdout.String()
+}
+
+// expectedDecryption returns the expected result of the decryption of an encrypted text.
+func expectedDecryption(clearText string, keepOthers bool) string {
+	var result strings.Builder
+	for _, b := range []byte(strings.ToUpper(clearText)) {
+		if keepOthers || (b >= 'A' && b <= 'Z') {
+			result.WriteByte(b)
+		}
+	}
+
+	return result.String()
+}
+
+// expectFileName checks a generated file name.
+func expectFileName(t *testing.T, kind string, in string, got string, expected string) {
+	t.Helper()
+
+	if got != expected {
+		t.Errorf(`Name of %s file for '%s' is '%s' instead of '%s'`, kind, in, got, expected)
+	}
+}
+
+// setInput sets the input of an interactive session for a test.
+func setInput(t *testing.T, input string) {
+	t.Helper()
+
+	inReader = strings.NewReader(input)
+	t.Cleanup(func() { inReader = os.Stdin })
+}
+
+// writeTestFile writes a test file and returns its path.
+func writeTestFile(t *testing.T, dir string, name string, content string) string {
+	t.Helper()
+
+	filePath := filepath.Join(dir, name)
+	err := os.WriteFile(filePath, []byte(content), 0600)
+	if err != nil {
+		t.Fatalf(`Error writing file '%s': %v`, filePath, err)
+	}
+
+	return filePath
+}
+
+// readTestFile reads a test file.
+func readTestFile(t *testing.T, filePath string) string {
+	t.Helper()
+
+	content, err := os.ReadFile(filePath)
+	if err != nil {
+		t.Fatalf(`Error reading file '%s': %v`, filePath, err)
+	}
+
+	return string(content)
+}
@@ -0,0 +1,96 @@
+//
+// SPDX-FileCopyrightText: Copyright 2026 Frank Schwab
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileType: SOURCE
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+//
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Author: Frank Schwab
+//
+// Version: 1.0.0
+//
+// Change history:
+//    2026-10-18: V1.0.0: Created.
+//
+
+// Package nametable implements a table of the names of the values of an enumeration.
+package nametable
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ******** Public types ********
+
+// Entry is a value and its name.
+type Entry[T comparable] struct {
+	Value T
+	Name  string
+}
+
+// Table contains the values of an enumeration and their names in the order in which they are listed.
+type Table[T comparable] []Entry[T]
+
+// ******** Public functions ********
+
+// FromValues creates a table from values that know their name.
+func FromValues[T comparable](values []T, name func(T) string) Table[T] {
+	result := make(Table[T], len(values))
+	for i, v := range values {
+		result[i] = Entry[T]{Value: v, Name: name(v)}
+	}
+
+	return result
+}
+
+// ******** Public type functions ********
+
+// Parse returns the value with the given name. The comparison is case-insensitive.
+// If there is no value with this name, the returned error wraps errUnknown.
+func (t Table[T]) Parse(name string, errUnknown error) (T, error) {
+	for _, e := range t {
+		if strings.EqualFold(e.Name, name) {
+			return e.Value, nil
+		}
+	}
+
+	var result T
+	return result, fmt.Errorf(`'%s': %w`, name, errUnknown)
+}
+
+// Names returns the names of all values in the order of the table.
+func (t Table[T]) Names() []string {
+	result := make([]string, len(t))
+	for i, e := range t {
+		result[i] = e.Name
+	}
+
+	return result
+}
+
+// Name returns the name of a value. A value that is not in the table is named "unknown(value)".
+// It can be used in the String method of the value type, as the number of the value is
+// formatted without calling that method.
+func (t Table[T]) Name(value T) string {
+	for _, e := range t {
+		if e.Value == value {
+			return e.Name
+		}
+	}
+
+	return fmt.Sprintf(`unknown(%d)`, any(value))
+}
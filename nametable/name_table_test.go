@@ -0,0 +1,104 @@
+//
+// SPDX-FileCopyrightText: Copyright 2026 Frank Schwab
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileType: SOURCE
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+//
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Author: Frank Schwab
+//
+// Version: 1.0.0
+//
+// Change history:
+//    2026-10-18: V1.0.0: Created.
+//
+
+package nametable_test
+
+import (
+	"errors"
+	"homophone/nametable"
+	"slices"
+	"testing"
+)
+
+// ******** Private types ********
+
+// color is an enumeration whose String method uses the name table.
+type color byte
+
+// ******** Private constants ********
+
+const (
+	red color = iota + 1
+	green
+)
+
+// ******** Private variables ********
+
+var errUnknownColor = errors.New(`unknown color`)
+
+var colorNames = nametable.Table[color]{
+	{Value: red, Name: `red`},
+	{Value: green, Name: `green`},
+}
+
+// ******** Test functions ********
+
+func TestParse(t *testing.T) {
+	c, err := colorNames.Parse(`GrEeN`, errUnknownColor)
+	if err != nil || c != green {
+		t.Fatalf(`Expected '%s', got '%s' with error %v`, green, c, err)
+	}
+
+	_, err = colorNames.Parse(`blue`, errUnknownColor)
+	if !errors.Is(err, errUnknownColor) {
+		t.Fatalf(`Expected error %v, got %v`, errUnknownColor, err)
+	}
+}
+
+func TestNames(t *testing.T) {
+	names := colorNames.Names()
+	if !slices.Equal(names, []string{`red`, `green`}) {
+		t.Fatalf(`Wrong names: %v`, names)
+	}
+
+	// The result must be a copy.
+	names[0] = `blue`
+	if red.String() != `red` {
+		t.Fatal(`Changing the names changed the table`)
+	}
+}
+
+func TestName(t *testing.T) {
+	if red.String() != `red` || color(7).String() != `unknown(7)` {
+		t.Fatalf(`Wrong names '%s' and '%s'`, red, color(7))
+	}
+}
+
+func TestFromValues(t *testing.T) {
+	table := nametable.FromValues([]color{green, red}, color.String)
+	if !slices.Equal(table.Names(), []string{`green`, `red`}) {
+		t.Fatalf(`Wrong names: %v`, table.Names())
+	}
+}
+
+// ******** Private type functions ********
+
+// String returns the name of the color.
+func (c color) String() string {
+	return colorNames.Name(c)
+}
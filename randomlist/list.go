@@ -20,45 +20,53 @@
 //
 // Author: Frank Schwab
 //
-// Version: 1.0.0
+// Version: 2.1.0
 //
 // Change history:
 //    2025-01-02: V1.0.0: Created.
+//    2026-10-18: V2.0.0: Add selection strategies.
+//    2026-10-18: V2.1.0: Add probabilities and Clear.
 //
 
 // Package randomlist implements a list of elements that is accessed in a random sequence.
 package randomlist
 
 import (
+	"homophone/slicehelper"
 	"math/rand/v2"
+	"slices"
 )
 
 // ******** Public types ********
 
-// RandomList implements a list of elements that are accessed in a random order.
+// RandomList implements a list of elements that are accessed in an order
+// that is determined by a selection [Strategy].
 type RandomList[T any] struct {
 	baseSlice []T
 	length    int
+	strategy  Strategy
 	index     []int
 	actIndex  int
+	recent    []int
 }
 
 // ******** Public generation function ********
 
-// New creates a new random list.
+// New creates a new random list with the [ShuffledCycle] strategy.
 func New[T any](s []T) *RandomList[T] {
-	sliceLen := len(s)
-	index := make([]int, sliceLen)
-	if sliceLen > 1 {
-		newRandomIndexList(index, sliceLen)
-	}
+	return NewWithStrategy(s, ShuffledCycle)
+}
 
-	return &RandomList[T]{
+// NewWithStrategy creates a new random list with the given selection strategy.
+func NewWithStrategy[T any](s []T, strategy Strategy) *RandomList[T] {
+	result := &RandomList[T]{
 		baseSlice: s,
-		length:    sliceLen,
-		index:     index,
-		actIndex:  0,
+		length:    len(s),
 	}
+
+	result.SetStrategy(strategy)
+
+	return result
 }
 
 // ******** Public functions ********
@@ -73,7 +81,63 @@ func (r *RandomList[T]) BaseList() []T {
 	return r.baseSlice
 }
 
-// RandomElement returns a random element from the list.
+// Probabilities returns the long-run probability with which each element is selected.
+// All strategies select each element equally often.
+func (r *RandomList[T]) Probabilities() []float64 {
+	result := make([]float64, r.length)
+	for i := range result {
+		result[i] = 1 / float64(r.length)
+	}
+
+	return result
+}
+
+// Clear wipes the elements and the index slices of the random list.
+// The random list must not be used after it has been cleared.
+func (r *RandomList[T]) Clear() {
+	var zero T
+	slicehelper.FillToCap(r.baseSlice, zero)
+	slicehelper.ClearNumber(r.index)
+	slicehelper.ClearNumber(r.recent)
+
+	r.baseSlice = nil
+	r.index = nil
+	r.recent = nil
+	r.length = 0
+	r.actIndex = 0
+}
+
+// Strategy returns the selection strategy of the random list.
+func (r *RandomList[T]) Strategy() Strategy {
+	return r.strategy
+}
+
+// SetStrategy sets the selection strategy of the random list and starts the selection anew.
+// An unknown strategy is treated as [ShuffledCycle].
+func (r *RandomList[T]) SetStrategy(strategy Strategy) {
+	if int(strategy) >= len(strategyNames) {
+		strategy = ShuffledCycle
+	}
+
+	r.strategy = strategy
+	r.actIndex = 0
+	r.index = nil
+	r.recent = nil
+
+	sliceLen := r.length
+	switch strategy {
+	case ShuffledCycle:
+		r.index = make([]int, sliceLen)
+		if sliceLen > 1 {
+			newRandomIndexList(r.index, sliceLen)
+		}
+
+	case Window:
+		r.recent = make([]int, 0, sliceLen>>1)
+	}
+}
+
+// RandomElement returns the next element from the list according to the selection strategy.
 func (r *RandomList[T]) RandomElement() T {
 	sliceLen := r.length
 	baseSlice := r.baseSlice
@@ -84,12 +148,62 @@ func (r *RandomList[T]) RandomElement() T {
 	}
 
 	var resultIndex int
-	resultIndex, r.actIndex = incIndex(r.index, r.actIndex, sliceLen)
+	switch r.strategy {
+	case Uniform:
+		resultIndex = rand.IntN(sliceLen)
+
+	case SequentialCycle:
+		resultIndex = r.actIndex
+		r.actIndex++
+		if r.actIndex >= sliceLen {
+			r.actIndex = 0
+		}
+
+	case Window:
+		resultIndex = r.nextWindowIndex()
+
+	default:
+		resultIndex, r.actIndex = incIndex(r.index, r.actIndex, sliceLen)
+	}
 
 	// Return the random element.
 	return baseSlice[resultIndex]
 }
 
+// ******** Private type functions ********
+
+// nextWindowIndex returns a random index that is not among the recently returned indices
+// and remembers it as recently returned.
+func (r *RandomList[T]) nextWindowIndex() int {
+	recent := r.recent
+
+	// Choose the n-th of the indices that have not been returned recently.
+	n := rand.IntN(r.length - len(recent))
+	result := 0
+	for ; ; result++ {
+		if !slices.Contains(recent, result) {
+			if n == 0 {
+				break
+			}
+			n--
+		}
+	}
+
+	// The recent indices are a ring buffer.
+	windowSize := cap(recent)
+	if len(recent) < windowSize {
+		r.recent = append(recent, result)
+	} else {
+		recent[r.actIndex] = result
+		r.actIndex++
+		if r.actIndex >= windowSize {
+			r.actIndex = 0
+		}
+	}
+
+	return result
+}
+
 // ******** Private functions ********
 
 // incIndex returns the current random index and increments the index into the index slice.
@@ -20,10 +20,11 @@
 //
 // Author: Frank Schwab
 //
-// Version: 1.0.0
+// Version: 1.1.0
 //
 // Change history:
 //    2025-01-02: V1.0.0: Created.
+//    2026-10-18: V1.1.0: Test Clear.
 //
 
 package randomlist
@@ -65,6 +66,25 @@ func TestInt(t *testing.T) {
 	}
 }
 
+func TestClear(t *testing.T) {
+	r := NewWithStrategy([]int{1, 2, 3, 4, 5}, Window)
+	r.RandomElement()
+
+	baseSlice := r.baseSlice
+	index := r.index
+	recent := r.recent
+
+	r.Clear()
+
+	expectZero(t, baseSlice, `elements`)
+	expectZero(t, index, `index`)
+	expectZero(t, recent, `recent`)
+
+	if r.Len() != 0 || r.baseSlice != nil || r.index != nil || r.recent != nil {
+		t.Fatal(`Cleared random list still references its data`)
+	}
+}
+
 func TestFloat64(t *testing.T) {
 	for testLen := 1; testLen <= 100; testLen++ {
 		testSlice := make([]float64, testLen)
@@ -84,3 +104,17 @@ func TestFloat64(t *testing.T) {
 		}
 	}
 }
+
+// ******** Private functions ********
+
+// expectZero checks that all elements of a slice up to its capacity are zero.
+func expectZero[T comparable](t *testing.T, data []T, name string) {
+	t.Helper()
+
+	var zero T
+	for _, v := range data[:cap(data)] {
+		if v != zero {
+			t.Fatalf(`Data of '%s' have not been wiped: %v`, name, data[:cap(data)])
+		}
+	}
+}
@@ -0,0 +1,50 @@
+//
+// SPDX-FileCopyrightText: Copyright 2026 Frank Schwab
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileType: SOURCE
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+//
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Author: Frank Schwab
+//
+// Version: 1.0.0
+//
+// Change history:
+//    2026-10-18: V1.0.0: Created.
+//
+
+package randomlist
+
+// ******** Public types ********
+
+// Selector is a list of elements from which elements are selected one after the other.
+type Selector[T any] interface {
+	// Len returns the number of elements in the list.
+	Len() int
+
+	// BaseList returns the elements of the list.
+	BaseList() []T
+
+	// RandomElement returns the next selected element.
+	RandomElement() T
+
+	// Probabilities returns the long-run probability with which each element is selected.
+	Probabilities() []float64
+
+	// Clear wipes the elements and all selection data from memory.
+	// The selector must not be used after it has been cleared.
+	Clear()
+}
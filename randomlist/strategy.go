@@ -0,0 +1,93 @@
+//
+// SPDX-FileCopyrightText: Copyright 2026 Frank Schwab
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileType: SOURCE
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+//
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Author: Frank Schwab
+//
+// Version: 1.0.0
+//
+// Change history:
+//    2026-10-18: V1.0.0: Created.
+//
+
+package randomlist
+
+import (
+	"errors"
+	"homophone/nametable"
+)
+
+// ******** Public types ********
+
+// Strategy is the way a [RandomList] selects its elements.
+type Strategy byte
+
+// ******** Public constants ********
+
+// Known selection strategies.
+const (
+	// ShuffledCycle returns the elements in a random order. All elements are returned
+	// before a new random order is used.
+	ShuffledCycle Strategy = iota
+
+	// Uniform returns each element with the same probability, independent of the elements returned before.
+	Uniform
+
+	// SequentialCycle returns the elements in the order of the list, as it was done historically.
+	SequentialCycle
+
+	// Window returns a random element that has not been returned among the last elements.
+	// The size of the window is half the length of the list.
+	Window
+)
+
+// DefaultStrategy is the default selection strategy.
+const DefaultStrategy = ShuffledCycle
+
+// ErrUnknownStrategy is returned when a selection strategy is not known.
+var ErrUnknownStrategy = errors.New(`unknown selection strategy`)
+
+// ******** Private variables ********
+
+// strategyNames contains the names of the strategies in the order of their values.
+var strategyNames = nametable.Table[Strategy]{
+	{Value: ShuffledCycle, Name: `shuffled`},
+	{Value: Uniform, Name: `uniform`},
+	{Value: SequentialCycle, Name: `sequential`},
+	{Value: Window, Name: `window`},
+}
+
+// ******** Public functions ********
+
+// ParseStrategy returns the selection strategy with the given name.
+func ParseStrategy(name string) (Strategy, error) {
+	return strategyNames.Parse(name, ErrUnknownStrategy)
+}
+
+// StrategyNames returns the names of all known selection strategies.
+func StrategyNames() []string {
+	return strategyNames.Names()
+}
+
+// ******** Public type functions ********
+
+// String returns the name of the selection strategy.
+func (s Strategy) String() string {
+	return strategyNames.Name(s)
+}
@@ -0,0 +1,145 @@
+//
+// SPDX-FileCopyrightText: Copyright 2026 Frank Schwab
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileType: SOURCE
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+//
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Author: Frank Schwab
+//
+// Version: 1.0.0
+//
+// Change history:
+//    2026-10-18: V1.0.0: Created.
+//
+
+package randomlist
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+// ******** Test functions ********
+
+func TestParseStrategy(t *testing.T) {
+	for i, name := range StrategyNames() {
+		s, err := ParseStrategy(name)
+		if err != nil {
+			t.Fatalf(`Error parsing strategy '%s': %v`, name, err)
+		}
+
+		if s != Strategy(i) || s.String() != name {
+			t.Fatalf(`Strategy '%s' was parsed as %d (%s)`, name, s, s)
+		}
+	}
+
+	s, err := ParseStrategy(`SeQuEnTiAl`)
+	if err != nil || s != SequentialCycle {
+		t.Fatalf(`Strategy names are not case-insensitive: %d, %v`, s, err)
+	}
+
+	_, err = ParseStrategy(`dice`)
+	if !errors.Is(err, ErrUnknownStrategy) {
+		t.Fatalf(`Unknown strategy resulted in error %v instead of %v`, err, ErrUnknownStrategy)
+	}
+}
+
+func TestStrategiesReturnAllElements(t *testing.T) {
+	for _, strategy := range []Strategy{ShuffledCycle, Uniform, SequentialCycle, Window} {
+		for testLen := 1; testLen <= 20; testLen++ {
+			list := NewWithStrategy(indexSlice(testLen), strategy)
+			if list.Strategy() != strategy {
+				t.Fatalf(`List has strategy %s instead of %s`, list.Strategy(), strategy)
+			}
+
+			counts := make([]int, testLen)
+			for range 100 * testLen {
+				counts[list.RandomElement()]++
+			}
+
+			if slices.Contains(counts, 0) {
+				t.Fatalf(`Strategy %s did not return all elements of a list with length %d: %v`, strategy, testLen, counts)
+			}
+		}
+	}
+}
+
+func TestShuffledCycleIsPermutation(t *testing.T) {
+	const testLen = 17
+	list := New(indexSlice(testLen))
+	for range 10 {
+		cycle := make([]int, testLen)
+		for i := range cycle {
+			cycle[i] = list.RandomElement()
+		}
+
+		slices.Sort(cycle)
+		if !slices.Equal(cycle, indexSlice(testLen)) {
+			t.Fatalf(`Cycle is not a permutation: %v`, cycle)
+		}
+	}
+}
+
+func TestSequentialCycle(t *testing.T) {
+	const testLen = 7
+	list := NewWithStrategy(indexSlice(testLen), SequentialCycle)
+	for i := range 3 * testLen {
+		n := list.RandomElement()
+		if n != i%testLen {
+			t.Fatalf(`Element %d is %d instead of %d`, i, n, i%testLen)
+		}
+	}
+}
+
+func TestWindow(t *testing.T) {
+	for testLen := 2; testLen <= 20; testLen++ {
+		windowSize := testLen >> 1
+		list := NewWithStrategy(indexSlice(testLen), Window)
+		var previous []int
+		for range 50 * testLen {
+			n := list.RandomElement()
+			if slices.Contains(previous, n) {
+				t.Fatalf(`Element %d is repeated within the last %d elements %v`, n, windowSize, previous)
+			}
+
+			previous = append(previous, n)
+			if len(previous) > windowSize {
+				previous = previous[1:]
+			}
+		}
+	}
+}
+
+func TestUnknownStrategy(t *testing.T) {
+	list := NewWithStrategy(indexSlice(5), Strategy(200))
+	if list.Strategy() != ShuffledCycle {
+		t.Fatalf(`Unknown strategy was set as %s instead of %s`, list.Strategy(), ShuffledCycle)
+	}
+}
+
+// ******** Private functions ********
+
+// indexSlice returns a slice that contains the numbers from 0 to n-1.
+func indexSlice(n int) []int {
+	result := make([]int, n)
+	for i := range result {
+		result[i] = i
+	}
+
+	return result
+}
@@ -0,0 +1,190 @@
+//
+// SPDX-FileCopyrightText: Copyright 2026 Frank Schwab
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileType: SOURCE
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+//
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Author: Frank Schwab
+//
+// Version: 1.0.0
+//
+// Change history:
+//    2026-10-18: V1.0.0: Created.
+//
+
+package randomlist
+
+import (
+	"errors"
+	"homophone/slicehelper"
+	"math"
+	"math/rand/v2"
+)
+
+// ******** Public types ********
+
+// WeightedList implements a list of elements that are selected randomly with given weights.
+// It uses the alias method, so each selection takes a constant time.
+type WeightedList[T any] struct {
+	baseSlice   []T
+	probability []float64
+	alias       []int
+	weightShare []float64
+}
+
+// ******** Public variables ********
+
+// ErrWeightCount is returned when the number of weights is not the number of elements.
+var ErrWeightCount = errors.New(`number of weights is not the number of elements`)
+
+// ErrInvalidWeight is returned when a weight is negative, infinite or not a number.
+var ErrInvalidWeight = errors.New(`invalid weight`)
+
+// ErrZeroWeights is returned when the weights of a non-empty list are all zero.
+var ErrZeroWeights = errors.New(`all weights are zero`)
+
+// ******** Public generation function ********
+
+// NewWeighted creates a new weighted list.
+// Each element is selected with a probability proportional to its weight.
+func NewWeighted[T any](s []T, weights []float64) (*WeightedList[T], error) {
+	n := len(s)
+	if len(weights) != n {
+		return nil, ErrWeightCount
+	}
+
+	sum := 0.0
+	for _, w := range weights {
+		if w < 0 || math.IsInf(w, 0) || math.IsNaN(w) {
+			return nil, ErrInvalidWeight
+		}
+
+		sum += w
+	}
+
+	if n > 0 && sum == 0 {
+		return nil, ErrZeroWeights
+	}
+
+	result := &WeightedList[T]{
+		baseSlice:   s,
+		probability: make([]float64, n),
+		alias:       make([]int, n),
+		weightShare: make([]float64, n),
+	}
+
+	for i, w := range weights {
+		result.weightShare[i] = w / sum
+	}
+
+	result.buildAliasTable()
+
+	return result, nil
+}
+
+// ******** Public functions ********
+
+// Len returns the length of the weighted list.
+func (w *WeightedList[T]) Len() int {
+	return len(w.baseSlice)
+}
+
+// BaseList returns the base list of the weighted list.
+func (w *WeightedList[T]) BaseList() []T {
+	return w.baseSlice
+}
+
+// Probabilities returns the probability with which each element is selected.
+func (w *WeightedList[T]) Probabilities() []float64 {
+	result := make([]float64, len(w.weightShare))
+	copy(result, w.weightShare)
+
+	return result
+}
+
+// Clear wipes the elements and the probability, alias and weight tables of the weighted list.
+// The weighted list must not be used after it has been cleared.
+func (w *WeightedList[T]) Clear() {
+	var zero T
+	slicehelper.FillToCap(w.baseSlice, zero)
+	slicehelper.ClearNumber(w.probability)
+	slicehelper.ClearNumber(w.alias)
+	slicehelper.ClearNumber(w.weightShare)
+
+	w.baseSlice = nil
+	w.probability = nil
+	w.alias = nil
+	w.weightShare = nil
+}
+
+// RandomElement returns a random element with the probability of its weight.
+// This will panic if the list is empty.
+func (w *WeightedList[T]) RandomElement() T {
+	i := rand.IntN(len(w.baseSlice))
+	if rand.Float64() < w.probability[i] {
+		return w.baseSlice[i]
+	}
+
+	return w.baseSlice[w.alias[i]]
+}
+
+// ******** Private type functions ********
+
+// buildAliasTable builds the probability and alias tables with Vose's algorithm.
+// Each column i is selected with the same probability. It returns element i with the
+// probability probability[i] and the element alias[i] otherwise.
+func (w *WeightedList[T]) buildAliasTable() {
+	n := len(w.weightShare)
+	scaled := make([]float64, n)
+	small := make([]int, 0, n)
+	large := make([]int, 0, n)
+	for i, share := range w.weightShare {
+		scaled[i] = share * float64(n)
+		if scaled[i] < 1 {
+			small = append(small, i)
+		} else {
+			large = append(large, i)
+		}
+	}
+
+	for len(small) > 0 && len(large) > 0 {
+		s := small[len(small)-1]
+		small = small[:len(small)-1]
+		l := large[len(large)-1]
+
+		w.probability[s] = scaled[s]
+		w.alias[s] = l
+
+		// The large element fills the rest of the column of the small element.
+		scaled[l] -= 1 - scaled[s]
+		if scaled[l] < 1 {
+			large = large[:len(large)-1]
+			small = append(small, l)
+		}
+	}
+
+	// The remaining columns are full. Rounding errors may leave some in the small list.
+	for _, i := range large {
+		w.probability[i] = 1
+		w.alias[i] = i
+	}
+
+	for _, i := range small {
+		w.probability[i] = 1
+		w.alias[i] = i
+	}
+}
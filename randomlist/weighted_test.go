@@ -0,0 +1,162 @@
+//
+// SPDX-FileCopyrightText: Copyright 2026 Frank Schwab
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileType: SOURCE
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+//
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Author: Frank Schwab
+//
+// Version: 1.0.0
+//
+// Change history:
+//    2026-10-18: V1.0.0: Created.
+//
+
+package randomlist
+
+import (
+	"errors"
+	"math"
+	"math/rand/v2"
+	"testing"
+)
+
+// ******** Test functions ********
+
+func TestWeightedErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		weights  []float64
+		expected error
+	}{
+		{`too few weights`, []float64{1, 2}, ErrWeightCount},
+		{`negative weight`, []float64{1, -1, 2}, ErrInvalidWeight},
+		{`infinite weight`, []float64{1, math.Inf(1), 2}, ErrInvalidWeight},
+		{`weight is not a number`, []float64{1, math.NaN(), 2}, ErrInvalidWeight},
+		{`zero weights`, []float64{0, 0, 0}, ErrZeroWeights},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := NewWeighted([]int{1, 2, 3}, tc.weights)
+			if !errors.Is(err, tc.expected) {
+				t.Fatalf(`Got error %v instead of %v`, err, tc.expected)
+			}
+		})
+	}
+}
+
+func TestWeightedZeroLength(t *testing.T) {
+	w, err := NewWeighted[byte](nil, nil)
+	if err != nil {
+		t.Fatalf(`Error creating empty weighted list: %v`, err)
+	}
+
+	if w.Len() != 0 {
+		t.Fatal(`Zero elements slice does not have zero length`)
+	}
+}
+
+func TestWeightedProbabilities(t *testing.T) {
+	w, err := NewWeighted([]int{0, 1, 2, 3}, []float64{1, 0, 2, 5})
+	if err != nil {
+		t.Fatalf(`Error creating weighted list: %v`, err)
+	}
+
+	expected := []float64{0.125, 0, 0.25, 0.625}
+	for i, p := range w.Probabilities() {
+		if math.Abs(p-expected[i]) > 1e-12 {
+			t.Fatalf(`Probability of element %d is %g instead of %g`, i, p, expected[i])
+		}
+	}
+}
+
+func TestWeightedClear(t *testing.T) {
+	w, err := NewWeighted([]int{1, 2, 3, 4}, []float64{1, 3, 2, 5})
+	if err != nil {
+		t.Fatalf(`Error creating weighted list: %v`, err)
+	}
+
+	baseSlice := w.baseSlice
+	probability := w.probability
+	alias := w.alias
+	weightShare := w.weightShare
+
+	w.Clear()
+
+	expectZero(t, baseSlice, `elements`)
+	expectZero(t, probability, `probability table`)
+	expectZero(t, alias, `alias table`)
+	expectZero(t, weightShare, `weight shares`)
+
+	if w.baseSlice != nil || w.probability != nil || w.alias != nil || w.weightShare != nil {
+		t.Fatal(`Cleared weighted list still references its data`)
+	}
+}
+
+// TestWeightedDistribution checks that the alias tables select the elements with the probability of their weights.
+func TestWeightedDistribution(t *testing.T) {
+	rng := rand.New(rand.NewPCG(0x616c696173, 0x766f7365))
+	const drawCount = 200_000
+
+	for range 20 {
+		n := 1 + rng.IntN(12)
+		weights := make([]float64, n)
+		for i := range weights {
+			if rng.IntN(4) != 0 {
+				weights[i] = rng.Float64() * 10
+			}
+		}
+		weights[rng.IntN(n)] = 1
+
+		w, err := NewWeighted(indexSlice(n), weights)
+		if err != nil {
+			t.Fatalf(`Error creating weighted list with weights %v: %v`, weights, err)
+		}
+
+		// The probability of each element is the sum of its columns in the alias table.
+		tableProbabilities := make([]float64, n)
+		for i := range n {
+			tableProbabilities[i] += w.probability[i] / float64(n)
+			tableProbabilities[w.alias[i]] += (1 - w.probability[i]) / float64(n)
+		}
+
+		probabilities := w.Probabilities()
+		for i, p := range probabilities {
+			if math.Abs(tableProbabilities[i]-p) > 1e-12 {
+				t.Fatalf(`Weights %v: Alias table probability of element %d is %g instead of %g`, weights, i, tableProbabilities[i], p)
+			}
+		}
+
+		counts := make([]int, n)
+		for range drawCount {
+			counts[w.RandomElement()]++
+		}
+
+		for i, p := range probabilities {
+			if p == 0 && counts[i] != 0 {
+				t.Fatalf(`Weights %v: Element %d has weight 0, but was selected %d times`, weights, i, counts[i])
+			}
+
+			// Allow 5 standard deviations.
+			limit := 5 * math.Sqrt(drawCount*p*(1-p))
+			if math.Abs(float64(counts[i])-drawCount*p) > limit+1 {
+				t.Fatalf(`Weights %v: Element %d was selected %d times instead of about %.0f`, weights, i, counts[i], drawCount*p)
+			}
+		}
+	}
+}
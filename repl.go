@@ -0,0 +1,291 @@
+//
+// SPDX-FileCopyrightText: Copyright 2026 Frank Schwab
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileType: SOURCE
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+//
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Author: Frank Schwab
+//
+// Version: 1.0.0
+//
+// Change history:
+//    2026-10-18: V1.0.0: Created.
+//
+
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"homophone/analysis"
+	"homophone/homosubst"
+	"homophone/slicehelper"
+	"io"
+	"strings"
+)
+
+// ******** Private types ********
+
+// replSession contains the state of an interactive session.
+type replSession struct {
+	// substitutor is the substitutor of the session. It is nil until a key is loaded or created.
+	substitutor *homosubst.Substitutor
+	// keepOthers indicates that characters that are not in the range A-Z are kept.
+	keepOthers bool
+	// clearText contains the lines that have been entered since the last key has been created.
+	// A new key is created from them. It is wiped when the key has been created.
+	clearText []byte
+	// statistics contains the frequencies of the ciphertext characters since the key has been loaded or created.
+	statistics *analysis.Statistics
+}
+
+// ******** Private constants ********
+
+// replPrompt is the prompt for an input line.
+const replPrompt = `> `
+
+// replCommandPrefix starts a command in an input line.
+const replCommandPrefix = `:`
+
+// replCountsPerLine is the number of character counts in one output line.
+const replCountsPerLine = 13
+
+// ******** Private functions ********
+
+// doRepl runs an interactive session. Each input line is encrypted and decrypted immediately.
+// If substitutionFileName is empty, the key is created from the first line.
+func doRepl(substitutionFileName string, keepOthers bool) int {
+	session := &replSession{
+		keepOthers: keepOthers,
+		statistics: analysis.NewStatistics(analysis.SubstitutionAlphabet),
+	}
+	defer session.close()
+
+	if len(substitutionFileName) != 0 {
+		warnIfReadableByOthers(substitutionFileName)
+
+		substitutor, err := homosubst.NewFromFile(substitutionFileName)
+		if err != nil {
+			return printErrorf(`Error loading substitution file: %v`, err)
+		}
+
+		session.substitutor = substitutor
+		_, _ = fmt.Fprintf(outWriter, "Loaded substitution file: '%s'\n", substitutionFileName)
+	} else {
+		_, _ = fmt.Fprintln(outWriter, `No key file. The key is created from the first line.`)
+	}
+
+	_, _ = fmt.Fprintf(outWriter, "Type a line to encrypt it or '%shelp' for the commands.\n", replCommandPrefix)
+
+	scanner := bufio.NewScanner(inReader)
+	for {
+		_, _ = fmt.Fprint(outWriter, replPrompt)
+		if !scanner.Scan() {
+			break
+		}
+
+		line := scanner.Text()
+		command, isCommand := strings.CutPrefix(strings.TrimSpace(line), replCommandPrefix)
+		if isCommand {
+			if !session.execute(strings.ToLower(command)) {
+				return rcOK
+			}
+		} else if len(strings.TrimSpace(line)) != 0 {
+			session.encryptLine(line)
+		}
+	}
+
+	_, _ = fmt.Fprintln(outWriter)
+
+	err := scanner.Err()
+	if err != nil {
+		return printErrorf(`Error reading input: %v`, err)
+	}
+
+	return rcOK
+}
+
+// ******** Private type functions ********
+
+// execute executes a command. It returns false, if the session is to be ended.
+func (r *replSession) execute(command string) bool {
+	switch command {
+	case `help`, `h`, `?`:
+		printReplHelp()
+
+	case `new`, `n`:
+		r.newKey()
+
+	case `table`, `t`:
+		if r.hasKey() {
+			r.substitutor.Fprint(outWriter)
+			printFlatness(r.substitutor)
+		}
+
+	case `keep`, `k`:
+		r.keepOthers = !r.keepOthers
+		_, _ = fmt.Fprintf(outWriter, "Keep mode is %s\n", onOff(r.keepOthers))
+
+	case `freq`, `f`:
+		r.printCounts()
+
+	case `quit`, `q`, `exit`:
+		return false
+
+	default:
+		replErrorf(`Unknown command '%s%s'. Type '%shelp' for the commands`, replCommandPrefix, command, replCommandPrefix)
+	}
+
+	return true
+}
+
+// encryptLine encrypts a line, prints the ciphertext, decrypts the ciphertext and prints the decrypted text.
+func (r *replSession) encryptLine(line string) {
+	r.addLine(line)
+
+	if r.substitutor == nil {
+		r.newKey()
+		if r.substitutor == nil {
+			return
+		}
+	}
+
+	var ciphertext bytes.Buffer
+	err := r.substitutor.EncryptStream(strings.NewReader(line), io.MultiWriter(&ciphertext, r.statistics), r.keepOthers)
+	if err != nil {
+		if errors.Is(err, homosubst.ErrNoSubstitution) {
+			replErrorf(`The key can not encrypt the line: %v. Type '%snew' to create a key from the lines since the last key`, err, replCommandPrefix)
+		} else {
+			replErrorf(`Error encrypting the line: %v`, err)
+		}
+
+		return
+	}
+
+	var decrypted strings.Builder
+	err = r.substitutor.DecryptStream(bytes.NewReader(ciphertext.Bytes()), &decrypted)
+	if err != nil {
+		replErrorf(`Error decrypting the line: %v`, err)
+		return
+	}
+
+	_, _ = fmt.Fprintf(outWriter, "Ciphertext: %s\n", ciphertext.String())
+	_, _ = fmt.Fprintf(outWriter, "Decrypted:  %s\n", decrypted.String())
+}
+
+// addLine appends a line to the clear text. If the clear text has to grow, the old copy is wiped.
+func (r *replSession) addLine(line string) {
+	needed := len(r.clearText) + len(line) + 1
+	if needed > cap(r.clearText) {
+		grown := make([]byte, len(r.clearText), 2*needed)
+		copy(grown, r.clearText)
+		slicehelper.ClearNumber(r.clearText)
+		r.clearText = grown
+	}
+
+	r.clearText = append(r.clearText, line...)
+	r.clearText = append(r.clearText, '\n')
+}
+
+// newKey creates a new key from the lines that have been entered since the last key and resets the frequency counts.
+// The lines are wiped afterwards.
+func (r *replSession) newKey() {
+	substitutor, err := homosubst.NewSubstitutorFromReader(bytes.NewReader(r.clearText), homosubst.Options{})
+	if err != nil {
+		replErrorf(`Error creating key from the lines entered since the last key: %v`, err)
+		return
+	}
+
+	characterCount := len(r.clearText)
+
+	r.close()
+	r.substitutor = substitutor
+	r.statistics = analysis.NewStatistics(analysis.SubstitutionAlphabet)
+
+	_, _ = fmt.Fprintf(outWriter, "Created a new key from %d characters\n", characterCount)
+}
+
+// hasKey checks whether there is a key and prints an error message, if there is none.
+func (r *replSession) hasKey() bool {
+	if r.substitutor == nil {
+		replErrorf(`There is no key, yet. Type a line to create one`)
+		return false
+	}
+
+	return true
+}
+
+// printCounts prints the frequencies of the ciphertext characters since the key has been loaded or created.
+func (r *replSession) printCounts() {
+	alphabet := r.statistics.Alphabet()
+
+	_, _ = fmt.Fprintf(outWriter, "Ciphertext characters: %d, different: %d of %d\n", r.statistics.Total(), r.statistics.DistinctCharacters(), len(alphabet))
+	for i := 0; i < len(alphabet); i++ {
+		_, _ = fmt.Fprintf(outWriter, "%c:%4d", alphabet[i], r.statistics.Count(alphabet[i]))
+		if (i+1)%replCountsPerLine == 0 || i == len(alphabet)-1 {
+			_, _ = fmt.Fprintln(outWriter)
+		} else {
+			_, _ = fmt.Fprint(outWriter, `  `)
+		}
+	}
+
+	if r.statistics.Total() != 0 {
+		_, _ = fmt.Fprintf(outWriter, "Deviation from the ideal frequency: %.3f%%\n", r.statistics.Flatness().Deviation*100)
+	}
+}
+
+// close closes the substitutor of the session, if there is one, and wipes the clear text.
+func (r *replSession) close() {
+	if r.substitutor != nil {
+		closeSubstitutor(r.substitutor)
+		r.substitutor = nil
+	}
+
+	slicehelper.ClearNumber(r.clearText)
+	r.clearText = r.clearText[:0]
+}
+
+// ******** Private functions ********
+
+// printReplHelp prints the commands of an interactive session.
+func printReplHelp() {
+	_, _ = fmt.Fprintln(outWriter, `Each line that is not a command is encrypted and decrypted. The commands are:`)
+	_, _ = fmt.Fprintf(outWriter, "   %shelp:  Show this information\n", replCommandPrefix)
+	_, _ = fmt.Fprintf(outWriter, "   %snew:   Create a new key from the lines entered since the last key\n", replCommandPrefix)
+	_, _ = fmt.Fprintf(outWriter, "   %stable: Show the substitution table\n", replCommandPrefix)
+	_, _ = fmt.Fprintf(outWriter, "   %skeep:  Toggle keeping of characters that are not in the range A-Z\n", replCommandPrefix)
+	_, _ = fmt.Fprintf(outWriter, "   %sfreq:  Show the frequencies of the ciphertext characters since the key has been loaded or created\n", replCommandPrefix)
+	_, _ = fmt.Fprintf(outWriter, "   %squit:  End the session\n", replCommandPrefix)
+	_, _ = fmt.Fprintln(outWriter, `Each command can be abbreviated to its first letter`)
+}
+
+// replErrorf prints an error message of an interactive session.
+func replErrorf(format string, a ...any) {
+	_, _ = fmt.Fprintf(errWriter, format, a...)
+	_, _ = fmt.Fprintln(errWriter)
+}
+
+// onOff returns "on" for true and "off" for false.
+func onOff(b bool) string {
+	if b {
+		return `on`
+	}
+
+	return `off`
+}
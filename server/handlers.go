@@ -0,0 +1,381 @@
+//
+// SPDX-FileCopyrightText: Copyright 2026 Frank Schwab
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileType: SOURCE
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+//
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Author: Frank Schwab
+//
+// Version: 1.2.0
+//
+// Change history:
+//    2026-10-18: V1.0.0: Created.
+//    2026-10-18: V1.1.0: Reject texts with letters that the key cannot substitute.
+//    2026-10-18: V1.2.0: Add mode and shift.
+//
+
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"homophone/analysis"
+	"homophone/distributor"
+	"homophone/homosubst"
+	"homophone/integritycheckedfile"
+	"homophone/randomlist"
+	"net/http"
+	"strings"
+)
+
+// ******** Private types ********
+
+// handlerFunc handles a parsed request and returns the response that is sent as JSON.
+type handlerFunc func(req *request) (any, error)
+
+// keyResponse is the response of the "/keygen" endpoint.
+type keyResponse struct {
+	Key      string             `json:"key"`
+	Flatness *analysis.Flatness `json:"flatness,omitempty"`
+}
+
+// encryptResponse is the response of the "/encrypt" endpoint.
+type encryptResponse struct {
+	Ciphertext string `json:"ciphertext"`
+	Key        string `json:"key"`
+}
+
+// decryptResponse is the response of the "/decrypt" endpoint.
+type decryptResponse struct {
+	Text string `json:"text"`
+}
+
+// bigramResponse is a bigram and its count in the response of the "/analyze" endpoint.
+type bigramResponse struct {
+	Bigram string `json:"bigram"`
+	Count  uint   `json:"count"`
+}
+
+// analyzeResponse is the response of the "/analyze" endpoint.
+type analyzeResponse struct {
+	Characters               uint              `json:"characters"`
+	DistinctCharacters       int               `json:"distinctCharacters"`
+	Flatness                 analysis.Flatness `json:"flatness"`
+	IndexOfCoincidence       float64           `json:"indexOfCoincidence"`
+	Bigrams                  uint              `json:"bigrams"`
+	DistinctBigrams          int               `json:"distinctBigrams"`
+	BigramIndexOfCoincidence float64           `json:"bigramIndexOfCoincidence"`
+	TopBigrams               []bigramResponse  `json:"topBigrams"`
+}
+
+// errorResponse is the response for an error.
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+// ******** Private constants ********
+
+// defaultTopCount is the default number of most frequent bigrams in the analysis.
+const defaultTopCount = 10
+
+// defaultShift is the default shift of the Caesar mode.
+const defaultShift = 3
+
+// ******** Private functions ********
+
+// endpoint creates the HTTP handler for an endpoint.
+// It accepts only POST requests with a body of limited size and writes the response as JSON.
+func endpoint(config Config, handle handlerFunc) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.Header().Set(`Allow`, http.MethodPost)
+			writeError(w, http.StatusMethodNotAllowed, errors.New(`only POST is allowed`))
+			return
+		}
+
+		r.Body = http.MaxBytesReader(w, r.Body, config.MaxRequestSize)
+
+		req, err := parseRequest(r, config.MaxRequestSize)
+		if err == nil {
+			var response any
+			response, err = handle(req)
+			if err == nil {
+				writeJSON(w, http.StatusOK, response)
+				return
+			}
+		}
+
+		var reqErr *requestError
+		if errors.As(err, &reqErr) {
+			writeError(w, reqErr.status, reqErr.err)
+		} else {
+			writeError(w, http.StatusInternalServerError, err)
+		}
+	})
+}
+
+// handleKeygen creates a key from the frequencies of a text.
+func handleKeygen(req *request) (any, error) {
+	s, err := newSubstitutor(req)
+	if err != nil {
+		return nil, err
+	}
+	defer closeSubstitutor(s)
+
+	var key []byte
+	key, err = s.MarshalText()
+	if err != nil {
+		return nil, err
+	}
+
+	result := keyResponse{Key: string(key)}
+
+	flatness, err := s.Flatness()
+	if err == nil {
+		result.Flatness = &flatness
+	}
+
+	return result, nil
+}
+
+// handleEncrypt encrypts a text with the given key or with a new key for the text.
+func handleEncrypt(req *request) (any, error) {
+	if len(req.Text) == 0 {
+		return nil, badRequest(`text is missing`)
+	}
+
+	var s *homosubst.Substitutor
+	var err error
+	if req.hasKey() {
+		// The selection of the substitutions is stored in the key, so it can not be changed.
+		if hasKeyOptions(req) {
+			return nil, badRequest(`the options of a key can not be used with a given key`)
+		}
+
+		s, err = loadSubstitutor(req)
+	} else {
+		s, err = newSubstitutor(req)
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer closeSubstitutor(s)
+
+	var ciphertext bytes.Buffer
+	err = s.EncryptStream(strings.NewReader(req.Text), &ciphertext, req.Keep)
+	if err != nil {
+		if errors.Is(err, homosubst.ErrNoSubstitution) {
+			return nil, badRequest(`text does not fit the key: %v`, err)
+		}
+
+		return nil, err
+	}
+
+	var key []byte
+	key, err = s.MarshalText()
+	if err != nil {
+		return nil, err
+	}
+
+	return encryptResponse{Ciphertext: ciphertext.String(), Key: string(key)}, nil
+}
+
+// handleDecrypt decrypts a ciphertext with the given key.
+func handleDecrypt(req *request) (any, error) {
+	if len(req.Ciphertext) == 0 {
+		return nil, badRequest(`ciphertext is missing`)
+	}
+
+	if !req.hasKey() {
+		return nil, badRequest(`key is missing`)
+	}
+
+	s, err := loadSubstitutor(req)
+	if err != nil {
+		return nil, err
+	}
+	defer closeSubstitutor(s)
+
+	var text bytes.Buffer
+	err = s.DecryptStream(strings.NewReader(req.Ciphertext), &text)
+	if err != nil {
+		return nil, err
+	}
+
+	return decryptResponse{Text: text.String()}, nil
+}
+
+// handleAnalyze returns the statistics of the characters and bigrams of a ciphertext.
+func handleAnalyze(req *request) (any, error) {
+	if len(req.Ciphertext) == 0 {
+		return nil, badRequest(`ciphertext is missing`)
+	}
+
+	topCount := defaultTopCount
+	if req.Top != nil {
+		topCount = *req.Top
+		if topCount < 0 {
+			return nil, badRequest(`invalid number of bigrams: %d`, topCount)
+		}
+	}
+
+	statistics, err := analysis.FromReader(strings.NewReader(req.Ciphertext))
+	if err != nil {
+		return nil, err
+	}
+
+	result := analyzeResponse{
+		Characters:               statistics.Total(),
+		DistinctCharacters:       statistics.DistinctCharacters(),
+		Flatness:                 statistics.Flatness(),
+		IndexOfCoincidence:       statistics.IndexOfCoincidence(),
+		Bigrams:                  statistics.BigramTotal(),
+		DistinctBigrams:          statistics.DistinctBigrams(),
+		BigramIndexOfCoincidence: statistics.BigramIndexOfCoincidence(),
+		TopBigrams:               []bigramResponse{},
+	}
+
+	for _, bc := range statistics.TopBigrams(topCount) {
+		result.TopBigrams = append(result.TopBigrams, bigramResponse{Bigram: string(bc.Bigram[:]), Count: bc.Count})
+	}
+
+	return result, nil
+}
+
+// newSubstitutor creates a new substitutor for the text of a request with the options of the request.
+func newSubstitutor(req *request) (*homosubst.Substitutor, error) {
+	if len(req.Text) == 0 {
+		return nil, badRequest(`text is missing`)
+	}
+
+	options, hashAlgorithm, err := substitutorOptions(req)
+	if err != nil {
+		return nil, err
+	}
+
+	var s *homosubst.Substitutor
+	s, err = homosubst.NewSubstitutorFromReader(strings.NewReader(req.Text), options)
+	if err != nil {
+		return nil, badRequest(`could not create key: %v`, err)
+	}
+
+	err = s.SetHashAlgorithm(hashAlgorithm)
+	if err != nil {
+		closeSubstitutor(s)
+		return nil, badRequest(`could not set hash algorithm: %v`, err)
+	}
+
+	return s, nil
+}
+
+// substitutorOptions returns the options and the hash algorithm for a new substitutor.
+func substitutorOptions(req *request) (homosubst.Options, integritycheckedfile.HashAlgorithm, error) {
+	var err error
+
+	options := homosubst.Options{
+		Weighted:          req.Weighted,
+		ContextClassCount: req.Bigram,
+	}
+
+	if len(req.Method) != 0 {
+		options.Apportioner, err = distributor.ParseMethod(req.Method)
+		if err != nil {
+			return options, 0, badRequest(`invalid apportionment method: %v`, err)
+		}
+	}
+
+	if len(req.Select) != 0 {
+		options.Selection, err = randomlist.ParseStrategy(req.Select)
+		if err != nil {
+			return options, 0, badRequest(`invalid selection strategy: %v`, err)
+		}
+	}
+
+	if req.Bigram < 0 || req.Bigram > homosubst.MaxContextClassCount {
+		return options, 0, badRequest(`invalid number of predecessor classes: %d`, req.Bigram)
+	}
+
+	if len(req.Mode) != 0 {
+		options.Mode, err = homosubst.ParseMode(req.Mode)
+		if err != nil {
+			return options, 0, badRequest(`invalid mode: %v`, err)
+		}
+	}
+
+	switch {
+	case options.Mode == homosubst.Caesar && req.Shift != nil:
+		options.Shift = *req.Shift
+
+	case options.Mode == homosubst.Caesar:
+		options.Shift = defaultShift
+
+	case req.Shift != nil:
+		return options, 0, badRequest(`a shift can only be used with mode '%s'`, homosubst.Caesar)
+	}
+
+	hashAlgorithm := integritycheckedfile.HashSHA3_256
+	if len(req.Hash) != 0 {
+		hashAlgorithm, err = integritycheckedfile.ParseHashAlgorithm(req.Hash)
+		if err != nil {
+			return options, 0, badRequest(`invalid hash algorithm: %v`, err)
+		}
+	}
+
+	return options, hashAlgorithm, nil
+}
+
+// hasKeyOptions returns true, if the request contains an option for the creation of a key.
+func hasKeyOptions(req *request) bool {
+	return len(req.Method) != 0 || len(req.Select) != 0 || req.Weighted || req.Bigram != 0 || len(req.Hash) != 0 ||
+		len(req.Mode) != 0 || req.Shift != nil
+}
+
+// loadSubstitutor loads a substitutor from the key of a request.
+// An uploaded key file contains the raw bytes, any other key the base64 encoded bytes of a key file.
+func loadSubstitutor(req *request) (*homosubst.Substitutor, error) {
+	var err error
+
+	result := &homosubst.Substitutor{}
+	if len(req.keyData) != 0 {
+		err = result.UnmarshalBinary(req.keyData)
+	} else {
+		err = result.UnmarshalText([]byte(strings.TrimSpace(req.Key)))
+	}
+	if err != nil {
+		return nil, badRequest(`invalid key: %v`, err)
+	}
+
+	return result, nil
+}
+
+// closeSubstitutor closes a substitutor and thereby wipes its data.
+func closeSubstitutor(s *homosubst.Substitutor) {
+	_ = s.Close()
+}
+
+// writeError writes an error response.
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, errorResponse{Error: err.Error()})
+}
+
+// writeJSON writes a JSON response.
+func writeJSON(w http.ResponseWriter, status int, response any) {
+	w.Header().Set(`Content-Type`, mediaTypeJSON)
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(response)
+}
@@ -0,0 +1,299 @@
+//
+// SPDX-FileCopyrightText: Copyright 2026 Frank Schwab
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileType: SOURCE
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+//
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Author: Frank Schwab
+//
+// Version: 1.1.0
+//
+// Change history:
+//    2026-10-18: V1.0.0: Created.
+//    2026-10-18: V1.1.0: Add mode and shift.
+//
+
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"strconv"
+)
+
+// ******** Private types ********
+
+// request contains the parameters of all endpoints.
+// Each endpoint uses only some of them.
+type request struct {
+	Text       string `json:"text"`
+	Ciphertext string `json:"ciphertext"`
+	Key        string `json:"key"`
+	Keep       bool   `json:"keep"`
+	Method     string `json:"method"`
+	Select     string `json:"select"`
+	Weighted   bool   `json:"weighted"`
+	Bigram     int    `json:"bigram"`
+	Hash       string `json:"hash"`
+	Top        *int   `json:"top"`
+	Mode       string `json:"mode"`
+	Shift      *int   `json:"shift"`
+
+	// keyData contains the raw bytes of a key file that has been uploaded in a multipart form.
+	// Key contains the base64 encoded bytes of a key file.
+	keyData []byte
+}
+
+// requestError is an error that is caused by the request and has an HTTP status code.
+type requestError struct {
+	status int
+	err    error
+}
+
+// ******** Private constants ********
+
+// Media types of requests.
+const (
+	mediaTypeJSON      = `application/json`
+	mediaTypeMultipart = `multipart/form-data`
+	mediaTypeForm      = `application/x-www-form-urlencoded`
+)
+
+// ******** Private type functions ********
+
+// hasKey returns true, if the request contains a key.
+func (req *request) hasKey() bool {
+	return len(req.Key) != 0 || len(req.keyData) != 0
+}
+
+// Error returns the error message.
+func (e *requestError) Error() string {
+	return e.err.Error()
+}
+
+// Unwrap returns the underlying error.
+func (e *requestError) Unwrap() error {
+	return e.err
+}
+
+// ******** Private functions ********
+
+// badRequest returns an error for an invalid request.
+func badRequest(format string, a ...any) error {
+	return &requestError{status: http.StatusBadRequest, err: fmt.Errorf(format, a...)}
+}
+
+// parseRequest parses a JSON, multipart or form request.
+// The body must already be limited in size.
+func parseRequest(r *http.Request, maxSize int64) (*request, error) {
+	mediaType, _, err := mime.ParseMediaType(r.Header.Get(`Content-Type`))
+	if err != nil {
+		return nil, &requestError{status: http.StatusUnsupportedMediaType, err: fmt.Errorf(`invalid content type: %w`, err)}
+	}
+
+	result := &request{}
+	switch mediaType {
+	case mediaTypeJSON:
+		decoder := json.NewDecoder(r.Body)
+		decoder.DisallowUnknownFields()
+		err = decoder.Decode(result)
+		if err != nil {
+			return nil, bodyError(`invalid JSON`, err)
+		}
+
+		// The body must contain only one JSON object.
+		err = decoder.Decode(&struct{}{})
+		if err == nil {
+			return nil, badRequest(`invalid JSON: data after the object`)
+		}
+		if !errors.Is(err, io.EOF) {
+			return nil, bodyError(`invalid JSON`, err)
+		}
+
+	case mediaTypeMultipart:
+		err = r.ParseMultipartForm(maxSize)
+		if err != nil {
+			return nil, bodyError(`invalid multipart form`, err)
+		}
+		defer func() { _ = r.MultipartForm.RemoveAll() }()
+
+		err = fillFromForm(result, r.MultipartForm)
+		if err != nil {
+			return nil, err
+		}
+
+	case mediaTypeForm:
+		err = r.ParseForm()
+		if err != nil {
+			return nil, bodyError(`invalid form`, err)
+		}
+
+		err = fillFromForm(result, &multipart.Form{Value: r.PostForm})
+		if err != nil {
+			return nil, err
+		}
+
+	default:
+		return nil, &requestError{status: http.StatusUnsupportedMediaType, err: fmt.Errorf(`unsupported content type '%s'`, mediaType)}
+	}
+
+	return result, nil
+}
+
+// bodyError returns the error for a body that could not be parsed.
+// A body that is too large results in the status "Request Entity Too Large".
+func bodyError(message string, err error) error {
+	var maxBytesError *http.MaxBytesError
+	if errors.As(err, &maxBytesError) {
+		return &requestError{status: http.StatusRequestEntityTooLarge, err: fmt.Errorf(`request is larger than %d bytes`, maxBytesError.Limit)}
+	}
+
+	return badRequest(`%s: %v`, message, err)
+}
+
+// fillFromForm fills the request from the values and files of a form.
+func fillFromForm(result *request, form *multipart.Form) error {
+	var err error
+
+	for _, field := range []struct {
+		name   string
+		target *string
+	}{
+		{`text`, &result.Text},
+		{`ciphertext`, &result.Ciphertext},
+		{`method`, &result.Method},
+		{`select`, &result.Select},
+		{`hash`, &result.Hash},
+		{`mode`, &result.Mode},
+	} {
+		*field.target, err = formString(form, field.name)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, field := range []struct {
+		name   string
+		target *bool
+	}{
+		{`keep`, &result.Keep},
+		{`weighted`, &result.Weighted},
+	} {
+		*field.target, err = formBool(form, field.name)
+		if err != nil {
+			return err
+		}
+	}
+
+	var bigram *int
+	bigram, err = formInt(form, `bigram`)
+	if err != nil {
+		return err
+	}
+	if bigram != nil {
+		result.Bigram = *bigram
+	}
+
+	result.Top, err = formInt(form, `top`)
+	if err != nil {
+		return err
+	}
+
+	result.Shift, err = formInt(form, `shift`)
+	if err != nil {
+		return err
+	}
+
+	// A key field contains the base64 encoded bytes of a key file, an uploaded key file contains the raw bytes.
+	if values := form.Value[`key`]; len(values) > 0 {
+		result.Key = values[0]
+	} else {
+		result.keyData, err = formFile(form, `key`)
+	}
+
+	return err
+}
+
+// formString returns the value of a form field or the contents of a file with the name.
+func formString(form *multipart.Form, name string) (string, error) {
+	if values := form.Value[name]; len(values) > 0 {
+		return values[0], nil
+	}
+
+	data, err := formFile(form, name)
+
+	return string(data), err
+}
+
+// formFile returns the contents of the file with the name. A missing file is nil.
+func formFile(form *multipart.Form, name string) ([]byte, error) {
+	files := form.File[name]
+	if len(files) == 0 {
+		return nil, nil
+	}
+
+	file, err := files[0].Open()
+	if err != nil {
+		return nil, badRequest(`could not open file '%s': %v`, name, err)
+	}
+	defer func() { _ = file.Close() }()
+
+	var data []byte
+	data, err = io.ReadAll(file)
+	if err != nil {
+		return nil, badRequest(`could not read file '%s': %v`, name, err)
+	}
+
+	return data, nil
+}
+
+// formBool returns the boolean value of a form field. A missing field is false.
+func formBool(form *multipart.Form, name string) (bool, error) {
+	value, err := formString(form, name)
+	if err != nil || len(value) == 0 {
+		return false, err
+	}
+
+	var result bool
+	result, err = strconv.ParseBool(value)
+	if err != nil {
+		return false, badRequest(`invalid value of '%s': '%s'`, name, value)
+	}
+
+	return result, nil
+}
+
+// formInt returns the integer value of a form field. A missing field is nil.
+func formInt(form *multipart.Form, name string) (*int, error) {
+	value, err := formString(form, name)
+	if err != nil || len(value) == 0 {
+		return nil, err
+	}
+
+	var result int
+	result, err = strconv.Atoi(value)
+	if err != nil {
+		return nil, badRequest(`invalid value of '%s': '%s'`, name, value)
+	}
+
+	return &result, nil
+}
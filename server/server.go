@@ -0,0 +1,131 @@
+//
+// SPDX-FileCopyrightText: Copyright 2026 Frank Schwab
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileType: SOURCE
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+//
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Author: Frank Schwab
+//
+// Version: 1.0.0
+//
+// Change history:
+//    2026-10-18: V1.0.0: Created.
+//
+
+// Package server implements an HTTP server for the homophonic substitution.
+package server
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"time"
+)
+
+// ******** Public types ********
+
+// Config contains the configuration of the server.
+// The zero value contains the default configuration.
+type Config struct {
+	// MaxRequestSize is the maximum size of a request body in bytes.
+	// If it is 0, [DefaultMaxRequestSize] is used.
+	MaxRequestSize int64
+}
+
+// ******** Public constants ********
+
+// DefaultMaxRequestSize is the default maximum size of a request body in bytes.
+const DefaultMaxRequestSize = 1 << 20
+
+// ******** Private constants ********
+
+// Timeouts of the server.
+const (
+	readHeaderTimeout = 10 * time.Second
+	readTimeout       = time.Minute
+	writeTimeout      = time.Minute
+	idleTimeout       = 2 * time.Minute
+	shutdownTimeout   = 10 * time.Second
+)
+
+// ******** Public functions ********
+
+// NewHandler creates the handler for the endpoints "/encrypt", "/decrypt", "/keygen" and "/analyze".
+func NewHandler(config Config) http.Handler {
+	if config.MaxRequestSize <= 0 {
+		config.MaxRequestSize = DefaultMaxRequestSize
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle(`/encrypt`, endpoint(config, handleEncrypt))
+	mux.Handle(`/decrypt`, endpoint(config, handleDecrypt))
+	mux.Handle(`/keygen`, endpoint(config, handleKeygen))
+	mux.Handle(`/analyze`, endpoint(config, handleAnalyze))
+
+	return mux
+}
+
+// ListenAndServe listens on the TCP address addr and serves requests until ctx is done.
+// Then the server is shut down gracefully, i.e. running requests are completed.
+func ListenAndServe(ctx context.Context, addr string, config Config) error {
+	listener, err := net.Listen(`tcp`, addr)
+	if err != nil {
+		return err
+	}
+
+	return Serve(ctx, listener, config)
+}
+
+// Serve serves requests on listener until ctx is done.
+// Then the server is shut down gracefully, i.e. running requests are completed.
+// The listener is closed when Serve returns.
+func Serve(ctx context.Context, listener net.Listener, config Config) error {
+	server := &http.Server{
+		Handler:           NewHandler(config),
+		ReadHeaderTimeout: readHeaderTimeout,
+		ReadTimeout:       readTimeout,
+		WriteTimeout:      writeTimeout,
+		IdleTimeout:       idleTimeout,
+	}
+
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- server.Serve(listener)
+	}()
+
+	select {
+	case err := <-serveErr:
+		return err
+
+	case <-ctx.Done():
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+
+		err := server.Shutdown(shutdownCtx)
+		if err != nil {
+			return err
+		}
+
+		err = <-serveErr
+		if errors.Is(err, http.ErrServerClosed) {
+			return nil
+		}
+
+		return err
+	}
+}
@@ -0,0 +1,325 @@
+//
+// SPDX-FileCopyrightText: Copyright 2026 Frank Schwab
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileType: SOURCE
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+//
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Author: Frank Schwab
+//
+// Version: 1.1.0
+//
+// Change history:
+//    2026-10-18: V1.0.0: Created.
+//    2026-10-18: V1.1.0: Test modes.
+//
+
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"mime/multipart"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+// ******** Private constants ********
+
+// testText is the clear text of the tests.
+const testText = "The quick brown fox jumps over the lazy dog.\nPack my box with five dozen liquor jugs!\n"
+
+// ******** Test functions ********
+
+func TestJSONRoundTrip(t *testing.T) {
+	handler := NewHandler(Config{})
+
+	for _, body := range []string{
+		`{"text": ` + jsonString(testText) + `, "keep": true}`,
+		`{"text": ` + jsonString(testText) + `, "keep": true, "method": "dhondt", "select": "window", "weighted": true, "hash": "blake2b"}`,
+		`{"text": ` + jsonString(testText) + `, "keep": true, "method": "dhondt", "bigram": 4, "hash": "blake2b"}`,
+		`{"text": ` + jsonString(testText) + `, "keep": true, "mode": "mono"}`,
+		`{"text": ` + jsonString(testText) + `, "keep": true, "mode": "caesar", "shift": 7}`,
+	} {
+		var encrypted encryptResponse
+		expectStatus(t, handler, `/encrypt`, mediaTypeJSON, body, http.StatusOK, &encrypted)
+
+		if len(encrypted.Key) == 0 || encrypted.Ciphertext == strings.ToUpper(testText) {
+			t.Fatalf(`Invalid encryption: %+v`, encrypted)
+		}
+
+		var decrypted decryptResponse
+		expectStatus(t, handler, `/decrypt`, mediaTypeJSON,
+			`{"ciphertext": `+jsonString(encrypted.Ciphertext)+`, "key": `+jsonString(encrypted.Key)+`}`,
+			http.StatusOK, &decrypted)
+
+		if decrypted.Text != strings.ToUpper(testText) {
+			t.Fatalf("Decrypted text differs:\nexpected: %q\ngot:      %q", strings.ToUpper(testText), decrypted.Text)
+		}
+	}
+}
+
+func TestKeygenAndEncryptWithKey(t *testing.T) {
+	handler := NewHandler(Config{})
+
+	var key keyResponse
+	expectStatus(t, handler, `/keygen`, mediaTypeJSON, `{"text": `+jsonString(testText)+`}`, http.StatusOK, &key)
+	if key.Flatness == nil || key.Flatness.Count != 52 {
+		t.Fatalf(`Invalid key response: %+v`, key)
+	}
+
+	form := url.Values{`text`: {`ATTACK AT DAWN`}, `key`: {key.Key}}
+	var encrypted encryptResponse
+	expectStatus(t, handler, `/encrypt`, mediaTypeForm, form.Encode(), http.StatusOK, &encrypted)
+	if encrypted.Key != key.Key {
+		t.Fatal(`Encryption with a given key returned a different key`)
+	}
+
+	if len(encrypted.Ciphertext) != len(`ATTACKATDAWN`) {
+		t.Fatalf(`Ciphertext '%s' has the wrong length`, encrypted.Ciphertext)
+	}
+
+	// The selection is stored in the key and can not be changed.
+	var response errorResponse
+	form.Set(`weighted`, `true`)
+	expectStatus(t, handler, `/encrypt`, mediaTypeForm, form.Encode(), http.StatusBadRequest, &response)
+
+	// A letter that does not occur in the text of the key cannot be encrypted.
+	expectStatus(t, handler, `/keygen`, mediaTypeJSON, `{"text": "abc"}`, http.StatusOK, &key)
+	expectStatus(t, handler, `/encrypt`, mediaTypeJSON, `{"text": "xyz", "key": `+jsonString(key.Key)+`}`, http.StatusBadRequest, &response)
+}
+
+func TestMultipart(t *testing.T) {
+	handler := NewHandler(Config{})
+
+	var body bytes.Buffer
+	w := multipart.NewWriter(&body)
+	part, _ := w.CreateFormFile(`text`, `clear.txt`)
+	_, _ = part.Write([]byte(testText))
+	_ = w.WriteField(`keep`, `true`)
+	_ = w.Close()
+
+	var encrypted encryptResponse
+	expectStatus(t, handler, `/encrypt`, w.FormDataContentType(), body.String(), http.StatusOK, &encrypted)
+
+	var analyzed analyzeResponse
+	expectStatus(t, handler, `/analyze`, mediaTypeJSON,
+		`{"ciphertext": `+jsonString(encrypted.Ciphertext)+`, "top": 3}`,
+		http.StatusOK, &analyzed)
+
+	if analyzed.Characters != uint(len(expectedLetters(testText))) || len(analyzed.TopBigrams) != 3 {
+		t.Fatalf(`Invalid analysis: %+v`, analyzed)
+	}
+}
+
+func TestMultipartKeyFile(t *testing.T) {
+	handler := NewHandler(Config{})
+
+	var key keyResponse
+	expectStatus(t, handler, `/keygen`, mediaTypeJSON, `{"text": `+jsonString(testText)+`}`, http.StatusOK, &key)
+
+	keyData, err := base64.StdEncoding.DecodeString(key.Key)
+	if err != nil {
+		t.Fatalf(`Key is not base64 encoded: %v`, err)
+	}
+
+	// An uploaded key file contains the raw bytes of the key file.
+	contentType, body := multipartWithKeyFile(map[string]string{`text`: `ATTACK AT DAWN`}, keyData)
+	var encrypted encryptResponse
+	expectStatus(t, handler, `/encrypt`, contentType, body, http.StatusOK, &encrypted)
+	if encrypted.Key != key.Key {
+		t.Fatal(`Encryption with an uploaded key file returned a different key`)
+	}
+
+	contentType, body = multipartWithKeyFile(map[string]string{`ciphertext`: encrypted.Ciphertext}, keyData)
+	var decrypted decryptResponse
+	expectStatus(t, handler, `/decrypt`, contentType, body, http.StatusOK, &decrypted)
+	if decrypted.Text != `ATTACKATDAWN` {
+		t.Fatalf(`Decrypted text is '%s' instead of 'ATTACKATDAWN'`, decrypted.Text)
+	}
+
+	// An uploaded key file is not base64 decoded.
+	contentType, body = multipartWithKeyFile(map[string]string{`ciphertext`: encrypted.Ciphertext}, []byte(key.Key))
+	var response errorResponse
+	expectStatus(t, handler, `/decrypt`, contentType, body, http.StatusBadRequest, &response)
+}
+
+func TestCaesarShift(t *testing.T) {
+	handler := NewHandler(Config{})
+
+	for _, tc := range []struct {
+		body     string
+		expected string
+	}{
+		{`{"text": "abc xyz", "keep": true, "mode": "caesar"}`, `DEF ABC`},
+		{`{"text": "abc xyz", "keep": true, "mode": "caesar", "shift": 1}`, `BCD YZA`},
+		{`{"text": "abc xyz", "keep": true, "mode": "Caesar", "shift": -1}`, `ZAB WXY`},
+	} {
+		var encrypted encryptResponse
+		expectStatus(t, handler, `/encrypt`, mediaTypeJSON, tc.body, http.StatusOK, &encrypted)
+		if encrypted.Ciphertext != tc.expected {
+			t.Fatalf(`'%s': Ciphertext is '%s' instead of '%s'`, tc.body, encrypted.Ciphertext, tc.expected)
+		}
+	}
+}
+
+func TestErrors(t *testing.T) {
+	handler := NewHandler(Config{MaxRequestSize: 100})
+
+	tests := []struct {
+		name        string
+		path        string
+		contentType string
+		body        string
+		status      int
+	}{
+		{`missing text`, `/encrypt`, mediaTypeJSON, `{}`, http.StatusBadRequest},
+		{`no letters`, `/keygen`, mediaTypeJSON, `{"text": "0123"}`, http.StatusBadRequest},
+		{`missing key`, `/decrypt`, mediaTypeJSON, `{"ciphertext": "abc"}`, http.StatusBadRequest},
+		{`invalid key`, `/decrypt`, mediaTypeJSON, `{"ciphertext": "abc", "key": "SEZERg=="}`, http.StatusBadRequest},
+		{`unknown field`, `/encrypt`, mediaTypeJSON, `{"txt": "abc"}`, http.StatusBadRequest},
+		{`invalid method`, `/keygen`, mediaTypeJSON, `{"text": "abc", "method": "unknown"}`, http.StatusBadRequest},
+		{`invalid bigram`, `/keygen`, mediaTypeJSON, `{"text": "abc", "bigram": 27}`, http.StatusBadRequest},
+		{`bigram with weighted`, `/keygen`, mediaTypeJSON, `{"text": "abc", "bigram": 2, "weighted": true}`, http.StatusBadRequest},
+		{`invalid top`, `/analyze`, mediaTypeJSON, `{"ciphertext": "abc", "top": -1}`, http.StatusBadRequest},
+		{`invalid mode`, `/keygen`, mediaTypeJSON, `{"text": "abc", "mode": "unknown"}`, http.StatusBadRequest},
+		{`mode with bigram`, `/keygen`, mediaTypeJSON, `{"text": "abc", "mode": "caesar", "bigram": 2}`, http.StatusBadRequest},
+		{`shift without caesar`, `/keygen`, mediaTypeJSON, `{"text": "abc", "shift": 2}`, http.StatusBadRequest},
+		{`mode with key`, `/encrypt`, mediaTypeForm, `text=abc&key=SEZERg%3D%3D&mode=caesar`, http.StatusBadRequest},
+		{`second JSON object`, `/keygen`, mediaTypeJSON, `{"text": "abc"} {"text": "def"}`, http.StatusBadRequest},
+		{`data after JSON`, `/keygen`, mediaTypeJSON, `{"text": "abc"} x`, http.StatusBadRequest},
+		{`invalid bool`, `/encrypt`, mediaTypeForm, `text=abc&keep=maybe`, http.StatusBadRequest},
+		{`too large`, `/keygen`, mediaTypeJSON, `{"text": "` + strings.Repeat(`a`, 200) + `"}`, http.StatusRequestEntityTooLarge},
+		{`unsupported type`, `/keygen`, `text/plain`, `abc`, http.StatusUnsupportedMediaType},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var response errorResponse
+			expectStatus(t, handler, tt.path, tt.contentType, tt.body, tt.status, &response)
+			if len(response.Error) == 0 {
+				t.Fatal(`Error response has no message`)
+			}
+		})
+	}
+}
+
+func TestMethodNotAllowed(t *testing.T) {
+	recorder := httptest.NewRecorder()
+	NewHandler(Config{}).ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, `/encrypt`, nil))
+
+	if recorder.Code != http.StatusMethodNotAllowed || recorder.Header().Get(`Allow`) != http.MethodPost {
+		t.Fatalf(`GET returned status %d with allowed methods '%s'`, recorder.Code, recorder.Header().Get(`Allow`))
+	}
+}
+
+func TestGracefulShutdown(t *testing.T) {
+	listener, err := net.Listen(`tcp`, `127.0.0.1:0`)
+	if err != nil {
+		t.Fatalf(`Could not listen: %v`, err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- Serve(ctx, listener, Config{})
+	}()
+
+	var response *http.Response
+	response, err = http.Post(`http://`+listener.Addr().String()+`/keygen`, mediaTypeJSON, strings.NewReader(`{"text": "abc"}`))
+	if err != nil {
+		t.Fatalf(`Request failed: %v`, err)
+	}
+	_ = response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		t.Fatalf(`Status is %d instead of %d`, response.StatusCode, http.StatusOK)
+	}
+
+	cancel()
+
+	select {
+	case err = <-done:
+		if err != nil {
+			t.Fatalf(`Serve returned error: %v`, err)
+		}
+
+	case <-time.After(shutdownTimeout):
+		t.Fatal(`Server did not shut down`)
+	}
+}
+
+// ******** Private functions ********
+
+// expectStatus sends a POST request to handler, checks the status and decodes the JSON response into response.
+func expectStatus(t *testing.T, handler http.Handler, path string, contentType string, body string, status int, response any) {
+	t.Helper()
+
+	r := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+	r.Header.Set(`Content-Type`, contentType)
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, r)
+
+	if recorder.Code != status {
+		t.Fatalf("%s: Status is %d instead of %d. Response:\n%s", path, recorder.Code, status, recorder.Body.String())
+	}
+
+	err := json.Unmarshal(recorder.Body.Bytes(), response)
+	if err != nil {
+		t.Fatalf(`%s: Invalid JSON response: %v`, path, err)
+	}
+}
+
+// multipartWithKeyFile returns the content type and the body of a multipart form with fields and an uploaded key file.
+func multipartWithKeyFile(fields map[string]string, keyData []byte) (string, string) {
+	var body bytes.Buffer
+	w := multipart.NewWriter(&body)
+	for name, value := range fields {
+		_ = w.WriteField(name, value)
+	}
+
+	part, _ := w.CreateFormFile(`key`, `key.subst`)
+	_, _ = part.Write(keyData)
+	_ = w.Close()
+
+	return w.FormDataContentType(), body.String()
+}
+
+// jsonString returns s as a JSON string.
+func jsonString(s string) string {
+	result, _ := json.Marshal(s)
+	return string(result)
+}
+
+// expectedLetters returns the letters of a text in upper case.
+func expectedLetters(text string) string {
+	var result strings.Builder
+	for _, b := range []byte(strings.ToUpper(text)) {
+		if b >= 'A' && b <= 'Z' {
+			result.WriteByte(b)
+		}
+	}
+
+	return result.String()
+}